@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"log"
-	"os" // Nécessaire pour lire les variables d'environnement (clés API)
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/Azertdev/FiberTest/config"
 	// Assurez-vous que le chemin vers vos adapters est correct
@@ -11,39 +15,69 @@ import (
 	"github.com/Azertdev/FiberTest/internal/repositories"
 	"github.com/Azertdev/FiberTest/internal/routes"
 	"github.com/Azertdev/FiberTest/internal/services"
-	"github.com/gofiber/helmet/v2"
 
 	// Assurez-vous que le chemin vers vos utils (pour TranscriptUtil) est correct
 	"github.com/Azertdev/FiberTest/internal/utils"
-
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"   // Exemple: ajout de CORS
-	"github.com/gofiber/fiber/v2/middleware/logger" // Exemple: ajout de Logger
 )
 
 func main() {
-	config.InitDB() // Ceci initialise la variable globale config.DB
-	if config.DB == nil {
-		log.Fatal("Échec de l'initialisation de la base de données (config.DB est nil)")
+	// Sous-commande : go run ./cmd migrate status|up|down
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
 	}
 
-	youtubeAPIKey := os.Getenv("YOUTUBE_API_KEY")
-	groqAPIKey := os.Getenv("GROQ_API_KEY")
+	// Configuration typée : environnement > fichier (CONFIG_FILE ou .env) > valeurs par défaut
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("ERREUR FATALE: Configuration invalide:\n%v", err)
+	}
 
-	if youtubeAPIKey == "" {
-		log.Fatal("ERREUR FATALE: Variable d'environnement YOUTUBE_API_KEY manquante.")
+	config.InitDB(cfg.Database) // Ceci initialise la variable globale config.DB
+	if config.DB == nil {
+		log.Fatal("Échec de l'initialisation de la base de données (config.DB est nil)")
 	}
-	if groqAPIKey == "" {
-		log.Fatal("ERREUR FATALE: Variable d'environnement GROQ_API_KEY manquante.")
+
+	jwtKeys, err := config.LoadJWTKeySet(cfg.JWT)
+	if err != nil {
+		log.Fatalf("ERREUR FATALE: Clés JWT invalides: %v", err)
 	}
 	log.Println("Configuration et clés API chargées.")
 
 	allRepositories := repositories.NewAllRepository(config.DB)
+	// Tentatives de connexion : en mémoire par défaut (instance unique), Postgres pour partager entre instances
+	if cfg.Security.LoginAttemptStore != "postgres" {
+		allRepositories.LoginAttemptRepository = repositories.NewMemoryLoginAttemptRepository()
+	}
 	log.Println("Repositories initialisés.")
 
-	youtubeAdapter := adapters.NewYouTubeAdapter(youtubeAPIKey)
-	groqAdapter := adapters.NewGroqAdapter(groqAPIKey)
+	youtubeAdapter := adapters.NewYouTubeAdapter(cfg.YouTube.APIKey, youtubeOptions(cfg.YouTube))
+	groqAdapter := adapters.NewGroqAdapter(cfg.Groq.APIKey, groqOptions(cfg.Groq))
 	transcriptUtil := utils.NewTranscriptUtil()
+
+	// Canaux de diffusion des notifications : toujours les logs, plus un webhook si configuré
+	notificationChannels := []services.NotificationChannel{adapters.NewLogNotifier()}
+	if cfg.Alerts.WebhookURL != "" {
+		notificationChannels = append(notificationChannels, adapters.NewWebhookNotifier(cfg.Alerts.WebhookURL))
+	}
+
+	// Emails transactionnels : fichiers .eml si MAIL_OUTPUT_DIR est défini, sinon les logs
+	var mailer services.Mailer = adapters.NewLogMailer()
+	if cfg.Mail.OutputDir != "" {
+		fileMailer, err := adapters.NewFileMailer(cfg.Mail.OutputDir, cfg.Mail.From)
+		if err != nil {
+			log.Fatalf("ERREUR FATALE: %v", err)
+		}
+		mailer = fileMailer
+	}
+	// Connexion OpenID Connect (Google + accès YouTube) si configurée
+	oauthConfigs, oauthSecretBox, err := config.LoadOAuthProviders(cfg.OAuth)
+	if err != nil {
+		log.Fatalf("ERREUR FATALE: Configuration OAuth invalide: %v", err)
+	}
+	var oauthProviders []services.OIDCProvider
+	for _, providerConfig := range oauthConfigs {
+		oauthProviders = append(oauthProviders, adapters.NewOIDCProvider(providerConfig, nil))
+	}
 	log.Println("Adapters et Utilitaires initialisés.")
 
 	// --- 4. Initialisation de Tous les Services (Injection des dépendances) ---
@@ -51,31 +85,77 @@ func main() {
 		allRepositories,   // <-- Injection de insightRepo
 		youtubeAdapter, // <-- Injection de youtubeAdapter
 		groqAdapter,    // <-- Injection de groqAdapter
+		adapters.NewLexiconAdapter(), // Analyse hors ligne (repli de Groq, plans sans IA)
 		transcriptUtil, // <-- Injection de transcriptUtil
+		notificationChannels,
+		mailer,
+		oauthProviders,
+		oauthSecretBox,
+		jwtKeys,
+		serviceOptions(cfg),
 	)
 	log.Println("Services initialisés.")
 
+	// Arrêt gracieux sur SIGINT / SIGTERM (déploiements)
+	stopCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Purge périodique des refresh tokens, entrées de révocation et tentatives de connexion expirés
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stopCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := allServices.TokenService.PurgeExpired(stopCtx); err != nil {
+				log.Printf("WARN: Échec de la purge des tokens expirés: %v", err)
+			}
+			if err := allServices.LoginGuardService.PurgeExpired(stopCtx); err != nil {
+				log.Printf("WARN: Échec de la purge des tentatives de connexion: %v", err)
+			}
+		}
+	}()
+
 	// --- 5. Initialisation des Handlers (passe les services appropriés) ---
-	allHandlers := handlers.NewAllHandlers(allServices.UserService, allServices.CommentService)
+	allHandlers := handlers.NewAllHandlers(allServices)
 	log.Println("Handlers initialisés.")
 
 	// --- 6. Configuration de l'Application Fiber (Middlewares, Routes) ---
-	app := fiber.New()
-
-	// Ajout de middlewares utiles
-	app.Use(cors.New())   // Autoriser les requêtes Cross-Origin (configurez selon vos besoins)
-	app.Use(logger.New()) // Logger les requêtes HTTP
-	app.Use(helmet.New())
-	// Création d'un groupe pour les routes API (bonne pratique)
-	routes.SetupUserRoutes(app, allHandlers.UserHandler)
-	routes.SetupCommentsRoutes(app, allHandlers.CommentHandler)
+	app := routes.NewApp(allServices, allHandlers)
 	log.Println("Application Fiber et routes configurées.")
 
 	// --- 7. Démarrage du Serveur Fiber ---
-	port := ":3001" // Vous pouvez aussi lire ceci depuis une variable d'environnement ou config
+	port := cfg.Addr()
 	log.Printf("Démarrage du serveur EngageSense sur le port %s", port)
-	err2 := app.Listen(port)
-	if err2 != nil {
-		log.Fatalf("Échec du démarrage du serveur Fiber: %v", err2)
+	listenErr := make(chan error, 1)
+	go func() { listenErr <- app.Listen(port) }()
+
+	select {
+	case err := <-listenErr:
+		log.Fatalf("Échec du démarrage du serveur Fiber: %v", err)
+	case <-stopCtx.Done():
+	}
+
+	// --- 8. Arrêt : readiness en échec, plus de nouvelles connexions, drain des analyses, fermeture de la base ---
+	log.Printf("Arrêt demandé : fin des requêtes et analyses en cours (délai max %s)", cfg.Server.ShutdownTimeout)
+	stop() // un second signal interrompt immédiatement le processus
+	allServices.HealthService.SetDraining()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	drained := make(chan error, 1)
+	go func() { drained <- allServices.CommentService.Drain(shutdownCtx) }()
+	if err := app.ShutdownWithContext(shutdownCtx); err != nil {
+		log.Printf("WARN: Arrêt du serveur HTTP incomplet: %v", err)
+	}
+	if err := <-drained; err != nil {
+		log.Printf("WARN: Analyses interrompues à l'expiration du délai d'arrêt: %v", err)
+	}
+	if err := config.CloseDB(); err != nil {
+		log.Printf("WARN: Échec de la fermeture de la base de données: %v", err)
 	}
+	log.Println("Serveur arrêté.")
 }
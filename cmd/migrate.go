@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/Azertdev/FiberTest/config"
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/migrations"
+)
+
+const migrateUsage = `Usage: migrate <commande>
+
+  status        liste les migrations et leur état
+  up [version]  applique les migrations en attente (jusqu'à version incluse si précisée)
+  down [n]      annule les n dernières migrations (1 par défaut)
+`
+
+// runMigrate exécute la sous-commande migrate et retourne le code de sortie
+func runMigrate(args []string) int {
+	if len(args) == 0 || len(args) > 2 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	var number int64
+	if len(args) == 2 {
+		parsed, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || parsed <= 0 {
+			fmt.Fprintf(os.Stderr, "argument invalide: %q (entier positif attendu)\n", args[1])
+			return 2
+		}
+		number = parsed
+	}
+
+	dbConfig, err := config.LoadDatabase()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Configuration invalide:\n%v\n", err)
+		return 1
+	}
+	if dbConfig.Driver != database.DriverPostgres {
+		// SQLite : schéma déduit des modèles au démarrage, pas de migrations versionnées
+		fmt.Fprintln(os.Stderr, "la commande migrate ne concerne que PostgreSQL (DB_DRIVER=postgres)")
+		return 2
+	}
+	db, err := config.OpenDB(*dbConfig)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// Pas de délai par requête : une migration peut durer, et l'attente du verrou aussi
+	ctx := database.WithoutQueryTimeout(context.Background())
+	switch args[0] {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNOM\tÉTAT")
+		for _, status := range statuses {
+			state := "en attente"
+			switch {
+			case status.Missing:
+				state = "appliquée le " + status.AppliedAt.Format("2006-01-02 15:04:05") + " (absente de ce binaire)"
+			case status.AppliedAt != nil:
+				state = "appliquée le " + status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, state)
+		}
+		w.Flush()
+	case "up":
+		applied, err := migrator.Up(ctx, number)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		if len(applied) == 0 {
+			fmt.Println("Schéma déjà à jour.")
+		}
+		for _, migration := range applied {
+			fmt.Println("appliquée:", migration)
+		}
+	case "down":
+		steps := 1
+		if number > 0 {
+			steps = int(number)
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if errors.Is(err, migrations.ErrNothingToRollback) {
+			fmt.Println(err)
+			return 0
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		for _, migration := range reverted {
+			fmt.Println("annulée:", migration)
+		}
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
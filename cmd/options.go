@@ -0,0 +1,55 @@
+package main
+
+import (
+	"github.com/Azertdev/FiberTest/config"
+	"github.com/Azertdev/FiberTest/internal/adapters"
+	"github.com/Azertdev/FiberTest/internal/services"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// Traduction de la configuration en options des adapters et des services :
+// config ne dépend pas des couches qu'il paramètre
+
+func youtubeOptions(cfg config.YouTubeConfig) adapters.YouTubeOptions {
+	return adapters.YouTubeOptions{BaseURL: cfg.BaseURL}
+}
+
+func groqOptions(cfg config.GroqConfig) adapters.GroqOptions {
+	return adapters.GroqOptions{
+		Model:                  cfg.Model,
+		BaseURL:                cfg.BaseURL,
+		Timeout:                cfg.Timeout,
+		AnalysisTemperature:    cfg.AnalysisTemperature,
+		AnalysisMaxTokens:      cfg.AnalysisMaxTokens,
+		SummaryTemperature:     cfg.SummaryTemperature,
+		SummaryMaxTokens:       cfg.SummaryMaxTokens,
+		MetaSummaryTemperature: cfg.MetaSummaryTemperature,
+		MetaSummaryMaxTokens:   cfg.MetaSummaryMaxTokens,
+	}
+}
+
+func serviceOptions(cfg *config.Config) services.ServiceOptions {
+	guard := cfg.Security.LoginGuard
+	return services.ServiceOptions{
+		AppBaseURL: cfg.Server.AppBaseURL,
+		Analysis: services.AnalysisOptions{
+			MaxComments:     cfg.Analysis.MaxComments,
+			ChunkSize:       cfg.Analysis.ChunkSize,
+			ChunkDelay:      cfg.Analysis.ChunkDelay,
+			DefaultMaxAge:   cfg.Analysis.InsightMaxAge,
+			Merge:           utils.MergeLimits{MaxListItems: cfg.Analysis.MergeMaxListItems, MaxKeywords: cfg.Analysis.MergeMaxKeywords},
+			MetaSummary:     cfg.Analysis.MetaSummary,
+			OfflineFallback: cfg.Analysis.OfflineFallback,
+			OfflinePlans:    cfg.Analysis.OfflinePlans,
+		},
+		LoginGuard: services.LoginGuardPolicy{
+			Window:          guard.Window,
+			DelayAfter:      guard.DelayAfter,
+			BaseDelay:       guard.BaseDelay,
+			MaxDelay:        guard.MaxDelay,
+			MaxUserFailures: guard.MaxUserFailures,
+			MaxIPFailures:   guard.MaxIPFailures,
+			LockoutDuration: guard.LockoutDuration,
+		},
+	}
+}
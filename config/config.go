@@ -0,0 +1,517 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// Config regroupe toute la configuration de l'application.
+// Priorité des sources : variables d'environnement > fichier (CONFIG_FILE, ".env" par défaut) > valeurs par défaut.
+// Les sections sont de simples valeurs : cmd/main.go les traduit en options des adapters et des services.
+type Config struct {
+	Server   ServerConfig
+	Database DatabaseConfig
+	YouTube  YouTubeConfig
+	Groq     GroqConfig
+	Analysis AnalysisConfig
+	JWT      JWTConfig
+	OAuth    OAuthConfig
+	Mail     MailConfig
+	Alerts   AlertsConfig
+	Security SecurityConfig
+}
+
+type ServerConfig struct {
+	Port       string // PORT
+	AppBaseURL string // APP_BASE_URL : URL du front utilisée dans les liens envoyés par email
+	// SHUTDOWN_TIMEOUT : délai laissé aux requêtes et analyses en cours lors d'un arrêt (SIGTERM)
+	ShutdownTimeout time.Duration
+}
+
+type DatabaseConfig struct {
+	Driver         string // DB_DRIVER : "postgres" (défaut) ou "sqlite" (développement local, sans Neon)
+	URL            string // DB_URL_NEON (postgres)
+	SQLitePath     string // DB_SQLITE_PATH (sqlite)
+	MigrateOnStart bool   // DB_MIGRATE_ON_START : applique les migrations en attente au démarrage
+	// Pool de connexions (DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME, DB_CONN_MAX_IDLE_TIME)
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	QueryTimeout    time.Duration // DB_QUERY_TIMEOUT : durée maximale d'une requête SQL (0 : illimitée)
+}
+
+type YouTubeConfig struct {
+	APIKey  string // YOUTUBE_API_KEY
+	BaseURL string // YOUTUBE_BASE_URL (vide : API Google ; sinon émulateur ou serveur de test)
+}
+
+type GroqConfig struct {
+	APIKey                 string        // GROQ_API_KEY
+	Model                  string        // GROQ_MODEL
+	BaseURL                string        // GROQ_BASE_URL : endpoint chat completions compatible OpenAI
+	Timeout                time.Duration // GROQ_TIMEOUT : timeout HTTP d'un appel
+	AnalysisTemperature    float64       // GROQ_ANALYSIS_TEMPERATURE
+	AnalysisMaxTokens      int           // GROQ_ANALYSIS_MAX_TOKENS
+	SummaryTemperature     float64       // GROQ_SUMMARY_TEMPERATURE
+	SummaryMaxTokens       int           // GROQ_SUMMARY_MAX_TOKENS
+	MetaSummaryTemperature float64       // GROQ_META_SUMMARY_TEMPERATURE
+	MetaSummaryMaxTokens   int           // GROQ_META_SUMMARY_MAX_TOKENS
+}
+
+type AnalysisConfig struct {
+	MaxComments       int64         // ANALYSIS_MAX_COMMENTS : commentaires récupérés par analyse
+	ChunkSize         int           // ANALYSIS_CHUNK_SIZE : commentaires par appel Groq
+	ChunkDelay        time.Duration // ANALYSIS_CHUNK_DELAY : pause entre deux lots
+	InsightMaxAge     time.Duration // INSIGHT_MAX_AGE : âge max d'un insight servi sans nouvelle analyse
+	MergeMaxListItems int           // MERGE_MAX_LIST_ITEMS
+	MergeMaxKeywords  int           // MERGE_MAX_KEYWORDS
+	MetaSummary       bool          // ANALYSIS_META_SUMMARY : sentiment et résumé globaux rédigés par le modèle (désactivé par défaut)
+	OfflineFallback   bool          // ANALYSIS_OFFLINE_FALLBACK : lot en échec analysé hors ligne
+	OfflinePlans      []string      // ANALYSIS_OFFLINE_PLANS : plans toujours analysés hors ligne ("free,pro")
+}
+
+type JWTConfig struct {
+	KeysFile string // JWT_KEYS_FILE (prioritaire)
+	Secret   string // JWT_SECRET (HS256)
+	KID      string // JWT_KID
+}
+
+type OAuthConfig struct {
+	GoogleClientID     string
+	GoogleClientSecret string
+	GoogleRedirectURL  string
+	EncryptionKey      string // OAUTH_ENCRYPTION_KEY : 32 octets en base64
+}
+
+type MailConfig struct {
+	OutputDir string // MAIL_OUTPUT_DIR : fichiers .eml si défini, sinon les logs
+	From      string
+}
+
+type AlertsConfig struct {
+	WebhookURL string // ALERT_WEBHOOK_URL (optionnel)
+}
+
+type SecurityConfig struct {
+	LoginAttemptStore string // "memory" (instance unique) ou "postgres" (partagé entre instances)
+	LoginGuard        LoginGuardConfig
+}
+
+// LoginGuardConfig : seuils de la protection contre le brute-force (LOGIN_*)
+type LoginGuardConfig struct {
+	Window          time.Duration // LOGIN_WINDOW
+	DelayAfter      int           // LOGIN_DELAY_AFTER : échecs tolérés avant le premier délai
+	BaseDelay       time.Duration // LOGIN_BASE_DELAY
+	MaxDelay        time.Duration // LOGIN_MAX_DELAY
+	MaxUserFailures int           // LOGIN_MAX_USER_FAILURES
+	MaxIPFailures   int           // LOGIN_MAX_IP_FAILURES
+	LockoutDuration time.Duration // LOGIN_LOCKOUT_DURATION
+}
+
+// Default retourne la configuration par défaut (les secrets restent vides)
+func Default() Config {
+	return Config{
+		Server: ServerConfig{Port: "3001", AppBaseURL: "http://localhost:3000", ShutdownTimeout: 60 * time.Second},
+		Database: DatabaseConfig{
+			Driver:          database.DriverPostgres,
+			SQLitePath:      "engagesense.db",
+			MigrateOnStart:  true,
+			MaxOpenConns:    20,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			QueryTimeout:    5 * time.Second,
+		},
+		Groq: GroqConfig{
+			Model:                  "deepseek-r1-distill-llama-70b",
+			BaseURL:                "https://api.groq.com/openai/v1/chat/completions",
+			Timeout:                90 * time.Second,
+			AnalysisTemperature:    0.5,
+			AnalysisMaxTokens:      4096,
+			SummaryTemperature:     0.3,
+			SummaryMaxTokens:       768,
+			MetaSummaryTemperature: 0.3,
+			MetaSummaryMaxTokens:   2048, // les modèles de raisonnement consomment des tokens avant de répondre
+		},
+		Analysis: AnalysisConfig{
+			MaxComments:       2000,
+			ChunkSize:         50,
+			ChunkDelay:        500 * time.Millisecond,
+			InsightMaxAge:     24 * time.Hour,
+			MergeMaxListItems: 10,
+			MergeMaxKeywords:  15,
+			MetaSummary:       false, // un appel au modèle de plus par analyse : à activer explicitement
+			OfflineFallback:   true,
+		},
+		JWT:  JWTConfig{KID: "default"},
+		Mail: MailConfig{From: "EngageSense <no-reply@engagesense.local>"},
+		Security: SecurityConfig{
+			LoginAttemptStore: "memory",
+			LoginGuard: LoginGuardConfig{
+				Window:          15 * time.Minute,
+				DelayAfter:      3,
+				BaseDelay:       time.Second,
+				MaxDelay:        30 * time.Second,
+				MaxUserFailures: 5,
+				MaxIPFailures:   20,
+				LockoutDuration: 15 * time.Minute,
+			},
+		},
+	}
+}
+
+// Load construit et valide la configuration. Toutes les erreurs sont retournées ensemble
+// (errors.Join) pour pouvoir tout corriger en un seul redémarrage.
+func Load() (*Config, error) {
+	s, err := newSource()
+	if err != nil {
+		return nil, err
+	}
+	cfg := Default()
+
+	s.str("PORT", &cfg.Server.Port)
+	s.str("APP_BASE_URL", &cfg.Server.AppBaseURL)
+	s.duration("SHUTDOWN_TIMEOUT", &cfg.Server.ShutdownTimeout)
+	s.database(&cfg.Database)
+	s.str("YOUTUBE_API_KEY", &cfg.YouTube.APIKey)
+	s.str("YOUTUBE_BASE_URL", &cfg.YouTube.BaseURL)
+
+	s.str("GROQ_API_KEY", &cfg.Groq.APIKey)
+	s.str("GROQ_MODEL", &cfg.Groq.Model)
+	s.str("GROQ_BASE_URL", &cfg.Groq.BaseURL)
+	s.duration("GROQ_TIMEOUT", &cfg.Groq.Timeout)
+	s.float("GROQ_ANALYSIS_TEMPERATURE", &cfg.Groq.AnalysisTemperature)
+	s.int("GROQ_ANALYSIS_MAX_TOKENS", &cfg.Groq.AnalysisMaxTokens)
+	s.float("GROQ_SUMMARY_TEMPERATURE", &cfg.Groq.SummaryTemperature)
+	s.int("GROQ_SUMMARY_MAX_TOKENS", &cfg.Groq.SummaryMaxTokens)
+	s.float("GROQ_META_SUMMARY_TEMPERATURE", &cfg.Groq.MetaSummaryTemperature)
+	s.int("GROQ_META_SUMMARY_MAX_TOKENS", &cfg.Groq.MetaSummaryMaxTokens)
+
+	s.int64("ANALYSIS_MAX_COMMENTS", &cfg.Analysis.MaxComments)
+	s.int("ANALYSIS_CHUNK_SIZE", &cfg.Analysis.ChunkSize)
+	s.duration("ANALYSIS_CHUNK_DELAY", &cfg.Analysis.ChunkDelay)
+	s.duration("INSIGHT_MAX_AGE", &cfg.Analysis.InsightMaxAge)
+	s.int("MERGE_MAX_LIST_ITEMS", &cfg.Analysis.MergeMaxListItems)
+	s.int("MERGE_MAX_KEYWORDS", &cfg.Analysis.MergeMaxKeywords)
+	s.bool("ANALYSIS_META_SUMMARY", &cfg.Analysis.MetaSummary)
+	s.bool("ANALYSIS_OFFLINE_FALLBACK", &cfg.Analysis.OfflineFallback)
+	s.list("ANALYSIS_OFFLINE_PLANS", &cfg.Analysis.OfflinePlans)
+
+	s.str("JWT_KEYS_FILE", &cfg.JWT.KeysFile)
+	s.str("JWT_SECRET", &cfg.JWT.Secret)
+	s.str("JWT_KID", &cfg.JWT.KID)
+
+	s.str("GOOGLE_CLIENT_ID", &cfg.OAuth.GoogleClientID)
+	s.str("GOOGLE_CLIENT_SECRET", &cfg.OAuth.GoogleClientSecret)
+	s.str("GOOGLE_REDIRECT_URL", &cfg.OAuth.GoogleRedirectURL)
+	s.str("OAUTH_ENCRYPTION_KEY", &cfg.OAuth.EncryptionKey)
+
+	s.str("MAIL_OUTPUT_DIR", &cfg.Mail.OutputDir)
+	s.str("MAIL_FROM", &cfg.Mail.From)
+	s.str("ALERT_WEBHOOK_URL", &cfg.Alerts.WebhookURL)
+
+	s.str("LOGIN_ATTEMPT_STORE", &cfg.Security.LoginAttemptStore)
+	s.duration("LOGIN_WINDOW", &cfg.Security.LoginGuard.Window)
+	s.int("LOGIN_DELAY_AFTER", &cfg.Security.LoginGuard.DelayAfter)
+	s.duration("LOGIN_BASE_DELAY", &cfg.Security.LoginGuard.BaseDelay)
+	s.duration("LOGIN_MAX_DELAY", &cfg.Security.LoginGuard.MaxDelay)
+	s.int("LOGIN_MAX_USER_FAILURES", &cfg.Security.LoginGuard.MaxUserFailures)
+	s.int("LOGIN_MAX_IP_FAILURES", &cfg.Security.LoginGuard.MaxIPFailures)
+	s.duration("LOGIN_LOCKOUT_DURATION", &cfg.Security.LoginGuard.LockoutDuration)
+
+	problems := append(s.problems, cfg.Validate()...)
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return &cfg, nil
+}
+
+// LoadDatabase ne lit que la section base de données (commande migrate : pas besoin des clés d'API)
+func LoadDatabase() (*DatabaseConfig, error) {
+	s, err := newSource()
+	if err != nil {
+		return nil, err
+	}
+	cfg := Default().Database
+	s.database(&cfg)
+	problems := append(s.problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, errors.Join(problems...)
+	}
+	return &cfg, nil
+}
+
+// DSN retourne la chaîne de connexion du moteur choisi
+func (c *DatabaseConfig) DSN() string {
+	if c.Driver == database.DriverSQLite {
+		return c.SQLitePath
+	}
+	return c.URL
+}
+
+func (s *source) database(cfg *DatabaseConfig) {
+	s.str("DB_DRIVER", &cfg.Driver)
+	s.str("DB_URL_NEON", &cfg.URL)
+	s.str("DB_SQLITE_PATH", &cfg.SQLitePath)
+	s.bool("DB_MIGRATE_ON_START", &cfg.MigrateOnStart)
+	s.int("DB_MAX_OPEN_CONNS", &cfg.MaxOpenConns)
+	s.int("DB_MAX_IDLE_CONNS", &cfg.MaxIdleConns)
+	s.duration("DB_CONN_MAX_LIFETIME", &cfg.ConnMaxLifetime)
+	s.duration("DB_CONN_MAX_IDLE_TIME", &cfg.ConnMaxIdleTime)
+	s.duration("DB_QUERY_TIMEOUT", &cfg.QueryTimeout)
+}
+
+func (c *DatabaseConfig) validate() []error {
+	var problems []error
+	switch c.Driver {
+	case database.DriverPostgres:
+		if c.URL == "" {
+			problems = append(problems, errors.New("DB_URL_NEON est requis"))
+		}
+	case database.DriverSQLite:
+		if c.SQLitePath == "" {
+			problems = append(problems, errors.New("DB_SQLITE_PATH est requis avec DB_DRIVER=sqlite"))
+		}
+	default:
+		problems = append(problems, fmt.Errorf("DB_DRIVER doit valoir %q ou %q (reçu %q)", database.DriverPostgres, database.DriverSQLite, c.Driver))
+	}
+	if c.MaxOpenConns <= 0 {
+		problems = append(problems, fmt.Errorf("DB_MAX_OPEN_CONNS doit être strictement positif (reçu %d)", c.MaxOpenConns))
+	}
+	if c.MaxIdleConns < 0 || c.MaxIdleConns > c.MaxOpenConns {
+		problems = append(problems, fmt.Errorf("DB_MAX_IDLE_CONNS doit être compris entre 0 et DB_MAX_OPEN_CONNS (reçu %d)", c.MaxIdleConns))
+	}
+	if c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		problems = append(problems, errors.New("DB_CONN_MAX_LIFETIME et DB_CONN_MAX_IDLE_TIME ne peuvent pas être négatifs (0 : illimité)"))
+	}
+	if c.QueryTimeout < 0 {
+		problems = append(problems, errors.New("DB_QUERY_TIMEOUT ne peut pas être négatif (0 : illimité)"))
+	}
+	return problems
+}
+
+func newSource() (*source, error) {
+	fileValues, err := readConfigFile()
+	if err != nil {
+		return nil, err
+	}
+	return &source{file: fileValues}, nil
+}
+
+// readConfigFile lit CONFIG_FILE s'il est défini (erreur s'il est absent), sinon ".env" s'il existe
+func readConfigFile() (map[string]string, error) {
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit || path == "" {
+		path = ".env"
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil
+		}
+	}
+	values, err := godotenv.Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture du fichier de configuration %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Validate retourne la liste de tous les problèmes de configuration
+func (c *Config) Validate() []error {
+	var problems []error
+	required := func(key, value string) {
+		if value == "" {
+			problems = append(problems, fmt.Errorf("%s est requis", key))
+		}
+	}
+	positive := func(key string, value int64) {
+		if value <= 0 {
+			problems = append(problems, fmt.Errorf("%s doit être strictement positif (reçu %d)", key, value))
+		}
+	}
+	positiveDuration := func(key string, value time.Duration) {
+		if value <= 0 {
+			problems = append(problems, fmt.Errorf("%s doit être une durée strictement positive (reçu %s)", key, value))
+		}
+	}
+	temperature := func(key string, value float64) {
+		if value < 0 || value > 2 {
+			problems = append(problems, fmt.Errorf("%s doit être compris entre 0 et 2 (reçu %g)", key, value))
+		}
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		problems = append(problems, fmt.Errorf("PORT invalide: %q", c.Server.Port))
+	}
+	if u, err := url.Parse(c.Server.AppBaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		problems = append(problems, fmt.Errorf("APP_BASE_URL invalide: %q", c.Server.AppBaseURL))
+	}
+	positiveDuration("SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout)
+	problems = append(problems, c.Database.validate()...)
+	required("YOUTUBE_API_KEY", c.YouTube.APIKey)
+	required("GROQ_API_KEY", c.Groq.APIKey)
+
+	required("GROQ_MODEL", c.Groq.Model)
+	if u, err := url.Parse(c.Groq.BaseURL); err != nil || u.Scheme == "" || u.Host == "" {
+		problems = append(problems, fmt.Errorf("GROQ_BASE_URL invalide: %q", c.Groq.BaseURL))
+	}
+	positiveDuration("GROQ_TIMEOUT", c.Groq.Timeout)
+	positive("GROQ_ANALYSIS_MAX_TOKENS", int64(c.Groq.AnalysisMaxTokens))
+	positive("GROQ_SUMMARY_MAX_TOKENS", int64(c.Groq.SummaryMaxTokens))
+	positive("GROQ_META_SUMMARY_MAX_TOKENS", int64(c.Groq.MetaSummaryMaxTokens))
+	temperature("GROQ_ANALYSIS_TEMPERATURE", c.Groq.AnalysisTemperature)
+	temperature("GROQ_SUMMARY_TEMPERATURE", c.Groq.SummaryTemperature)
+	temperature("GROQ_META_SUMMARY_TEMPERATURE", c.Groq.MetaSummaryTemperature)
+
+	positive("ANALYSIS_MAX_COMMENTS", c.Analysis.MaxComments)
+	positive("ANALYSIS_CHUNK_SIZE", int64(c.Analysis.ChunkSize))
+	if c.Analysis.ChunkDelay < 0 {
+		problems = append(problems, fmt.Errorf("ANALYSIS_CHUNK_DELAY ne peut pas être négatif"))
+	}
+	positiveDuration("INSIGHT_MAX_AGE", c.Analysis.InsightMaxAge)
+	positive("MERGE_MAX_LIST_ITEMS", int64(c.Analysis.MergeMaxListItems))
+	positive("MERGE_MAX_KEYWORDS", int64(c.Analysis.MergeMaxKeywords))
+	for _, plan := range c.Analysis.OfflinePlans {
+		if plan != models.PlanFree && plan != models.PlanPro && plan != models.PlanBusiness {
+			problems = append(problems, fmt.Errorf("ANALYSIS_OFFLINE_PLANS: plan inconnu %q", plan))
+		}
+	}
+
+	if _, err := LoadJWTKeySet(c.JWT); err != nil {
+		problems = append(problems, fmt.Errorf("clés JWT invalides: %w", err))
+	}
+	if _, _, err := LoadOAuthProviders(c.OAuth); err != nil {
+		problems = append(problems, fmt.Errorf("configuration OAuth invalide: %w", err))
+	}
+
+	if c.Security.LoginAttemptStore != "memory" && c.Security.LoginAttemptStore != "postgres" {
+		problems = append(problems, fmt.Errorf("LOGIN_ATTEMPT_STORE doit valoir \"memory\" ou \"postgres\" (reçu %q)", c.Security.LoginAttemptStore))
+	}
+	guard := c.Security.LoginGuard
+	positiveDuration("LOGIN_WINDOW", guard.Window)
+	if guard.DelayAfter < 0 {
+		problems = append(problems, fmt.Errorf("LOGIN_DELAY_AFTER ne peut pas être négatif (reçu %d)", guard.DelayAfter))
+	}
+	if guard.BaseDelay < 0 {
+		problems = append(problems, fmt.Errorf("LOGIN_BASE_DELAY ne peut pas être négatif (reçu %s)", guard.BaseDelay))
+	}
+	positive("LOGIN_MAX_USER_FAILURES", int64(guard.MaxUserFailures))
+	positive("LOGIN_MAX_IP_FAILURES", int64(guard.MaxIPFailures))
+	positiveDuration("LOGIN_LOCKOUT_DURATION", guard.LockoutDuration)
+	if guard.BaseDelay > guard.MaxDelay {
+		problems = append(problems, fmt.Errorf("LOGIN_BASE_DELAY (%s) dépasse LOGIN_MAX_DELAY (%s)", guard.BaseDelay, guard.MaxDelay))
+	}
+	return problems
+}
+
+// Addr retourne l'adresse d'écoute du serveur HTTP
+func (c *Config) Addr() string {
+	return ":" + c.Server.Port
+}
+
+// source lit une clé dans l'environnement puis dans le fichier, et accumule les erreurs de conversion
+type source struct {
+	file     map[string]string
+	problems []error
+}
+
+func (s *source) lookup(key string) (string, bool) {
+	if value, ok := os.LookupEnv(key); ok {
+		return strings.TrimSpace(value), true
+	}
+	value, ok := s.file[key]
+	return strings.TrimSpace(value), ok
+}
+
+func (s *source) str(key string, target *string) {
+	if value, ok := s.lookup(key); ok && value != "" {
+		*target = value
+	}
+}
+
+func (s *source) parse(key string, convert func(string) error) {
+	value, ok := s.lookup(key)
+	if !ok || value == "" {
+		return
+	}
+	if err := convert(value); err != nil {
+		s.problems = append(s.problems, fmt.Errorf("%s invalide: %q", key, value))
+	}
+}
+
+// Les convertisseurs ne modifient la cible qu'en cas de succès : une valeur invalide
+// n'est signalée qu'une fois (pas d'erreur de validation supplémentaire sur un zéro)
+func (s *source) int(key string, target *int) {
+	s.parse(key, func(value string) error {
+		parsed, err := strconv.Atoi(value)
+		if err == nil {
+			*target = parsed
+		}
+		return err
+	})
+}
+
+func (s *source) int64(key string, target *int64) {
+	s.parse(key, func(value string) error {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err == nil {
+			*target = parsed
+		}
+		return err
+	})
+}
+
+func (s *source) float(key string, target *float64) {
+	s.parse(key, func(value string) error {
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			*target = parsed
+		}
+		return err
+	})
+}
+
+func (s *source) bool(key string, target *bool) {
+	s.parse(key, func(value string) error {
+		parsed, err := strconv.ParseBool(value)
+		if err == nil {
+			*target = parsed
+		}
+		return err
+	})
+}
+
+// list lit une liste séparée par des virgules ("free,pro") ; les éléments vides sont ignorés
+func (s *source) list(key string, target *[]string) {
+	value, ok := s.lookup(key)
+	if !ok || value == "" {
+		return
+	}
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*target = items
+}
+
+// duration accepte le format Go ("500ms", "90s", "24h")
+func (s *source) duration(key string, target *time.Duration) {
+	s.parse(key, func(value string) error {
+		parsed, err := time.ParseDuration(value)
+		if err == nil {
+			*target = parsed
+		}
+		return err
+	})
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Secrets requis par Validate, fournis par le fichier de configuration des tests
+const requiredSettings = `
+DB_URL_NEON=postgres://localhost/test
+YOUTUBE_API_KEY=yt-key
+GROQ_API_KEY=groq-key
+JWT_SECRET=0123456789abcdef0123456789abcdef
+`
+
+// loadWith charge la configuration depuis un fichier temporaire (CONFIG_FILE) et les variables
+// d'environnement données ; les clés lues par les tests sont d'abord retirées de l'environnement
+func loadWith(t *testing.T, file string, env map[string]string) (*Config, error) {
+	t.Helper()
+	for _, key := range []string{"PORT", "GROQ_MODEL", "GROQ_TIMEOUT", "ANALYSIS_CHUNK_SIZE", "LOGIN_DELAY_AFTER",
+		"DB_URL_NEON", "DB_DRIVER", "YOUTUBE_API_KEY", "GROQ_API_KEY", "JWT_SECRET", "JWT_KEYS_FILE", "GOOGLE_CLIENT_ID"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+	path := filepath.Join(t.TempDir(), "test.env")
+	if err := os.WriteFile(path, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	return Load()
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	cases := []struct {
+		name      string
+		file      string
+		env       map[string]string
+		port      string
+		model     string
+		chunkSize int
+	}{
+		{"valeurs par défaut", "", nil, "3001", "deepseek-r1-distill-llama-70b", 50},
+		{"fichier", "PORT=4000\nGROQ_MODEL=llama3-8b\nANALYSIS_CHUNK_SIZE=20\n", nil, "4000", "llama3-8b", 20},
+		{"environnement prioritaire", "PORT=4000\nGROQ_MODEL=llama3-8b\n",
+			map[string]string{"PORT": "5000", "ANALYSIS_CHUNK_SIZE": "10"}, "5000", "llama3-8b", 10},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := loadWith(t, requiredSettings+tc.file, tc.env)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Server.Port != tc.port || cfg.Groq.Model != tc.model || cfg.Analysis.ChunkSize != tc.chunkSize {
+				t.Fatalf("port %q, modèle %q, lots de %d ; attendu %q, %q, %d",
+					cfg.Server.Port, cfg.Groq.Model, cfg.Analysis.ChunkSize, tc.port, tc.model, tc.chunkSize)
+			}
+		})
+	}
+}
+
+func TestLoadJoinsAllProblems(t *testing.T) {
+	_, err := loadWith(t, "PORT=http\nGROQ_TIMEOUT=bientôt\n", map[string]string{"LOGIN_DELAY_AFTER": "-1"})
+	if err == nil {
+		t.Fatal("configuration invalide acceptée")
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("erreurs non regroupées: %T", err)
+	}
+	// Conversion (GROQ_TIMEOUT), validation (PORT, LOGIN_DELAY_AFTER) et secrets manquants
+	for _, want := range []string{"GROQ_TIMEOUT invalide", "PORT invalide", "LOGIN_DELAY_AFTER", "DB_URL_NEON est requis",
+		"YOUTUBE_API_KEY est requis", "GROQ_API_KEY est requis", "clés JWT invalides"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("problème %q absent de:\n%v", want, err)
+		}
+	}
+	if len(joined.Unwrap()) != 7 {
+		t.Fatalf("%d problèmes, attendu 7:\n%v", len(joined.Unwrap()), err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() Config {
+		cfg := Default()
+		cfg.Database.URL = "postgres://localhost/test"
+		cfg.YouTube.APIKey = "yt-key"
+		cfg.Groq.APIKey = "groq-key"
+		cfg.JWT.Secret = "0123456789abcdef0123456789abcdef"
+		return cfg
+	}
+	cases := []struct {
+		name   string
+		mutate func(*Config)
+		want   string // vide : configuration valide
+	}{
+		{"configuration par défaut", func(*Config) {}, ""},
+		{"port hors limites", func(c *Config) { c.Server.Port = "70000" }, "PORT invalide"},
+		{"URL du front relative", func(c *Config) { c.Server.AppBaseURL = "/app" }, "APP_BASE_URL invalide"},
+		{"moteur inconnu", func(c *Config) { c.Database.Driver = "mysql" }, "DB_DRIVER doit valoir"},
+		{"pool incohérent", func(c *Config) { c.Database.MaxIdleConns = 50 }, "DB_MAX_IDLE_CONNS"},
+		{"température trop haute", func(c *Config) { c.Groq.SummaryTemperature = 2.5 }, "GROQ_SUMMARY_TEMPERATURE"},
+		{"endpoint Groq invalide", func(c *Config) { c.Groq.BaseURL = "groq" }, "GROQ_BASE_URL invalide"},
+		{"lots vides", func(c *Config) { c.Analysis.ChunkSize = 0 }, "ANALYSIS_CHUNK_SIZE"},
+		{"pause négative", func(c *Config) { c.Analysis.ChunkDelay = -time.Second }, "ANALYSIS_CHUNK_DELAY"},
+		{"plan inconnu", func(c *Config) { c.Analysis.OfflinePlans = []string{"gold"} }, `plan inconnu "gold"`},
+		{"OAuth incomplet", func(c *Config) { c.OAuth.GoogleClientID = "client" }, "configuration OAuth invalide"},
+		{"store inconnu", func(c *Config) { c.Security.LoginAttemptStore = "redis" }, "LOGIN_ATTEMPT_STORE"},
+		{"délai après un nombre négatif d'échecs", func(c *Config) { c.Security.LoginGuard.DelayAfter = -1 }, "LOGIN_DELAY_AFTER"},
+		{"délai sans seuil", func(c *Config) { c.Security.LoginGuard.DelayAfter = 0 }, ""},
+		{"délai de base négatif", func(c *Config) { c.Security.LoginGuard.BaseDelay = -time.Second }, "LOGIN_BASE_DELAY ne peut pas"},
+		{"délai de base au-delà du maximum", func(c *Config) { c.Security.LoginGuard.BaseDelay = time.Minute }, "dépasse LOGIN_MAX_DELAY"},
+		{"aucun échec toléré", func(c *Config) { c.Security.LoginGuard.MaxUserFailures = 0 }, "LOGIN_MAX_USER_FAILURES"},
+		{"fenêtre nulle", func(c *Config) { c.Security.LoginGuard.Window = 0 }, "LOGIN_WINDOW"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := valid()
+			tc.mutate(&cfg)
+			err := errors.Join(cfg.Validate()...)
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("configuration refusée: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("erreur %v, attendu %q", err, tc.want)
+			}
+		})
+	}
+}
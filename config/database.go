@@ -1,49 +1,64 @@
 package config
 
 import (
+	"context"
 	"fmt"
 	"log"
-	"os"
-	"github.com/Azertdev/FiberTest/internal/models"
-	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
+
+	"github.com/Azertdev/FiberTest/internal/database"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
 
-func InitDB() {
-	// Charger les variables d’environnement
-	err := godotenv.Load()
+// OpenDB ouvre la connexion, règle le pool et le délai par requête, sans toucher au schéma (commande migrate)
+func OpenDB(cfg DatabaseConfig) (*gorm.DB, error) {
+	db, err := database.Open(cfg.Driver, cfg.DSN())
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
 	if err != nil {
-		log.Fatal("Erreur lors du chargement du fichier .env")
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	if err := database.UseQueryTimeout(db, cfg.QueryTimeout); err != nil {
+		return nil, err
 	}
+	return db, nil
+}
 
-	dsn := os.Getenv("DB_URL_NEON")
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// CloseDB ferme le pool de connexions (arrêt du serveur)
+func CloseDB() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
 	if err != nil {
-		log.Fatal("Erreur de connexion à la base de données :", err)
+		return err
 	}
+	return sqlDB.Close()
+}
 
+// InitDB ouvre la connexion et, si MigrateOnStart, met le schéma à jour
+// (PostgreSQL : internal/migrations et la commande "migrate" ; SQLite : schéma déduit des modèles).
+func InitDB(cfg DatabaseConfig) {
+	db, err := OpenDB(cfg)
+	if err != nil {
+		log.Fatal("Erreur de connexion à la base de données :", err)
+	}
 	DB = db
-	fmt.Println("✅ Connecté à PostgreSQL")
-// Créer le type ENUM 'user_role' dans PostgreSQL
-	// Utilisation de la commande SQL pour créer le type ENUM directement dans la base de données
-	db.Exec(`CREATE TYPE user_role AS ENUM ('user', 'admin')`)
-	db.Exec(`CREATE TYPE user_platform AS ENUM ('instagram', 'twitter', 'youtube')`)
-	db.Exec(`CREATE TYPE subscription_plan AS ENUM ('free', 'pro', 'business')`)
-	db.Exec(`CREATE TYPE subscription_Status AS ENUM ('active', 'cancelled')`)
-	db.Exec(`CREATE TYPE Notification_Type AS ENUM ('analysis', 'payment', 'alert')`)
-
-// Supprimer la table 'users' si elle existe déjà
-// if err := db.Migrator().DropTable(&models.User{}); err != nil {
-// 	log.Fatal("Erreur lors de la suppression de la table 'users':", err)
-// }
-// fmt.Println("🗑️ Table 'users' supprimée avec succès")
+	fmt.Printf("✅ Connecté à la base de données (%s)\n", cfg.Driver)
 
-// Auto-migrer les modèles, ce qui recréera la table 'users' avec le nouveau schéma
-if err := db.AutoMigrate(&models.User{}, &models.Subscription{}, &models.Comment{}, &models.Insight{}, &models.Notification{}); err != nil {
-	log.Fatal("Erreur lors de la migration des modèles :", err)
-}
-fmt.Println("✅ Tables recréées avec succès")
+	if !cfg.MigrateOnStart {
+		return
+	}
+	applied, err := database.Migrate(context.Background(), db)
+	if err != nil {
+		log.Fatal("Erreur lors de la migration du schéma :", err)
+	}
+	fmt.Printf("✅ Schéma à jour (%d migration(s) appliquée(s))\n", applied)
 }
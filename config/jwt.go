@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// jwtKeysFile est le format du fichier JWT_KEYS_FILE :
+//
+//	{
+//	  "active_kid": "2026-10",
+//	  "keys": [
+//	    {"kid": "2026-10", "key_file": "keys/jwt-2026-10.pem"},
+//	    {"kid": "2026-04", "alg": "HS256", "secret": "..."}
+//	  ]
+//	}
+//
+// Les clés non actives restent acceptées en vérification : pour une rotation, ajouter la
+// nouvelle clé, la rendre active, puis retirer l'ancienne une fois ses tokens expirés.
+type jwtKeysFile struct {
+	ActiveKID string `json:"active_kid"`
+	Keys      []struct {
+		KID     string `json:"kid"`
+		Alg     string `json:"alg"`      // optionnel pour key_file (déduit de la clé)
+		Secret  string `json:"secret"`   // HS256
+		KeyFile string `json:"key_file"` // RS256/EdDSA : PEM privé (signature) ou public (vérification seule)
+	} `json:"keys"`
+}
+
+// LoadJWTKeySet charge les clés de signature depuis JWT_KEYS_FILE, ou à défaut depuis
+// JWT_SECRET (HS256, kid JWT_KID ou "default").
+func LoadJWTKeySet(cfg JWTConfig) (*utils.KeySet, error) {
+	if cfg.KeysFile != "" {
+		return loadJWTKeysFile(cfg.KeysFile)
+	}
+
+	if cfg.Secret == "" {
+		return nil, errors.New("ni JWT_KEYS_FILE ni JWT_SECRET ne sont définis")
+	}
+	kid := cfg.KID
+	if kid == "" {
+		kid = "default"
+	}
+	return utils.NewKeySet(kid, []*utils.SigningKey{utils.NewHMACKey(kid, []byte(cfg.Secret))})
+}
+
+func loadJWTKeysFile(path string) (*utils.KeySet, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture de %s: %w", path, err)
+	}
+	var file jwtKeysFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return nil, fmt.Errorf("format JSON invalide dans %s: %w", path, err)
+	}
+
+	var keys []*utils.SigningKey
+	for _, entry := range file.Keys {
+		switch {
+		case entry.KeyFile != "":
+			pemBytes, err := os.ReadFile(entry.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("clé JWT %q: lecture de %s: %w", entry.KID, entry.KeyFile, err)
+			}
+			key, err := utils.ParsePEMKey(entry.KID, pemBytes)
+			if err != nil {
+				return nil, fmt.Errorf("clé JWT %q: %w", entry.KID, err)
+			}
+			if entry.Alg != "" && entry.Alg != key.Algorithm {
+				return nil, fmt.Errorf("clé JWT %q: algorithme %q déclaré mais clé %s fournie", entry.KID, entry.Alg, key.Algorithm)
+			}
+			keys = append(keys, key)
+		case entry.Secret != "":
+			if entry.Alg != "" && entry.Alg != utils.AlgHS256 {
+				return nil, fmt.Errorf("clé JWT %q: un secret ne peut être utilisé qu'avec HS256", entry.KID)
+			}
+			keys = append(keys, utils.NewHMACKey(entry.KID, []byte(entry.Secret)))
+		default:
+			return nil, fmt.Errorf("clé JWT %q: 'secret' ou 'key_file' requis", entry.KID)
+		}
+	}
+	return utils.NewKeySet(file.ActiveKID, keys)
+}
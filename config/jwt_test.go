@@ -0,0 +1,136 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// writeKeysFile écrit une clé Ed25519 (PEM PKCS#8) et le fichier JWT_KEYS_FILE qui la référence
+// (le chemin "{pem}" de keysJSON est remplacé par celui de la clé)
+func writeKeysFile(t *testing.T, keysJSON string) string {
+	t.Helper()
+	dir := t.TempDir()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pemPath := filepath.Join(dir, "jwt.pem")
+	if err := os.WriteFile(pemPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "jwt-keys.json")
+	if err := os.WriteFile(path, []byte(strings.ReplaceAll(keysJSON, "{pem}", pemPath)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// signingKID retourne le kid et l'algorithme de la clé qui signe les nouveaux tokens
+func signingKID(t *testing.T, keys *utils.KeySet) (string, string) {
+	t.Helper()
+	token, err := utils.GenerateJWT(keys, uuid.New(), "user")
+	if err != nil {
+		t.Fatalf("GenerateJWT: %v", err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &utils.Claims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid, parsed.Method.Alg()
+}
+
+func TestLoadJWTKeySet(t *testing.T) {
+	rotation := `{"active_kid": "2026-10", "keys": [
+		{"kid": "2026-10", "key_file": "{pem}"},
+		{"kid": "2026-04", "alg": "HS256", "secret": "` + testJWTSecret + `"}
+	]}`
+	cases := []struct {
+		name    string
+		cfg     func(t *testing.T) JWTConfig
+		kid     string
+		alg     string
+		wantErr string // vide : chargement réussi
+	}{
+		{"secret seul, kid par défaut", func(*testing.T) JWTConfig { return JWTConfig{Secret: testJWTSecret} }, "default", utils.AlgHS256, ""},
+		{"secret et JWT_KID", func(*testing.T) JWTConfig { return JWTConfig{Secret: testJWTSecret, KID: "v2"} }, "v2", utils.AlgHS256, ""},
+		{"fichier prioritaire sur le secret", func(t *testing.T) JWTConfig {
+			return JWTConfig{KeysFile: writeKeysFile(t, rotation), Secret: testJWTSecret, KID: "v2"}
+		}, "2026-10", utils.AlgEdDSA, ""},
+		{"fichier illisible, pas de repli sur le secret", func(t *testing.T) JWTConfig {
+			return JWTConfig{KeysFile: filepath.Join(t.TempDir(), "absent.json"), Secret: testJWTSecret}
+		}, "", "", "lecture de"},
+		{"aucune source", func(*testing.T) JWTConfig { return JWTConfig{} }, "", "", "ni JWT_KEYS_FILE ni JWT_SECRET"},
+		{"secret trop court", func(*testing.T) JWTConfig { return JWTConfig{Secret: "court"} }, "", "", "trop court"},
+		{"algorithme déclaré différent de la clé", func(t *testing.T) JWTConfig {
+			return JWTConfig{KeysFile: writeKeysFile(t, `{"active_kid": "k", "keys": [{"kid": "k", "alg": "RS256", "key_file": "{pem}"}]}`)}
+		}, "", "", "algorithme \"RS256\" déclaré"},
+		{"secret avec un algorithme asymétrique", func(t *testing.T) JWTConfig {
+			return JWTConfig{KeysFile: writeKeysFile(t, `{"active_kid": "k", "keys": [{"kid": "k", "alg": "EdDSA", "secret": "`+testJWTSecret+`"}]}`)}
+		}, "", "", "qu'avec HS256"},
+		{"entrée sans clé", func(t *testing.T) JWTConfig {
+			return JWTConfig{KeysFile: writeKeysFile(t, `{"active_kid": "k", "keys": [{"kid": "k"}]}`)}
+		}, "", "", "'secret' ou 'key_file' requis"},
+		{"JSON invalide", func(t *testing.T) JWTConfig { return JWTConfig{KeysFile: writeKeysFile(t, `{"keys": `)} }, "", "", "format JSON invalide"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys, err := LoadJWTKeySet(tc.cfg(t))
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("erreur %v, attendu %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadJWTKeySet: %v", err)
+			}
+			if kid, alg := signingKID(t, keys); kid != tc.kid || alg != tc.alg {
+				t.Fatalf("clé active %q (%s), attendu %q (%s)", kid, alg, tc.kid, tc.alg)
+			}
+		})
+	}
+}
+
+func TestLoadJWTKeySetKeepsRotatedKeysForVerification(t *testing.T) {
+	previous, err := LoadJWTKeySet(JWTConfig{Secret: testJWTSecret, KID: "2026-04"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldToken, err := utils.GenerateJWT(previous, uuid.New(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := LoadJWTKeySet(JWTConfig{KeysFile: writeKeysFile(t, `{"active_kid": "2026-10", "keys": [
+		{"kid": "2026-10", "key_file": "{pem}"},
+		{"kid": "2026-04", "secret": "`+testJWTSecret+`"}
+	]}`)})
+	if err != nil {
+		t.Fatalf("LoadJWTKeySet: %v", err)
+	}
+	if _, err := utils.VerifyJWT(keys, oldToken); err != nil {
+		t.Fatalf("token de l'ancienne clé refusé: %v", err)
+	}
+	// Seule la clé asymétrique est publiée
+	if jwks := keys.JWKS(); len(jwks) != 1 || jwks[0].Kid != "2026-10" {
+		t.Fatalf("JWKS: %+v", jwks)
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/Azertdev/FiberTest/internal/adapters"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// LoadOAuthProviders retourne les fournisseurs OpenID Connect configurés et la clé de chiffrement
+// des refresh tokens. Google est activé par GOOGLE_CLIENT_ID, GOOGLE_CLIENT_SECRET et GOOGLE_REDIRECT_URL ;
+// OAUTH_ENCRYPTION_KEY (32 octets en base64) est alors obligatoire.
+func LoadOAuthProviders(cfg OAuthConfig) ([]adapters.OIDCProviderConfig, *utils.SecretBox, error) {
+	var providers []adapters.OIDCProviderConfig
+	if cfg.GoogleClientID != "" {
+		if cfg.GoogleClientSecret == "" || cfg.GoogleRedirectURL == "" {
+			return nil, nil, errors.New("GOOGLE_CLIENT_SECRET et GOOGLE_REDIRECT_URL sont requis avec GOOGLE_CLIENT_ID")
+		}
+		providers = append(providers, adapters.NewGoogleOIDCConfig(cfg.GoogleClientID, cfg.GoogleClientSecret, cfg.GoogleRedirectURL))
+	}
+	if len(providers) == 0 {
+		return nil, nil, nil
+	}
+
+	encodedKey := cfg.EncryptionKey
+	if encodedKey == "" {
+		return nil, nil, errors.New("OAUTH_ENCRYPTION_KEY est requis lorsqu'un fournisseur OAuth est configuré")
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("OAUTH_ENCRYPTION_KEY invalide (base64 attendu): %w", err)
+	}
+	secretBox, err := utils.NewSecretBox(key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("OAUTH_ENCRYPTION_KEY invalide: %w", err)
+	}
+	return providers, secretBox, nil
+}
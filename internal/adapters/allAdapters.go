@@ -5,9 +5,9 @@ type AllAdapter struct{
 	YoutubeAdapter YouTubeAdapter
 }
 
-func NewAllAdapter(groqApiKey string, YoutubeApiKey string) AllAdapter{
+func NewAllAdapter(groqApiKey string, YoutubeApiKey string, groqOptions GroqOptions, youtubeOptions YouTubeOptions) AllAdapter{
 	return AllAdapter{
-		GroqAdapter: NewGroqAdapter(groqApiKey),
-		YoutubeAdapter: NewYouTubeAdapter(YoutubeApiKey),
+		GroqAdapter: NewGroqAdapter(groqApiKey, groqOptions),
+		YoutubeAdapter: NewYouTubeAdapter(YoutubeApiKey, youtubeOptions),
 	}
 }
\ No newline at end of file
@@ -14,23 +14,50 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Azertdev/FiberTest/internal/utils"
 )
 
 type GroqAdapter interface {
 	AnalyzeComments(ctx context.Context, comments []string, videoTranscript string) (string, error)
 	SummarizeTranscript(ctx context.Context, transcript string) (string, error)
+	SummarizeInsights(ctx context.Context, input utils.MetaSummaryInput) (string, error)
+}
+
+// GroqOptions : paramètres du modèle et des appels, réglables par environnement (voir config.Load)
+type GroqOptions struct {
+	Model                  string        // Nom du modèle Groq à utiliser (ex: "llama3-70b-8192")
+	BaseURL                string        // Endpoint chat completions compatible OpenAI
+	Timeout                time.Duration // Timeout HTTP d'un appel
+	AnalysisTemperature    float64
+	AnalysisMaxTokens      int
+	SummaryTemperature     float64 // Plus bas : plus factuel pour le résumé
+	SummaryMaxTokens       int     // Plus petit que pour l'analyse des commentaires
+	MetaSummaryTemperature float64 // Synthèse globale des lots (étape de réduction)
+	MetaSummaryMaxTokens   int
+}
+
+var DefaultGroqOptions = GroqOptions{
+	Model:                  "deepseek-r1-distill-llama-70b",
+	BaseURL:                "https://api.groq.com/openai/v1/chat/completions",
+	Timeout:                90 * time.Second,
+	AnalysisTemperature:    0.5,
+	AnalysisMaxTokens:      4096,
+	SummaryTemperature:     0.3,
+	SummaryMaxTokens:       768,
+	MetaSummaryTemperature: 0.3,
+	MetaSummaryMaxTokens:   2048, // les modèles de raisonnement consomment des tokens avant de répondre
 }
 
 // Structure qui implémente services.GroqAdapter
 type groqAdapter struct {
-	apiKey string
-	client *http.Client // Garde un client HTTP réutilisable
-	model  string       // Nom du modèle Groq à utiliser (ex: "llama3-70b-8192")
+	apiKey  string
+	client  *http.Client // Garde un client HTTP réutilisable
+	options GroqOptions
 }
 
 // Constructeur pour groqAdapter
 // Prend la clé API et retourne l'INTERFACE services.GroqAdapter
-func NewGroqAdapter(apiKey string) GroqAdapter {
+func NewGroqAdapter(apiKey string, options GroqOptions) GroqAdapter {
 	if apiKey == "" {
 		// Ne pas retourner d'erreur ici, car main.go vérifie déjà.
 		// Si vous voulez une double vérification, retournez (nil, error).
@@ -40,9 +67,9 @@ func NewGroqAdapter(apiKey string) GroqAdapter {
 	//deepseek-r1-distill-llama-70b
 	//llama-guard-3-8b
 	return &groqAdapter{
-		apiKey: apiKey,
-		client: &http.Client{Timeout: 90 * time.Second}, // Timeout configurable
-		model:  "deepseek-r1-distill-llama-70b",                 // Modèle par défaut, pourrait être configurable
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: options.Timeout},
+		options: options,
 	}
 }
 
@@ -71,7 +98,7 @@ Tu es un analyste expert... Ton objectif est d'extraire des informations clés e
 """
 
 # DONNÉES À ANALYSER : COMMENTAIRES UTILISATEURS
-(Chaque ligne est un commentaire incluant l'auteur et le texte exact)
+(Chaque ligne est un commentaire numéroté entre crochets, incluant l'auteur et le texte exact)
 - %s
 
 # INSTRUCTIONS D'ANALYSE ET FORMAT DE SORTIE OBLIGATOIRE
@@ -110,6 +137,12 @@ Liste les **principaux** mots-clés ou courtes expressions (1-3 mots) les plus f
 - MotClé2
 - Expression Clé 3
 
+## 8. Polarité des Commentaires
+Pour **chaque** commentaire fourni, écris une ligne "- [numéro] score étiquette" : le numéro entre crochets qui précède le commentaire, un score de -1 (très négatif) à 1 (très positif) et une étiquette parmi positif, neutre, négatif, mixte (mixte : éloges et critiques dans le même commentaire).
+- [1] 0.8 positif
+- [2] -0.4 négatif
+- [3] 0.1 mixte
+
 # RÈGLES IMPORTANTES
 - **Chaque commentaire fourni doit apparaître dans EXACTEMENT UNE des sections 3, 4, 5 ou 6.** Choisis la catégorie la plus pertinente même si le commentaire est neutre ou ambigu.
 - Respecte SCRUPULEUSEMENT le format...
@@ -118,83 +151,9 @@ Liste les **principaux** mots-clés ou courtes expressions (1-3 mots) les plus f
 `, videoTranscript, commentsFormatted)
 	// --- Fin du Prompt ---
 
-	payload := map[string]any{
-		"model": ga.model, // Utilise le modèle configuré
-		"messages": []map[string]string{
-			{"role": "user", "content": prompt},
-		},
-		"temperature": 0.5, // Configurable si besoin
-		"max_tokens":  4096, // Configurable si besoin
-	}
-
-	body, err := json.Marshal(payload)
-	if err != nil {
-		return "", fmt.Errorf("erreur lors du marshalling du payload Groq: %w", err)
-	}
-
-	// Création de la requête avec le contexte
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.groq.com/openai/v1/chat/completions", bytes.NewBuffer(body))
-	if err != nil {
-		return "", fmt.Errorf("erreur lors de la création de la requête Groq: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+ga.apiKey) // Utilise la clé API stockée
-
-	// Exécution de la requête via le client HTTP stocké
-	resp, err := ga.client.Do(req)
-	if err != nil {
-		// Vérifier si l'erreur est due à l'annulation du contexte
-		if errors.Is(err, context.Canceled) {
-			return "", errors.New("appel à l'API Groq annulé")
-		}
-		if errors.Is(err, context.DeadlineExceeded) {
-			return "", errors.New("timeout lors de l'appel à l'API Groq")
-		}
-		return "", fmt.Errorf("erreur lors de l'appel API Groq: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("erreur lors de la lecture de la réponse Groq: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("erreur API Groq (%d): %s", resp.StatusCode, string(respBodyBytes))
-	}
-
-	var groqResponse struct {
-		Choices []struct {
-			Message struct {
-				Content string `json:"content"`
-			} `json:"message"`
-		} `json:"choices"`
-		Usage struct {
-			PromptTokens     int `json:"prompt_tokens"`
-			CompletionTokens int `json:"completion_tokens"`
-			TotalTokens      int `json:"total_tokens"`
-		} `json:"usage"`
-	}
-
-	if err := json.Unmarshal(respBodyBytes, &groqResponse); err != nil {
-		// Logguer la réponse brute peut aider au débogage si le JSON est invalide
-		// log.Printf("DEBUG: Réponse Groq invalide: %s", string(respBodyBytes))
-		return "", fmt.Errorf("erreur lors du décodage de la réponse Groq: %w", err)
-	}
-
-	if len(groqResponse.Choices) == 0 || groqResponse.Choices[0].Message.Content == "" {
-		// log.Printf("DEBUG: Réponse Groq vide reçue: %+v", groqResponse)
-		return "", errors.New("aucune réponse ('content') reçue de Groq")
-	}
-
-	// Logguer l'usage peut être utile pour le suivi des coûts/limites
-	// log.Printf("DEBUG: Usage Groq: %+v", groqResponse.Usage)
-
-	return groqResponse.Choices[0].Message.Content, nil
+	return ga.chatCompletion(ctx, prompt, ga.options.AnalysisTemperature, ga.options.AnalysisMaxTokens)
 }
 
-
-
 func (ga *groqAdapter) SummarizeTranscript(ctx context.Context, transcript string) (string, error) {
 	if ga.apiKey == "" {
 		return "", errors.New("GroqAdapter non configuré avec une clé API")
@@ -246,79 +205,134 @@ Liste les questions rhétoriques ou directes posées par le locuteur DANS LA VID
 `, transcript) // Injection de la transcription brute (ou tronquée si nécessaire avant l'appel)
 	// --- Fin du Prompt ---
 
-	// Préparation du payload pour l'API Groq
+	log.Printf("INFO: Adapter: Appel API Groq pour résumer la transcription...")
+	// max_tokens ajusté à la longueur attendue du résumé (évite l'erreur 400)
+	summary, err := ga.chatCompletion(ctx, prompt, ga.options.SummaryTemperature, ga.options.SummaryMaxTokens)
+	if err != nil {
+		return "", fmt.Errorf("résumé de la transcription: %w", err)
+	}
+	log.Printf("INFO: Adapter: Résumé de transcription généré avec succès.")
+	return summary, nil
+}
+
+// SummarizeInsights produit le sentiment et le résumé globaux d'une vidéo à partir des résumés
+// des lots analysés, de la répartition de leurs sentiments et des mots-clés fusionnés.
+// La réponse reprend les sections 1 et 2 du format d'analyse (voir utils.ParseMetaSummary).
+func (ga *groqAdapter) SummarizeInsights(ctx context.Context, input utils.MetaSummaryInput) (string, error) {
+	if ga.apiKey == "" {
+		return "", errors.New("GroqAdapter non configuré avec une clé API")
+	}
+	if len(input.ChunkSummaries) == 0 {
+		return "", errors.New("aucun résumé de lot fourni pour la synthèse globale")
+	}
+
+	var summaries strings.Builder
+	for i, summary := range input.ChunkSummaries {
+		fmt.Fprintf(&summaries, "- Lot %d : %s\n", i+1, strings.ReplaceAll(summary, "\n", " "))
+	}
+	var sentiments strings.Builder
+	for _, count := range input.SentimentCounts {
+		fmt.Fprintf(&sentiments, "- %s : %d lot(s)\n", count.Sentiment, count.Chunks)
+	}
+	keywords := "Aucun"
+	if len(input.TopKeywords) > 0 {
+		keywords = strings.Join(input.TopKeywords, ", ")
+	}
+
+	prompt := fmt.Sprintf(`
+# RÔLE ET OBJECTIF
+Tu es un analyste expert des commentaires YouTube. Les %d commentaires d'une vidéo ont été analysés par lots ; tu reçois le résultat de chaque lot. Ton objectif est d'en faire UNE synthèse globale cohérente, représentative de l'ensemble des commentaires et pas seulement du premier lot.
+
+# RÉSUMÉS DES LOTS
+%s
+# SENTIMENT DE CHAQUE LOT
+%s
+# MOTS-CLÉS LES PLUS FRÉQUENTS
+%s
+
+# FORMAT DE SORTIE OBLIGATOIRE
+Réponds UNIQUEMENT avec les deux sections Markdown suivantes, avec exactement ces titres :
+
+## 1. Sentiment Général
+Décris en une phrase concise le sentiment dominant sur l'ensemble des lots, en tenant compte de leur répartition (ex: Majoritairement Positif, Partagé, Négatif avec des attentes fortes).
+
+## 2. Résumé Général des Commentaires
+Rédige un court paragraphe (3-5 phrases maximum) qui fusionne les thèmes principaux de tous les lots, sans les énumérer lot par lot.
+
+# RÈGLES IMPORTANTES
+- Base-toi EXCLUSIVEMENT sur les informations ci-dessus.
+- N'invente aucun commentaire ni aucun chiffre.
+`, input.CommentCount, summaries.String(), sentiments.String(), keywords)
+
+	log.Printf("INFO: Adapter: Appel API Groq pour la synthèse globale de %d lots...", len(input.ChunkSummaries))
+	return ga.chatCompletion(ctx, prompt, ga.options.MetaSummaryTemperature, ga.options.MetaSummaryMaxTokens)
+}
+
+// chatCompletion envoie un prompt utilisateur unique et retourne le contenu de la réponse.
+// Les erreurs de transport (annulation, timeout, réseau) restent accessibles par errors.Is / errors.As.
+func (ga *groqAdapter) chatCompletion(ctx context.Context, prompt string, temperature float64, maxTokens int) (string, error) {
 	payload := map[string]any{
-		// Utiliser un modèle potentiellement plus petit/rapide si suffisant ?
-		"model": ga.model, // ou "llama3-8b-8192" ?
+		"model": ga.options.Model,
 		"messages": []map[string]string{
 			{"role": "user", "content": prompt},
 		},
-		"temperature": 0.3, // Plus factuel pour le résumé
-		// Ajuster max_tokens pour la longueur attendue du résumé ET pour éviter l'erreur 400
-		"max_tokens":  768, // Exemple, plus petit que pour l'analyse des commentaires
+		"temperature": temperature,
+		"max_tokens":  maxTokens,
 	}
-
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", fmt.Errorf("erreur marshalling payload résumé Groq: %w", err)
+		return "", fmt.Errorf("erreur marshalling payload Groq: %w", err)
 	}
 
-	// Création et exécution de la requête HTTP
-	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.groq.com/openai/v1/chat/completions", bytes.NewBuffer(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", ga.options.BaseURL, bytes.NewBuffer(body))
 	if err != nil {
-		return "", fmt.Errorf("erreur création requête résumé Groq: %w", err)
+		return "", fmt.Errorf("erreur création requête Groq: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+ga.apiKey)
 
-	log.Printf("INFO: Adapter: Appel API Groq pour résumer la transcription...")
 	resp, err := ga.client.Do(req)
 	if err != nil {
-		if errors.Is(err, context.Canceled) { return "", errors.New("résumé Groq annulé par contexte") }
-		if errors.Is(err, context.DeadlineExceeded) { return "", errors.New("timeout résumé Groq") }
-		return "", fmt.Errorf("erreur appel API résumé Groq: %w", err)
+		if errors.Is(err, context.Canceled) {
+			return "", fmt.Errorf("appel à l'API Groq annulé: %w", err)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return "", fmt.Errorf("timeout lors de l'appel à l'API Groq: %w", err)
+		}
+		return "", fmt.Errorf("erreur lors de l'appel API Groq: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("erreur lecture réponse résumé Groq: %w", err)
+		return "", fmt.Errorf("erreur lors de la lecture de la réponse Groq: %w", err)
 	}
-
-	// Gestion de la réponse et des erreurs API
 	if resp.StatusCode != http.StatusOK {
-		log.Printf("ERROR: Réponse brute erreur API résumé Groq (%d): %s", resp.StatusCode, string(respBodyBytes))
-		// Tenter de parser l'erreur Groq pour un message plus clair
-		var errorResponse struct { Error struct { Message string `json:"message"`; Type string `json:"type"`; Code string `json:"code"` }}
+		// Message de l'API s'il est lisible, sinon le corps brut
+		var errorResponse struct {
+			Error struct {
+				Message string `json:"message"`
+				Type    string `json:"type"`
+			} `json:"error"`
+		}
 		if json.Unmarshal(respBodyBytes, &errorResponse) == nil && errorResponse.Error.Message != "" {
-            return "", fmt.Errorf("erreur API résumé Groq (%d - %s): %s", resp.StatusCode, errorResponse.Error.Type, errorResponse.Error.Message)
-        }
-		// Sinon, erreur générique
-		return "", fmt.Errorf("erreur API résumé Groq (%d)", resp.StatusCode)
+			return "", fmt.Errorf("erreur API Groq (%d - %s): %s", resp.StatusCode, errorResponse.Error.Type, errorResponse.Error.Message)
+		}
+		return "", fmt.Errorf("erreur API Groq (%d): %s", resp.StatusCode, string(respBodyBytes))
 	}
 
-	// Décodage de la réponse JSON de succès
 	var groqResponse struct {
 		Choices []struct {
 			Message struct {
 				Content string `json:"content"`
 			} `json:"message"`
 		} `json:"choices"`
-		// Usage non traité ici, mais pourrait l'être
 	}
-
 	if err := json.Unmarshal(respBodyBytes, &groqResponse); err != nil {
-		log.Printf("ERROR: Impossible de décoder la réponse Groq pour le résumé. Body: %s", string(respBodyBytes))
-		return "", fmt.Errorf("erreur décodage réponse succès résumé Groq: %w", err)
+		return "", fmt.Errorf("erreur lors du décodage de la réponse Groq: %w", err)
 	}
-
-	// Vérifier si la réponse contient bien du contenu
 	if len(groqResponse.Choices) == 0 || groqResponse.Choices[0].Message.Content == "" {
-		log.Printf("WARN: Réponse Groq reçue pour le résumé mais sans contenu. Body: %s", string(respBodyBytes))
-		return "", errors.New("aucune réponse ('content') reçue de Groq pour le résumé")
+		return "", errors.New("aucune réponse ('content') reçue de Groq")
 	}
-
-	log.Printf("INFO: Adapter: Résumé de transcription généré avec succès.")
-	// Retourne le contenu du résumé généré par Groq
 	return groqResponse.Choices[0].Message.Content, nil
-} // --- FIN NOUVELLE FONCTION ---
\ No newline at end of file
+}
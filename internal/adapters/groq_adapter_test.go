@@ -0,0 +1,122 @@
+package adapters
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/fakes"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+func testGroqOptions(api *fakes.GroqAPI) GroqOptions {
+	options := DefaultGroqOptions
+	options.BaseURL = api.URL()
+	options.Timeout = 5 * time.Second
+	return options
+}
+
+func TestGroqAdapterAnalyzeCommentsSendsPromptAndReturnsContent(t *testing.T) {
+	api := fakes.NewGroqAPI(t)
+	api.OnRequest(func(call int, request fakes.GroqRequest) (int, string) {
+		return http.StatusOK, "## 1. Sentiment Général\nPositif"
+	})
+	options := testGroqOptions(api)
+
+	adapter := NewGroqAdapter(fakes.GroqAPIKey, options)
+	content, err := adapter.AnalyzeComments(t.Context(), []string{"Super vidéo", "Et la suite ?"}, "transcription")
+	if err != nil {
+		t.Fatalf("AnalyzeComments: %v", err)
+	}
+	if content != "## 1. Sentiment Général\nPositif" {
+		t.Fatalf("contenu inattendu: %q", content)
+	}
+
+	requests := api.Requests()
+	if len(requests) != 1 {
+		t.Fatalf("%d requête(s) envoyée(s)", len(requests))
+	}
+	request := requests[0]
+	if request.Model != options.Model || request.Temperature != options.AnalysisTemperature || request.MaxTokens != options.AnalysisMaxTokens {
+		t.Fatalf("paramètres du modèle inattendus: %+v", request)
+	}
+	for _, expected := range []string{"Super vidéo", "Et la suite ?", "transcription", "## 7. Mots-clés et Thèmes Fréquents"} {
+		if !strings.Contains(request.Prompt(), expected) {
+			t.Fatalf("prompt sans %q", expected)
+		}
+	}
+}
+
+func TestGroqAdapterSummarizeInsightsSendsEveryChunk(t *testing.T) {
+	api := fakes.NewGroqAPI(t)
+	options := testGroqOptions(api)
+
+	adapter := NewGroqAdapter(fakes.GroqAPIKey, options)
+	_, err := adapter.SummarizeInsights(t.Context(), utils.MetaSummaryInput{
+		CommentCount:    120,
+		ChunkSummaries:  []string{"Le son plaît.", "Demandes de suite."},
+		SentimentCounts: []utils.SentimentCount{{Sentiment: "Positif", Chunks: 2}},
+		TopKeywords:     []string{"son", "suite"},
+	})
+	if err != nil {
+		t.Fatalf("SummarizeInsights: %v", err)
+	}
+
+	request := api.Requests()[0]
+	if request.Temperature != options.MetaSummaryTemperature || request.MaxTokens != options.MetaSummaryMaxTokens {
+		t.Fatalf("paramètres du modèle inattendus: %+v", request)
+	}
+	for _, expected := range []string{"120 commentaires", "Lot 1 : Le son plaît.", "Lot 2 : Demandes de suite.", "Positif : 2 lot(s)", "son, suite", "## 2. Résumé Général des Commentaires"} {
+		if !strings.Contains(request.Prompt(), expected) {
+			t.Fatalf("prompt sans %q", expected)
+		}
+	}
+
+	if _, err := adapter.SummarizeInsights(t.Context(), utils.MetaSummaryInput{}); err == nil || len(api.Requests()) != 1 {
+		t.Fatal("une synthèse sans résumé de lot ne doit pas appeler l'API")
+	}
+}
+
+func TestGroqAdapterSummarizeTranscriptUsesSummarySettings(t *testing.T) {
+	api := fakes.NewGroqAPI(t)
+	api.OnRequest(func(call int, request fakes.GroqRequest) (int, string) {
+		return http.StatusOK, "## 1. Résumé Global\nTutoriel de montage."
+	})
+	options := testGroqOptions(api)
+
+	adapter := NewGroqAdapter(fakes.GroqAPIKey, options)
+	summary, err := adapter.SummarizeTranscript(t.Context(), "Aujourd'hui on monte une vidéo.")
+	if err != nil || summary != "## 1. Résumé Global\nTutoriel de montage." {
+		t.Fatalf("SummarizeTranscript: %q, %v", summary, err)
+	}
+	request := api.Requests()[0]
+	if request.Temperature != options.SummaryTemperature || request.MaxTokens != options.SummaryMaxTokens || !strings.Contains(request.Prompt(), "on monte une vidéo") {
+		t.Fatalf("requête de résumé inattendue: %+v", request)
+	}
+
+	// Transcription indisponible : aucun appel
+	if _, err := adapter.SummarizeTranscript(t.Context(), ""); err != nil || len(api.Requests()) != 1 {
+		t.Fatalf("transcription vide: %v, %d requête(s)", err, len(api.Requests()))
+	}
+}
+
+func TestGroqAdapterSurfacesAPIErrors(t *testing.T) {
+	api := fakes.NewGroqAPI(t)
+	api.OnRequest(func(call int, request fakes.GroqRequest) (int, string) {
+		return http.StatusTooManyRequests, "Rate limit reached"
+	})
+
+	adapter := NewGroqAdapter(fakes.GroqAPIKey, testGroqOptions(api))
+	if _, err := adapter.AnalyzeComments(t.Context(), []string{"c"}, ""); err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("erreur 429 attendue, obtenu %v", err)
+	}
+	if _, err := adapter.SummarizeTranscript(t.Context(), "transcription"); err == nil || !strings.Contains(err.Error(), "Rate limit reached") {
+		t.Fatalf("message de l'API attendu pour le résumé, obtenu %v", err)
+	}
+
+	wrongKey := NewGroqAdapter("mauvaise-cle", testGroqOptions(api))
+	if _, err := wrongKey.AnalyzeComments(t.Context(), []string{"c"}, ""); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("erreur 401 attendue, obtenu %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// internal/adapters/lexicon_adapter.go
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// Analyseur hors ligne : même contrat que l'adapter Groq (réponse Markdown relue par
+// utils.ParseInsightResponse), calculé par utils.AnalyzeLexicon. Gratuit, déterministe et sans
+// réseau : repli quand Groq est indisponible et moteur des plans sans analyse IA.
+
+// offlineKeywordLimit : mots-clés extraits par lot (le service les reclasse sur l'ensemble des commentaires)
+const offlineKeywordLimit = 10
+
+// OfflineTranscriptSummary : résumé de transcription de l'analyse hors ligne (pas de modèle pour le rédiger)
+const OfflineTranscriptSummary = "Résumé non généré (analyse hors ligne)."
+
+// Commentaire formaté par le service : `[3] Auteur: Alice | Date: 2024-01-02 | Commentaire: "Super !"`
+var (
+	commentNumber  = regexp.MustCompile(`^\[(\d+)\]\s*`)
+	commentAuthor  = regexp.MustCompile(`Auteur:\s*(.*?)\s*\|`)
+	commentContent = regexp.MustCompile(`(?s)Commentaire:\s*"(.*)"\s*$`)
+)
+
+type lexiconAdapter struct{}
+
+func NewLexiconAdapter() GroqAdapter {
+	return &lexiconAdapter{}
+}
+
+type lexiconComment struct {
+	index  int
+	author string
+	text   string
+}
+
+func (la *lexiconAdapter) AnalyzeComments(ctx context.Context, comments []string, videoTranscript string) (string, error) {
+	if len(comments) == 0 {
+		return "", errors.New("aucun commentaire fourni pour l'analyse hors ligne")
+	}
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	insight := utils.ParsedInsight{CommentScores: map[int]utils.CommentScore{}}
+	emotions := map[string]float64{}
+	var polaritySum float64
+	var positives, negatives int
+	texts := make([]string, len(comments))
+	for i, raw := range comments {
+		comment := parseFormattedComment(raw, i+1)
+		texts[i] = comment.text
+		result := utils.AnalyzeLexicon(comment.text)
+		insight.CommentScores[comment.index] = result.Score
+		polaritySum += result.Score.Polarity
+		for emotion, intensity := range result.Emotions {
+			emotions[emotion] += intensity
+		}
+
+		// Chaque commentaire dans exactement une des sections 3 à 6, comme l'exige le prompt
+		item := comment.text
+		if comment.author != "" {
+			item = comment.author + ": " + comment.text
+		}
+		switch {
+		case strings.Contains(comment.text, "?"):
+			insight.QuestionComments = append(insight.QuestionComments, item)
+		case result.Score.Label == models.SentimentNegative:
+			insight.NegativeComments = append(insight.NegativeComments, item)
+		case result.Score.Label == models.SentimentPositive:
+			insight.TopComments = append(insight.TopComments, item)
+		default:
+			insight.FeedbackComments = append(insight.FeedbackComments, item)
+		}
+		switch result.Score.Label {
+		case models.SentimentPositive:
+			positives++
+		case models.SentimentNegative:
+			negatives++
+		}
+	}
+
+	count := len(comments)
+	insight.Keywords = utils.KeywordNames(utils.ExtractKeywords(texts, offlineKeywordLimit))
+	insight.Sentiment = lexiconSentiment(polaritySum/float64(count), positives, negatives, count)
+	insight.Summary = fmt.Sprintf("Analyse hors ligne de %d commentaires : %d positifs, %d négatifs, %d neutres ou partagés, dont %d questions.",
+		count, positives, negatives, count-positives-negatives, len(insight.QuestionComments))
+	if dominant := utils.DominantEmotions(emotions); len(dominant) > 0 {
+		insight.Summary += " Émotions dominantes : " + strings.Join(dominant[:min(2, len(dominant))], ", ") + "."
+	}
+	return utils.RenderInsightMarkdown(insight), nil
+}
+
+func (la *lexiconAdapter) SummarizeTranscript(ctx context.Context, transcript string) (string, error) {
+	return OfflineTranscriptSummary, nil
+}
+
+// SummarizeInsights : sentiment le plus fréquent parmi les lots et résumé construit à partir des
+// comptes et des mots-clés (pas de rédaction possible sans modèle)
+func (la *lexiconAdapter) SummarizeInsights(ctx context.Context, input utils.MetaSummaryInput) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	sentiment := "Neutre"
+	var shares []string
+	for _, count := range input.SentimentCounts {
+		shares = append(shares, fmt.Sprintf("%s (%d)", count.Sentiment, count.Chunks))
+	}
+	if len(input.SentimentCounts) > 0 {
+		sentiment = input.SentimentCounts[0].Sentiment
+	}
+	summary := fmt.Sprintf("Synthèse hors ligne de %d commentaires analysés en %d lots.", input.CommentCount, len(input.ChunkSummaries))
+	if len(shares) > 0 {
+		summary += " Sentiment des lots : " + strings.Join(shares, ", ") + "."
+	}
+	if len(input.TopKeywords) > 0 {
+		summary += " Thèmes fréquents : " + strings.Join(input.TopKeywords[:min(5, len(input.TopKeywords))], ", ") + "."
+	}
+	return utils.RenderInsightMarkdown(utils.ParsedInsight{Sentiment: sentiment, Summary: summary}), nil
+}
+
+// lexiconSentiment décrit le sentiment d'un lot à partir de la polarité moyenne ; "Partagé" quand
+// avis positifs et négatifs représentent chacun au moins 30 % des commentaires
+func lexiconSentiment(meanPolarity float64, positives, negatives, count int) string {
+	switch {
+	case positives*10 >= count*3 && negatives*10 >= count*3:
+		return "Partagé"
+	case meanPolarity >= utils.NeutralPolarityBand/2:
+		return "Majoritairement positif"
+	case meanPolarity <= -utils.NeutralPolarityBand/2:
+		return "Majoritairement négatif"
+	default:
+		return "Neutre"
+	}
+}
+
+// parseFormattedComment extrait le numéro, l'auteur et le texte d'un commentaire formaté par le
+// service ; un texte libre est analysé tel quel, numéroté par sa position
+func parseFormattedComment(raw string, position int) lexiconComment {
+	comment := lexiconComment{index: position, text: raw}
+	if match := commentNumber.FindStringSubmatch(raw); match != nil {
+		if index, err := strconv.Atoi(match[1]); err == nil {
+			comment.index = index
+		}
+		comment.text = raw[len(match[0]):]
+	}
+	if match := commentContent.FindStringSubmatch(raw); match != nil {
+		comment.text = match[1]
+		if author := commentAuthor.FindStringSubmatch(raw); author != nil {
+			comment.author = author[1]
+		}
+	}
+	// Une ligne par élément de liste dans la réponse Markdown
+	comment.text = strings.Join(strings.Fields(comment.text), " ")
+	return comment
+}
@@ -0,0 +1,85 @@
+package adapters
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+func TestLexiconAdapterAnalyzeCommentsFollowsTheAnalysisContract(t *testing.T) {
+	comments := []string{
+		`[1] Auteur: Alice | Date: 2024-01-02 | Commentaire: "Super vidéo, merci beaucoup !"`,
+		`[2] Auteur: Bob | Date: 2024-01-02 | Commentaire: "Le son est horrible,
+vraiment nul."`,
+		`[3] Auteur: Chloé | Date: 2024-01-03 | Commentaire: "Tu utilises quel micro ?"`,
+		`[4] Auteur: David | Date: 2024-01-03 | Commentaire: "Tourné à Lyon en 2023"`,
+	}
+	raw, err := NewLexiconAdapter().AnalyzeComments(t.Context(), comments, "")
+	if err != nil {
+		t.Fatalf("AnalyzeComments: %v", err)
+	}
+	parsed := utils.ParseInsightResponse(raw)
+	if len(parsed.Warnings) != 0 {
+		t.Fatalf("réponse hors format: %v\n%s", parsed.Warnings, raw)
+	}
+
+	sections := map[string][]string{
+		"positifs":  parsed.TopComments,
+		"négatifs":  parsed.NegativeComments,
+		"questions": parsed.QuestionComments,
+		"feedbacks": parsed.FeedbackComments,
+	}
+	expected := map[string]string{
+		"Alice: Super vidéo, merci beaucoup !":    "positifs",
+		"Bob: Le son est horrible, vraiment nul.": "négatifs",
+		"Chloé: Tu utilises quel micro ?":         "questions",
+		"David: Tourné à Lyon en 2023":            "feedbacks",
+	}
+	for item, section := range expected {
+		for name, items := range sections {
+			if found := slices.Contains(items, item); found != (name == section) {
+				t.Errorf("%q dans la section %s: %v", item, name, found)
+			}
+		}
+	}
+
+	if len(parsed.CommentScores) != len(comments) {
+		t.Fatalf("polarités: %v", parsed.CommentScores)
+	}
+	if parsed.CommentScores[1].Label != models.SentimentPositive || parsed.CommentScores[2].Label != models.SentimentNegative {
+		t.Fatalf("étiquettes inattendues: %v", parsed.CommentScores)
+	}
+	if parsed.Sentiment == "" || !strings.Contains(parsed.Summary, "4 commentaires") {
+		t.Fatalf("sentiment %q, résumé %q", parsed.Sentiment, parsed.Summary)
+	}
+
+	again, _ := NewLexiconAdapter().AnalyzeComments(t.Context(), comments, "")
+	if again != raw {
+		t.Fatal("l'analyse hors ligne doit être déterministe")
+	}
+}
+
+func TestLexiconAdapterSummaries(t *testing.T) {
+	adapter := NewLexiconAdapter()
+	if summary, err := adapter.SummarizeTranscript(t.Context(), "transcription"); err != nil || summary != OfflineTranscriptSummary {
+		t.Fatalf("SummarizeTranscript: %q, %v", summary, err)
+	}
+
+	input := utils.MetaSummaryInput{
+		CommentCount:    120,
+		ChunkSummaries:  []string{"a", "b", "c"},
+		SentimentCounts: []utils.SentimentCount{{Sentiment: "Majoritairement positif", Chunks: 2}, {Sentiment: "Neutre", Chunks: 1}},
+		TopKeywords:     []string{"micro", "montage"},
+	}
+	raw, err := adapter.SummarizeInsights(t.Context(), input)
+	if err != nil {
+		t.Fatalf("SummarizeInsights: %v", err)
+	}
+	sentiment, summary, err := utils.ParseMetaSummary(raw)
+	if err != nil || sentiment != "Majoritairement positif" || !strings.Contains(summary, "micro, montage") {
+		t.Fatalf("synthèse: %q, %q, %v", sentiment, summary, err)
+	}
+}
@@ -0,0 +1,64 @@
+// internal/adapters/mailer_adapter.go
+package adapters
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MailerAdapter délivre les emails transactionnels (vérification d'adresse, réinitialisation...)
+type MailerAdapter interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// --- Mailer "log" : écrit l'email dans les logs du serveur (développement local) ---
+
+type logMailer struct{}
+
+func NewLogMailer() MailerAdapter {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("EMAIL: [To: %s] [Subject: %s]\n%s", to, subject, body)
+	return nil
+}
+
+// --- Mailer "file" : écrit chaque email dans un fichier .eml d'un répertoire ---
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+// NewFileMailer crée le répertoire de sortie si nécessaire
+func NewFileMailer(dir, from string) (MailerAdapter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("erreur création du répertoire des emails '%s': %w", dir, err)
+	}
+	return &fileMailer{dir: dir, from: from}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, to, subject, body string) error {
+	now := time.Now()
+	var message strings.Builder
+	fmt.Fprintf(&message, "From: %s\r\n", m.from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&message, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	message.WriteString(body)
+
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405"), uuid.NewString())
+	if err := os.WriteFile(filepath.Join(m.dir, name), []byte(message.String()), 0o600); err != nil {
+		return fmt.Errorf("erreur écriture de l'email: %w", err)
+	}
+	return nil
+}
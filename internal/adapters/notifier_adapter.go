@@ -0,0 +1,81 @@
+// internal/adapters/notifier_adapter.go
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// Canaux de diffusion des notifications : ils satisfont services.NotificationChannel, seul
+// contrat déclaré pour ces canaux.
+
+// --- Canal "log" : écrit la notification dans les logs du serveur ---
+
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Name() string { return "log" }
+
+func (n *LogNotifier) Send(ctx context.Context, notification models.Notification) error {
+	log.Printf("NOTIFICATION: [UserID: %s] [%s] %s", notification.UserID, notification.Type, notification.Message)
+	return nil
+}
+
+// --- Canal "webhook" : POST JSON vers une URL configurée (Slack, Discord, n8n...) ---
+
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+func (n *WebhookNotifier) Send(ctx context.Context, notification models.Notification) error {
+	payload := map[string]any{
+		"id":         notification.ID,
+		"user_id":    notification.UserID,
+		"type":       notification.Type,
+		"message":    notification.Message,
+		"created_at": notification.CreatedAt,
+		// Champ "text" pour la compatibilité avec les webhooks Slack/Mattermost
+		"text": notification.Message,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("erreur marshalling payload webhook: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", n.url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("erreur création requête webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("erreur appel webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook a répondu avec le statut %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+// internal/adapters/oidc_adapter.go
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+const GoogleIssuer = "https://accounts.google.com"
+
+// OIDCProviderAdapter implémente le flux "authorization code" avec PKCE d'un fournisseur OpenID Connect
+type OIDCProviderAdapter interface {
+	Name() string
+	// AuthCodeURL construit l'URL d'autorisation (challenge PKCE S256 dérivé de verifier)
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	// Exchange échange le code, vérifie l'ID token (signature, iss, aud, exp, nonce) et retourne l'identité
+	Exchange(ctx context.Context, code, verifier, nonce string) (*models.ExternalIdentity, error)
+	// TokenSource fournit des tokens d'accès renouvelés à partir d'un refresh token stocké
+	TokenSource(ctx context.Context, refreshToken string) (oauth2.TokenSource, error)
+}
+
+// OIDCProviderConfig décrit un client OpenID Connect. Les endpoints sont découverts via
+// <Issuer>/.well-known/openid-configuration.
+type OIDCProviderConfig struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	AuthParams   map[string]string // paramètres supplémentaires de l'URL d'autorisation
+	// IssuerAliases : autres valeurs de "iss" acceptées dans les ID tokens
+	IssuerAliases []string
+}
+
+// NewGoogleOIDCConfig : connexion Google avec accès en lecture à la chaîne YouTube.
+// access_type=offline + prompt=consent garantissent la délivrance d'un refresh token.
+func NewGoogleOIDCConfig(clientID, clientSecret, redirectURL string) OIDCProviderConfig {
+	return OIDCProviderConfig{
+		Name:         "google",
+		Issuer:       GoogleIssuer,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+		Scopes:       []string{"openid", "email", "profile", models.YouTubeReadOnlyScope},
+		AuthParams: map[string]string{
+			"access_type":            "offline",
+			"prompt":                 "consent",
+			"include_granted_scopes": "true",
+		},
+		// Google émet parfois des ID tokens avec "iss" sans schéma
+		IssuerAliases: []string{"accounts.google.com"},
+	}
+}
+
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcProvider struct {
+	config OIDCProviderConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *oidcDiscovery
+	keys      map[string]any
+	keysAt    time.Time
+}
+
+// Délai minimal entre deux rechargements du JWKS (kid inconnu après une rotation chez le fournisseur)
+const jwksRefreshInterval = time.Minute
+
+// NewOIDCProvider ne contacte pas le fournisseur : la découverte est faite au premier usage
+func NewOIDCProvider(config OIDCProviderConfig, client *http.Client) OIDCProviderAdapter {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	config.Issuer = strings.TrimRight(config.Issuer, "/")
+	return &oidcProvider{config: config, client: client}
+}
+
+func (p *oidcProvider) Name() string { return p.config.Name }
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return "", err
+	}
+	options := []oauth2.AuthCodeOption{
+		oauth2.S256ChallengeOption(verifier),
+		oauth2.SetAuthURLParam("nonce", nonce),
+	}
+	for key, value := range p.config.AuthParams {
+		options = append(options, oauth2.SetAuthURLParam(key, value))
+	}
+	return oauthConfig.AuthCodeURL(state, options...), nil
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*models.ExternalIdentity, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := oauthConfig.Exchange(context.WithValue(ctx, oauth2.HTTPClient, p.client), code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, fmt.Errorf("échec de l'échange du code d'autorisation %s: %w", p.config.Name, err)
+	}
+
+	rawIDToken, _ := token.Extra("id_token").(string)
+	if rawIDToken == "" {
+		return nil, fmt.Errorf("réponse %s sans id_token", p.config.Name)
+	}
+	claims, err := p.verifyIDToken(ctx, rawIDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity := &models.ExternalIdentity{
+		Provider:      p.config.Name,
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: bool(claims.EmailVerified),
+		Name:          claims.Name,
+		RefreshToken:  token.RefreshToken,
+		Scopes:        p.config.Scopes,
+	}
+	// Le fournisseur peut accorder moins de scopes que demandé (consentement partiel)
+	if scope, ok := token.Extra("scope").(string); ok && scope != "" {
+		identity.Scopes = strings.Fields(scope)
+	}
+	return identity, nil
+}
+
+func (p *oidcProvider) TokenSource(ctx context.Context, refreshToken string) (oauth2.TokenSource, error) {
+	oauthConfig, err := p.oauthConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.client)
+	return oauthConfig.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}), nil
+}
+
+// --- Vérification de l'ID token ---
+
+// flexibleBool accepte true et "true" (certains fournisseurs encodent email_verified en chaîne)
+type flexibleBool bool
+
+func (b *flexibleBool) UnmarshalJSON(data []byte) error {
+	*b = flexibleBool(strings.Trim(string(data), `"`) == "true")
+	return nil
+}
+
+type idTokenClaims struct {
+	Nonce         string       `json:"nonce"`
+	Email         string       `json:"email"`
+	EmailVerified flexibleBool `json:"email_verified"`
+	Name          string       `json:"name"`
+	jwt.RegisteredClaims
+}
+
+func (p *oidcProvider) verifyIDToken(ctx context.Context, rawIDToken, nonce string) (*idTokenClaims, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := &idTokenClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims,
+		func(token *jwt.Token) (any, error) {
+			kid, _ := token.Header["kid"].(string)
+			return p.verificationKey(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{"RS256", "ES256", "EdDSA"}),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("id_token %s invalide: %w", p.config.Name, err)
+	}
+	if !p.acceptedIssuer(discovery.Issuer, claims.Issuer) {
+		return nil, fmt.Errorf("id_token %s invalide: issuer inattendu %q", p.config.Name, claims.Issuer)
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("id_token %s invalide: nonce inattendu", p.config.Name)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("id_token %s invalide: sujet manquant", p.config.Name)
+	}
+	return claims, nil
+}
+
+func (p *oidcProvider) acceptedIssuer(discovered, issuer string) bool {
+	if issuer == discovered {
+		return true
+	}
+	for _, alias := range p.config.IssuerAliases {
+		if issuer == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// verificationKey recharge le JWKS lorsqu'un kid est inconnu (rotation des clés du fournisseur)
+func (p *oidcProvider) verificationKey(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	key, ok := p.keys[kid]
+	stale := time.Since(p.keysAt) > jwksRefreshInterval
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	if !stale {
+		return nil, fmt.Errorf("clé de signature inconnue: %q", kid)
+	}
+
+	if err := p.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("clé de signature inconnue: %q", kid)
+}
+
+func (p *oidcProvider) refreshKeys(ctx context.Context) error {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return err
+	}
+	var jwks struct {
+		Keys []utils.JWK `json:"keys"`
+	}
+	if err := p.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return fmt.Errorf("échec du chargement du JWKS %s: %w", p.config.Name, err)
+	}
+
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		publicKey, err := jwk.PublicKey()
+		if err != nil {
+			continue // type de clé non supporté : les autres clés restent utilisables
+		}
+		keys[jwk.Kid] = publicKey
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysAt = time.Now()
+	p.mu.Unlock()
+	return nil
+}
+
+// --- Découverte ---
+
+func (p *oidcProvider) discover(ctx context.Context) (*oidcDiscovery, error) {
+	p.mu.Lock()
+	if p.discovery != nil {
+		defer p.mu.Unlock()
+		return p.discovery, nil
+	}
+	p.mu.Unlock()
+
+	var discovery oidcDiscovery
+	if err := p.getJSON(ctx, p.config.Issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("échec de la découverte OpenID %s: %w", p.config.Name, err)
+	}
+	if strings.TrimRight(discovery.Issuer, "/") != p.config.Issuer {
+		return nil, fmt.Errorf("découverte OpenID %s: issuer inattendu %q", p.config.Name, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("découverte OpenID %s: endpoints manquants", p.config.Name)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.discovery = &discovery
+	return p.discovery, nil
+}
+
+func (p *oidcProvider) oauthConfig(ctx context.Context) (*oauth2.Config, error) {
+	discovery, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  discovery.AuthorizationEndpoint,
+			TokenURL: discovery.TokenEndpoint,
+		},
+	}, nil
+}
+
+func (p *oidcProvider) getJSON(ctx context.Context, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s a répondu avec le statut %d", url, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return errors.New("réponse JSON invalide de " + url)
+	}
+	return nil
+}
@@ -0,0 +1,307 @@
+package adapters
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+const (
+	mockClientID     = "engagesense-test"
+	mockClientSecret = "test-secret"
+	mockRedirectURL  = "http://localhost:3001/auth/oauth/mock/callback"
+)
+
+// mockOIDCProvider est un fournisseur OpenID Connect minimal : découverte, autorisation
+// (redirection immédiate avec un code), token (vérification PKCE S256) et JWKS.
+type mockOIDCProvider struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu       sync.Mutex
+	pending  map[string]mockAuthorization // code -> autorisation en attente d'échange
+	audience string                       // aud des ID tokens émis (mockClientID par défaut)
+}
+
+type mockAuthorization struct {
+	challenge string
+	nonce     string
+}
+
+func newMockOIDCProvider(t *testing.T) *mockOIDCProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("génération de la clé RSA: %v", err)
+	}
+	mock := &mockOIDCProvider{t: t, key: key, pending: map[string]mockAuthorization{}, audience: mockClientID}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{
+			"issuer":                 mock.server.URL,
+			"authorization_endpoint": mock.server.URL + "/authorize",
+			"token_endpoint":         mock.server.URL + "/token",
+			"jwks_uri":               mock.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/authorize", mock.authorize)
+	mux.HandleFunc("/token", mock.token)
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"keys": []utils.JWK{{
+			Kty: "RSA", Kid: "mock-key", Alg: "RS256", Use: "sig",
+			N: base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mock.server = httptest.NewServer(mux)
+	t.Cleanup(mock.server.Close)
+	return mock
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func (m *mockOIDCProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != mockClientID || query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "requête d'autorisation invalide", http.StatusBadRequest)
+		return
+	}
+	code := "code-" + query.Get("state")
+	m.mu.Lock()
+	m.pending[code] = mockAuthorization{challenge: query.Get("code_challenge"), nonce: query.Get("nonce")}
+	m.mu.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	values := redirect.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirect.RawQuery = values.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (m *mockOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != mockClientID || clientSecret != mockClientSecret {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	switch r.PostForm.Get("grant_type") {
+	case "refresh_token":
+		if r.PostForm.Get("refresh_token") != "mock-refresh-token" {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"access_token": "refreshed-access-token", "token_type": "Bearer", "expires_in": 3600})
+		return
+	case "authorization_code":
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	m.mu.Lock()
+	authorization, ok := m.pending[code]
+	delete(m.pending, code) // un code ne s'échange qu'une fois
+	m.mu.Unlock()
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != authorization.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            m.server.URL,
+		"aud":            m.audience,
+		"sub":            "mock-subject-42",
+		"email":          "creator@example.com",
+		"email_verified": true,
+		"name":           "Test Creator",
+		"nonce":          authorization.nonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(time.Hour).Unix(),
+	})
+	idToken.Header["kid"] = "mock-key"
+	signed, err := idToken.SignedString(m.key)
+	if err != nil {
+		m.t.Errorf("signature de l'ID token: %v", err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token":  "mock-access-token",
+		"token_type":    "Bearer",
+		"expires_in":    3600,
+		"refresh_token": "mock-refresh-token",
+		"scope":         "openid email " + models.YouTubeReadOnlyScope,
+		"id_token":      signed,
+	})
+}
+
+func (m *mockOIDCProvider) provider() OIDCProviderAdapter {
+	return NewOIDCProvider(OIDCProviderConfig{
+		Name:         "mock",
+		Issuer:       m.server.URL,
+		ClientID:     mockClientID,
+		ClientSecret: mockClientSecret,
+		RedirectURL:  mockRedirectURL,
+		Scopes:       []string{"openid", "email", "profile", models.YouTubeReadOnlyScope},
+		AuthParams:   map[string]string{"access_type": "offline"},
+	}, m.server.Client())
+}
+
+// authorizeCode suit l'URL d'autorisation comme le ferait le navigateur et retourne le code reçu au callback
+func (m *mockOIDCProvider) authorizeCode(t *testing.T, authURL, expectedState string) string {
+	t.Helper()
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatalf("appel de l'URL d'autorisation: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("autorisation: statut %d, attendu 302", resp.StatusCode)
+	}
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("redirection invalide: %v", err)
+	}
+	if !strings.HasPrefix(callback.String(), mockRedirectURL) {
+		t.Fatalf("redirection vers %s, attendu %s", callback, mockRedirectURL)
+	}
+	if state := callback.Query().Get("state"); state != expectedState {
+		t.Fatalf("state %q, attendu %q", state, expectedState)
+	}
+	return callback.Query().Get("code")
+}
+
+func TestOIDCProviderAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	provider := mock.provider()
+	ctx := context.Background()
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	parsed, _ := url.Parse(authURL)
+	if parsed.Query().Get("code_challenge") == "" || parsed.Query().Get("access_type") != "offline" {
+		t.Fatalf("URL d'autorisation incomplète: %s", authURL)
+	}
+	if strings.Contains(authURL, verifier) {
+		t.Fatal("le code_verifier ne doit jamais apparaître dans l'URL d'autorisation")
+	}
+
+	code := mock.authorizeCode(t, authURL, "state-1")
+	identity, err := provider.Exchange(ctx, code, verifier, "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if identity.Provider != "mock" || identity.Subject != "mock-subject-42" {
+		t.Errorf("identité %s/%s inattendue", identity.Provider, identity.Subject)
+	}
+	if identity.Email != "creator@example.com" || !identity.EmailVerified {
+		t.Errorf("email %q (vérifié: %t) inattendu", identity.Email, identity.EmailVerified)
+	}
+	if identity.RefreshToken != "mock-refresh-token" {
+		t.Errorf("refresh token %q inattendu", identity.RefreshToken)
+	}
+	if len(identity.Scopes) != 3 || identity.Scopes[2] != models.YouTubeReadOnlyScope {
+		t.Errorf("scopes accordés %v inattendus", identity.Scopes)
+	}
+}
+
+func TestOIDCProviderRejectsWrongVerifier(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	provider := mock.provider()
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-2", "nonce-2", oauth2.GenerateVerifier())
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code := mock.authorizeCode(t, authURL, "state-2")
+	if _, err := provider.Exchange(ctx, code, oauth2.GenerateVerifier(), "nonce-2"); err == nil {
+		t.Fatal("un code_verifier différent doit être refusé")
+	}
+}
+
+func TestOIDCProviderRejectsNonceMismatch(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	provider := mock.provider()
+	ctx := context.Background()
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-3", "nonce-3", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code := mock.authorizeCode(t, authURL, "state-3")
+	if _, err := provider.Exchange(ctx, code, verifier, "another-nonce"); err == nil || !strings.Contains(err.Error(), "nonce") {
+		t.Fatalf("un nonce différent doit être refusé, obtenu: %v", err)
+	}
+}
+
+func TestOIDCProviderRejectsForeignAudience(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	mock.audience = "another-client"
+	provider := mock.provider()
+	ctx := context.Background()
+	verifier := oauth2.GenerateVerifier()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-4", "nonce-4", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+	code := mock.authorizeCode(t, authURL, "state-4")
+	if _, err := provider.Exchange(ctx, code, verifier, "nonce-4"); err == nil {
+		t.Fatal("un ID token émis pour un autre client doit être refusé")
+	}
+}
+
+func TestOIDCProviderTokenSourceUsesStoredRefreshToken(t *testing.T) {
+	mock := newMockOIDCProvider(t)
+	tokenSource, err := mock.provider().TokenSource(context.Background(), "mock-refresh-token")
+	if err != nil {
+		t.Fatalf("TokenSource: %v", err)
+	}
+	token, err := tokenSource.Token()
+	if err != nil {
+		t.Fatalf("renouvellement du token d'accès: %v", err)
+	}
+	if token.AccessToken != "refreshed-access-token" {
+		t.Errorf("token d'accès %q inattendu", token.AccessToken)
+	}
+}
@@ -9,28 +9,43 @@ import (
 	"time"
 
 	"github.com/Azertdev/FiberTest/internal/models"
+	"golang.org/x/oauth2"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
 )
 
 type YouTubeAdapter interface {
 	GetComments(ctx context.Context, videoID string, maxResults int64) ([]models.Comment, error) // Return models.Comment for simplicity now
+	// GetMyVideos liste les vidéos de la chaîne de l'utilisateur OAuth (privées et non répertoriées comprises)
+	GetMyVideos(ctx context.Context, tokenSource oauth2.TokenSource, maxResults int64) ([]models.YouTubeVideo, error)
 }
 
-// ... (Structure youtubeAdapter et constructeur NewYouTubeAdapter restent les mêmes) ...
+// YouTubeOptions : paramètres de l'API YouTube Data, réglables par environnement (voir config.Load)
+type YouTubeOptions struct {
+	BaseURL string // Endpoint de l'API (vide : API Google ; sinon émulateur ou serveur de test)
+}
+
+var DefaultYouTubeOptions = YouTubeOptions{}
+
+// youtubeMaxPageSize : l'API refuse maxResults > 100 sur commentThreads.list
+const youtubeMaxPageSize = 100
+
 type youtubeAdapter struct {
 	apiKey      string
 	ytService   *youtube.Service
 }
 
-func NewYouTubeAdapter(apiKey string) (YouTubeAdapter) {
-    // ... (code du constructeur identique) ...
+func NewYouTubeAdapter(apiKey string, options YouTubeOptions) (YouTubeAdapter) {
 	if apiKey == "" {
 		fmt.Println("clé API YouTube manquante pour l'adapter")
 		return nil
 	}
 	ctx := context.Background()
-	ytService, err := youtube.NewService(ctx, option.WithAPIKey(apiKey))
+	clientOptions := []option.ClientOption{option.WithAPIKey(apiKey)}
+	if options.BaseURL != "" {
+		clientOptions = append(clientOptions, option.WithEndpoint(options.BaseURL))
+	}
+	ytService, err := youtube.NewService(ctx, clientOptions...)
 	if err != nil {
 		fmt.Println("échec de la création du service YouTube: %w", err)
 		return nil
@@ -43,7 +58,8 @@ func NewYouTubeAdapter(apiKey string) (YouTubeAdapter) {
 }
 
 
-// Implémentation de la méthode GetComments de l'interface
+// Implémentation de la méthode GetComments de l'interface.
+// Les fils sont lus page par page (100 au plus par appel) jusqu'à maxResults ou la dernière page.
 func (a *youtubeAdapter) GetComments(ctx context.Context, videoID string, maxResults int64) ([]models.Comment, error) {
 	if a.ytService == nil {
 		return nil, errors.New("service YouTube non initialisé dans l'adapter")
@@ -51,56 +67,107 @@ func (a *youtubeAdapter) GetComments(ctx context.Context, videoID string, maxRes
 
 	log.Printf("Adapter: Récupération des commentaires pour videoID: %s (max: %d)", videoID, maxResults)
 
-	call := a.ytService.CommentThreads.List([]string{"snippet"}).
-		VideoId(videoID).
-		TextFormat("plainText").
-		MaxResults(maxResults)
-
-	// --- CORRECTION ICI ---
-	// Supprimer .WithContext(ctx) - la méthode .Do() est appelée directement sur 'call'.
-	// Le contexte 'ctx' sera vérifié *après* l'erreur si nécessaire.
-	// Vous pouvez passer des googleapi.CallOption ici si besoin, mais pas le contexte directement.
-	response, err := call.Do()
-	// --- Fin de la Correction ---
+	var comments []models.Comment
+	pageToken := ""
+	for int64(len(comments)) < maxResults {
+		pageSize := min(maxResults-int64(len(comments)), youtubeMaxPageSize)
+		call := a.ytService.CommentThreads.List([]string{"snippet"}).
+			VideoId(videoID).
+			TextFormat("plainText").
+			MaxResults(pageSize).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
 
-	if err != nil {
-		// Il est TOUJOURS pertinent de vérifier si l'erreur provient du contexte
-		// car l'annulation/timeout du contexte peut causer une erreur dans la couche transport HTTP.
-		select {
-		case <-ctx.Done(): // Vérifie si le contexte a été annulé ou a dépassé son délai
-			log.Printf("WARN: Le contexte a été annulé ou a expiré pendant/après l'appel à YouTube API pour videoID %s: %v", videoID, ctx.Err())
-			// Retourner l'erreur du contexte est souvent plus informatif dans ce cas
-			return nil, fmt.Errorf("échec de la récupération des commentaires YouTube (contexte terminé: %w): %w", ctx.Err(), err)
-		default:
-			// Le contexte n'était pas terminé, l'erreur vient probablement de l'API elle-même.
+		response, err := call.Do()
+		if err != nil {
+			// L'annulation/timeout du contexte se manifeste par une erreur de la couche transport HTTP
+			if ctx.Err() != nil {
+				log.Printf("WARN: Le contexte a été annulé ou a expiré pendant l'appel à YouTube API pour videoID %s: %v", videoID, ctx.Err())
+				return nil, fmt.Errorf("échec de la récupération des commentaires YouTube (contexte terminé: %w): %w", ctx.Err(), err)
+			}
 			return nil, fmt.Errorf("erreur lors de l'appel à l'API YouTube CommentThreads pour videoID %s: %w", videoID, err)
 		}
-	}
 
-	// Traitement de la réponse (reste identique)
-	var comments []models.Comment
-	log.Printf("Adapter: Traitement de %d threads de commentaires reçus pour videoID: %s", len(response.Items), videoID)
-	for _, item := range response.Items {
-		// ... (vérifications nil et parsing) ...
-		if item.Snippet == nil || item.Snippet.TopLevelComment == nil || item.Snippet.TopLevelComment.Snippet == nil {
-			log.Printf("WARN: Structure de commentaire inattendue reçue de l'API YouTube pour videoID %s, élément ignoré.", videoID)
-			continue
-		}
-		snippet := item.Snippet.TopLevelComment.Snippet
-		parsedTime, parseErr := time.Parse(time.RFC3339, snippet.PublishedAt)
-		if parseErr != nil {
-			log.Printf("WARN: Erreur de parsing de la date '%s' pour un commentaire sur videoID %s: %v. Utilisation de l'heure actuelle.", snippet.PublishedAt, videoID, parseErr)
-			parsedTime = time.Now()
+		log.Printf("Adapter: Traitement de %d threads de commentaires reçus pour videoID: %s", len(response.Items), videoID)
+		for _, item := range response.Items {
+			if item.Snippet == nil || item.Snippet.TopLevelComment == nil || item.Snippet.TopLevelComment.Snippet == nil {
+				log.Printf("WARN: Structure de commentaire inattendue reçue de l'API YouTube pour videoID %s, élément ignoré.", videoID)
+				continue
+			}
+			snippet := item.Snippet.TopLevelComment.Snippet
+			parsedTime, parseErr := time.Parse(time.RFC3339, snippet.PublishedAt)
+			if parseErr != nil {
+				log.Printf("WARN: Erreur de parsing de la date '%s' pour un commentaire sur videoID %s: %v. Utilisation de l'heure actuelle.", snippet.PublishedAt, videoID, parseErr)
+				parsedTime = time.Now()
+			}
+			comments = append(comments, models.Comment{
+				VideoID:    videoID,
+				ChannelID:  item.Snippet.ChannelId,
+				Content:    snippet.TextDisplay,
+				Author:     snippet.AuthorDisplayName,
+				Date:       parsedTime,
+				ReplyCount: item.Snippet.TotalReplyCount,
+				LikeCount:  snippet.LikeCount,
+			})
 		}
-		comment := models.Comment{
-			VideoID: videoID,
-			Content: snippet.TextDisplay,
-			Author:  snippet.AuthorDisplayName,
-			Date:    parsedTime,
+
+		if response.NextPageToken == "" || len(response.Items) == 0 {
+			break
 		}
-		comments = append(comments, comment)
+		pageToken = response.NextPageToken
 	}
 
 	log.Printf("Adapter: %d commentaires formatés retournés pour videoID: %s", len(comments), videoID)
 	return comments, nil
-}
\ No newline at end of file
+}
+
+// GetMyVideos utilise le token OAuth de l'utilisateur (scope youtube.readonly) et non la clé API :
+// seul le propriétaire de la chaîne voit ses vidéos privées et non répertoriées.
+func (a *youtubeAdapter) GetMyVideos(ctx context.Context, tokenSource oauth2.TokenSource, maxResults int64) ([]models.YouTubeVideo, error) {
+	userService, err := youtube.NewService(ctx, option.WithTokenSource(tokenSource))
+	if err != nil {
+		return nil, fmt.Errorf("échec de la création du service YouTube utilisateur: %w", err)
+	}
+
+	channels, err := userService.Channels.List([]string{"contentDetails"}).Mine(true).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de l'appel à l'API YouTube Channels (mine): %w", err)
+	}
+	if len(channels.Items) == 0 || channels.Items[0].ContentDetails == nil || channels.Items[0].ContentDetails.RelatedPlaylists == nil {
+		return []models.YouTubeVideo{}, nil // compte Google sans chaîne YouTube
+	}
+	channelID := channels.Items[0].Id
+	uploads := channels.Items[0].ContentDetails.RelatedPlaylists.Uploads
+
+	items, err := userService.PlaylistItems.List([]string{"snippet", "status"}).
+		PlaylistId(uploads).
+		MaxResults(maxResults).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors de l'appel à l'API YouTube PlaylistItems pour la chaîne %s: %w", channelID, err)
+	}
+
+	videos := make([]models.YouTubeVideo, 0, len(items.Items))
+	for _, item := range items.Items {
+		if item.Snippet == nil || item.Snippet.ResourceId == nil {
+			continue
+		}
+		video := models.YouTubeVideo{
+			VideoID:   item.Snippet.ResourceId.VideoId,
+			ChannelID: channelID,
+			Title:     item.Snippet.Title,
+		}
+		if item.Status != nil {
+			video.PrivacyStatus = item.Status.PrivacyStatus
+		}
+		if publishedAt, err := time.Parse(time.RFC3339, item.Snippet.PublishedAt); err == nil {
+			video.PublishedAt = publishedAt
+		}
+		videos = append(videos, video)
+	}
+	log.Printf("Adapter: %d vidéos retournées pour la chaîne %s", len(videos), channelID)
+	return videos, nil
+}
@@ -0,0 +1,55 @@
+package adapters
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/fakes"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestYouTubeAdapterGetCommentsFollowsPagination(t *testing.T) {
+	api := fakes.NewYouTubeAPI(t)
+	published := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	threads := make([]models.Comment, 250)
+	for i := range threads {
+		threads[i] = models.Comment{ChannelID: "UC1", Content: fmt.Sprintf("commentaire %d", i), Author: "auteur", Date: published, ReplyCount: int64(i % 2), LikeCount: int64(i)}
+	}
+	api.SetComments("v1", threads)
+
+	adapter := NewYouTubeAdapter(fakes.YouTubeAPIKey, YouTubeOptions{BaseURL: api.BaseURL()})
+	comments, err := adapter.GetComments(t.Context(), "v1", 230)
+	if err != nil {
+		t.Fatalf("GetComments: %v", err)
+	}
+	// 230 commentaires demandés : pages de 100, 100 puis 30 (l'API refuse maxResults > 100)
+	if len(comments) != 230 || api.Requests() != 3 {
+		t.Fatalf("%d commentaire(s) en %d requête(s), attendu 230 en 3", len(comments), api.Requests())
+	}
+	last := comments[229]
+	if last.Content != "commentaire 229" || last.VideoID != "v1" || last.ChannelID != "UC1" || last.ReplyCount != 1 || last.LikeCount != 229 || !last.Date.Equal(published) {
+		t.Fatalf("commentaire mal converti: %+v", last)
+	}
+
+	// Moins de commentaires que demandé : arrêt à la dernière page
+	all, err := adapter.GetComments(t.Context(), "v1", 2000)
+	if err != nil || len(all) != 250 {
+		t.Fatalf("GetComments(2000): %v, %d commentaire(s)", err, len(all))
+	}
+}
+
+func TestYouTubeAdapterGetCommentsReportsAPIErrors(t *testing.T) {
+	api := fakes.NewYouTubeAPI(t)
+
+	adapter := NewYouTubeAdapter(fakes.YouTubeAPIKey, YouTubeOptions{BaseURL: api.BaseURL()})
+	if _, err := adapter.GetComments(t.Context(), "inconnue", 10); err == nil || !strings.Contains(err.Error(), "inconnue") {
+		t.Fatalf("vidéo inconnue: erreur attendue, obtenu %v", err)
+	}
+
+	wrongKey := NewYouTubeAdapter("mauvaise-cle", YouTubeOptions{BaseURL: api.BaseURL()})
+	if _, err := wrongKey.GetComments(t.Context(), "v1", 10); err == nil {
+		t.Fatal("clé invalide acceptée")
+	}
+}
@@ -0,0 +1,78 @@
+// internal/database/database.go
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/migrations"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// Moteurs supportés : PostgreSQL en production, SQLite (pur Go, sans cgo) en local et pour les tests
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Pragmas appliqués à chaque connexion SQLite : attente plutôt qu'erreur SQLITE_BUSY entre
+// connexions du pool, journal WAL (lectures concurrentes) et format de date triable
+const sqlitePragmas = "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=foreign_keys(1)&_time_format=sqlite"
+
+// Open ouvre la connexion selon le moteur (dsn : URL PostgreSQL ou chemin du fichier SQLite)
+func Open(driver, dsn string) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case DriverPostgres:
+		dialector = postgres.Open(dsn)
+	case DriverSQLite:
+		dialector = sqlite.Open(sqliteDSN(dsn))
+	default:
+		return nil, fmt.Errorf("moteur de base de données inconnu: %q", driver)
+	}
+	// TranslateError: permet de détecter gorm.ErrDuplicatedKey (versions d'insight concurrentes)
+	db, err := gorm.Open(dialector, &gorm.Config{TranslateError: true})
+	if err != nil {
+		return nil, fmt.Errorf("connexion à la base de données (%s): %w", driver, err)
+	}
+	return db, nil
+}
+
+func sqliteDSN(path string) string {
+	if strings.Contains(path, "_pragma=") {
+		return path // pragmas explicites : laissés tels quels
+	}
+	if strings.Contains(path, "?") {
+		return path + "&" + sqlitePragmas
+	}
+	return path + "?" + sqlitePragmas
+}
+
+// IsSQLite indique si la connexion utilise SQLite
+func IsSQLite(db *gorm.DB) bool {
+	return db.Dialector.Name() == DriverSQLite
+}
+
+// Migrate met le schéma à jour et retourne le nombre de migrations appliquées.
+// PostgreSQL : migrations SQL versionnées (internal/migrations).
+// SQLite : schéma déduit des modèles (base locale jetable, pas de données à préserver).
+func Migrate(ctx context.Context, db *gorm.DB) (int, error) {
+	ctx = WithoutQueryTimeout(ctx)
+	if IsSQLite(db) {
+		if err := db.WithContext(ctx).AutoMigrate(models.All()...); err != nil {
+			return 0, fmt.Errorf("création du schéma SQLite: %w", err)
+		}
+		return 0, nil
+	}
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return 0, err
+	}
+	applied, err := migrator.Up(ctx, 0)
+	return len(applied), err
+}
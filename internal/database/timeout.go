@@ -0,0 +1,90 @@
+// internal/database/timeout.go
+package database
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	queryTimeoutBefore = "database:query_timeout_before"
+	queryTimeoutAfter  = "database:query_timeout_after"
+	// Clés posées sur l'instance gorm entre les deux callbacks
+	queryTimeoutCancelKey   = "database:query_timeout_cancel"
+	queryTimeoutOriginalKey = "database:query_timeout_original"
+)
+
+type noQueryTimeoutKey struct{}
+
+// WithoutQueryTimeout désactive le délai par requête pour ctx (migrations, verrou consultatif :
+// opérations légitimement longues)
+func WithoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noQueryTimeoutKey{}, true)
+}
+
+// UseQueryTimeout borne chaque requête passant par db à timeout (0 : désactivé).
+// L'échéance du contexte de l'appelant reste prioritaire si elle est plus proche.
+func UseQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	return db.Use(&queryTimeout{timeout: timeout})
+}
+
+type queryTimeout struct {
+	timeout time.Duration
+}
+
+func (p *queryTimeout) Name() string {
+	return "database:query_timeout"
+}
+
+func (p *queryTimeout) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register(queryTimeoutBefore, p.before),
+		cb.Create().After("gorm:create").Register(queryTimeoutAfter, p.release),
+		cb.Query().Before("gorm:query").Register(queryTimeoutBefore, p.before),
+		cb.Query().After("gorm:query").Register(queryTimeoutAfter, p.release),
+		cb.Update().Before("gorm:update").Register(queryTimeoutBefore, p.before),
+		cb.Update().After("gorm:update").Register(queryTimeoutAfter, p.release),
+		cb.Delete().Before("gorm:delete").Register(queryTimeoutBefore, p.before),
+		cb.Delete().After("gorm:delete").Register(queryTimeoutAfter, p.release),
+		cb.Raw().Before("gorm:raw").Register(queryTimeoutBefore, p.before),
+		cb.Raw().After("gorm:raw").Register(queryTimeoutAfter, p.release),
+		// Row/Rows (et donc Scan) : les lignes sont lues après les callbacks, le contexte ne peut
+		// pas être annulé à ce moment-là ; il est libéré à son échéance
+		cb.Row().Before("gorm:row").Register(queryTimeoutBefore, p.before),
+		cb.Row().After("gorm:row").Register(queryTimeoutAfter, p.restore),
+	)
+}
+
+func (p *queryTimeout) before(db *gorm.DB) {
+	ctx := db.Statement.Context
+	if ctx == nil || ctx.Value(noQueryTimeoutKey{}) != nil {
+		return
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	db.Statement.Context = timeoutCtx
+	db.InstanceSet(queryTimeoutOriginalKey, ctx)
+	db.InstanceSet(queryTimeoutCancelKey, cancel)
+}
+
+// release annule le contexte de la requête terminée puis restaure celui de l'appelant
+func (p *queryTimeout) release(db *gorm.DB) {
+	if cancel, ok := db.InstanceGet(queryTimeoutCancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+	p.restore(db)
+}
+
+// restore remet le contexte d'origine : une même instance gorm peut enchaîner plusieurs
+// requêtes (Count puis Find) qui ne doivent pas hériter d'un contexte annulé
+func (p *queryTimeout) restore(db *gorm.DB) {
+	if ctx, ok := db.InstanceGet(queryTimeoutOriginalKey); ok {
+		db.Statement.Context = ctx.(context.Context)
+	}
+}
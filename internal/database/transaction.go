@@ -0,0 +1,28 @@
+// internal/database/transaction.go
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// Conn retourne la connexion à utiliser pour ctx : la transaction ouverte par WithinTransaction
+// si ctx en porte une, sinon db. Les repositories passent tous par Conn, ce qui leur permet de
+// participer à une transaction sans changer de signature.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// WithinTransaction exécute fn dans une transaction : validée si fn retourne nil, annulée sinon.
+// Les appels imbriqués utilisent un point de sauvegarde de la transaction englobante.
+func WithinTransaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	return Conn(ctx, db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
@@ -0,0 +1,277 @@
+// internal/fakes/fakes.go
+package fakes
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/oauth2"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/services"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// Doublures en mémoire des dépendances externes du pipeline d'analyse (services/interfaces.go).
+// Elles enregistrent les appels reçus pour que les tests puissent vérifier le découpage en lots,
+// et leurs réponses sont scriptables. Sûres pour un usage concurrent.
+
+var (
+	_ services.YouTubeAdapter = (*YouTube)(nil)
+	_ services.GroqAdapter    = (*Groq)(nil)
+	_ services.TranscriptUtil = (*Transcripts)(nil)
+	_ services.Mailer         = (*Mailer)(nil)
+)
+
+// YouTube sert des commentaires et des vidéos préenregistrés
+type YouTube struct {
+	mu       sync.Mutex
+	comments map[string][]models.Comment // par videoID
+	videos   []models.YouTubeVideo
+	err      error
+	calls    []string // videoIDs demandés à GetComments
+}
+
+func NewYouTube() *YouTube {
+	return &YouTube{comments: map[string][]models.Comment{}}
+}
+
+// SetComments enregistre les commentaires retournés pour videoID
+func (y *YouTube) SetComments(videoID string, comments []models.Comment) {
+	y.mu.Lock()
+	defer y.mu.Unlock()
+	y.comments[videoID] = comments
+}
+
+func (y *YouTube) SetVideos(videos []models.YouTubeVideo) {
+	y.mu.Lock()
+	defer y.mu.Unlock()
+	y.videos = videos
+}
+
+// FailWith fait échouer tous les appels suivants avec err (nil : rétablit le service)
+func (y *YouTube) FailWith(err error) {
+	y.mu.Lock()
+	defer y.mu.Unlock()
+	y.err = err
+}
+
+// Calls retourne les videoIDs demandés, dans l'ordre
+func (y *YouTube) Calls() []string {
+	y.mu.Lock()
+	defer y.mu.Unlock()
+	return append([]string(nil), y.calls...)
+}
+
+func (y *YouTube) GetComments(ctx context.Context, videoID string, maxResults int64) ([]models.Comment, error) {
+	y.mu.Lock()
+	defer y.mu.Unlock()
+	y.calls = append(y.calls, videoID)
+	if y.err != nil {
+		return nil, y.err
+	}
+	comments := y.comments[videoID]
+	if int64(len(comments)) > maxResults {
+		comments = comments[:maxResults]
+	}
+	// Copie : le pipeline complète les commentaires (UserID, ID) avant de les enregistrer
+	return append([]models.Comment(nil), comments...), nil
+}
+
+func (y *YouTube) GetMyVideos(ctx context.Context, tokenSource oauth2.TokenSource, maxResults int64) ([]models.YouTubeVideo, error) {
+	y.mu.Lock()
+	defer y.mu.Unlock()
+	if y.err != nil {
+		return nil, y.err
+	}
+	videos := y.videos
+	if int64(len(videos)) > maxResults {
+		videos = videos[:maxResults]
+	}
+	return append([]models.YouTubeVideo(nil), videos...), nil
+}
+
+// AnalyzeFunc produit la réponse d'un appel d'analyse ; call commence à 1
+type AnalyzeFunc func(call int, comments []string) (string, error)
+
+// MetaSummaryFunc produit la réponse de l'étape de réduction (synthèse globale des lots)
+type MetaSummaryFunc func(input utils.MetaSummaryInput) (string, error)
+
+// Groq répond aux analyses via une AnalyzeFunc (par défaut : une réponse Markdown valide listant
+// les commentaires du lot parmi les points positifs), aux synthèses globales via une
+// MetaSummaryFunc et aux résumés de transcription par un texte fixe
+type Groq struct {
+	mu               sync.Mutex
+	analyze          AnalyzeFunc
+	metaSummary      MetaSummaryFunc
+	summary          string
+	summaryErr       error
+	analyzeCalls     [][]string
+	metaSummaryCalls []utils.MetaSummaryInput
+	summaryCalls     int
+}
+
+func NewGroq() *Groq {
+	return &Groq{analyze: defaultAnalyze, metaSummary: defaultMetaSummary, summary: "Résumé de la transcription."}
+}
+
+func defaultAnalyze(call int, comments []string) (string, error) {
+	scores := map[int]utils.CommentScore{}
+	for i := range comments {
+		scores[i+1] = utils.NewCommentScore(0.5, "")
+	}
+	return InsightMarkdown(utils.ParsedInsight{
+		Sentiment:     "Positif",
+		Summary:       fmt.Sprintf("Lot %d : %d commentaires analysés.", call, len(comments)),
+		TopComments:   comments,
+		CommentScores: scores,
+	}), nil
+}
+
+func defaultMetaSummary(input utils.MetaSummaryInput) (string, error) {
+	return InsightMarkdown(utils.ParsedInsight{
+		Sentiment: "Positif",
+		Summary:   fmt.Sprintf("Synthèse de %d lots (%d commentaires).", len(input.ChunkSummaries), input.CommentCount),
+	}), nil
+}
+
+func (g *Groq) OnAnalyze(fn AnalyzeFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.analyze = fn
+}
+
+func (g *Groq) OnMetaSummary(fn MetaSummaryFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.metaSummary = fn
+}
+
+// SetSummary fixe la réponse de SummarizeTranscript (err non nil : échec)
+func (g *Groq) SetSummary(summary string, err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.summary, g.summaryErr = summary, err
+}
+
+// AnalyzeCalls retourne les lots reçus par AnalyzeComments, dans l'ordre
+func (g *Groq) AnalyzeCalls() [][]string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([][]string(nil), g.analyzeCalls...)
+}
+
+// MetaSummaryCalls retourne les entrées reçues par SummarizeInsights, dans l'ordre
+func (g *Groq) MetaSummaryCalls() []utils.MetaSummaryInput {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]utils.MetaSummaryInput(nil), g.metaSummaryCalls...)
+}
+
+func (g *Groq) SummaryCalls() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.summaryCalls
+}
+
+func (g *Groq) AnalyzeComments(ctx context.Context, comments []string, videoTranscript string) (string, error) {
+	g.mu.Lock()
+	g.analyzeCalls = append(g.analyzeCalls, append([]string(nil), comments...))
+	call, analyze := len(g.analyzeCalls), g.analyze
+	g.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return analyze(call, comments)
+}
+
+func (g *Groq) SummarizeInsights(ctx context.Context, input utils.MetaSummaryInput) (string, error) {
+	g.mu.Lock()
+	g.metaSummaryCalls = append(g.metaSummaryCalls, input)
+	metaSummary := g.metaSummary
+	g.mu.Unlock()
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return metaSummary(input)
+}
+
+func (g *Groq) SummarizeTranscript(ctx context.Context, transcript string) (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.summaryCalls++
+	if g.summaryErr != nil {
+		return "", g.summaryErr
+	}
+	return g.summary, nil
+}
+
+// Transcripts sert des transcriptions préenregistrées ; une vidéo inconnue produit une erreur
+type Transcripts struct {
+	mu          sync.Mutex
+	transcripts map[string]string
+}
+
+func NewTranscripts() *Transcripts {
+	return &Transcripts{transcripts: map[string]string{}}
+}
+
+func (t *Transcripts) Set(videoID, transcript string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.transcripts[videoID] = transcript
+}
+
+func (t *Transcripts) GetTranscript(ctx context.Context, videoID string) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	transcript, ok := t.transcripts[videoID]
+	if !ok {
+		return "", fmt.Errorf("aucune transcription disponible pour %s", videoID)
+	}
+	return transcript, nil
+}
+
+// Email est un message reçu par le fake Mailer
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer enregistre les emails au lieu de les envoyer, pour lire les liens qu'ils contiennent
+type Mailer struct {
+	mu   sync.Mutex
+	sent []Email
+}
+
+func NewMailer() *Mailer {
+	return &Mailer{}
+}
+
+// Sent retourne les emails envoyés à to, dans l'ordre
+func (m *Mailer) Sent(to string) []Email {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var sent []Email
+	for _, email := range m.sent {
+		if email.To == to {
+			sent = append(sent, email)
+		}
+	}
+	return sent
+}
+
+func (m *Mailer) Send(ctx context.Context, to, subject, body string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, Email{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// InsightMarkdown produit une réponse au format demandé par le prompt d'analyse
+// (voir utils.RenderInsightMarkdown)
+func InsightMarkdown(insight utils.ParsedInsight) string {
+	return utils.RenderInsightMarkdown(insight)
+}
@@ -0,0 +1,240 @@
+// internal/fakes/servers.go
+package fakes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// Serveurs HTTP de substitution pour tester les adapters réels (requêtes, pagination, erreurs)
+// sans accès réseau. Ils reproduisent le sous-ensemble des API utilisé par l'application.
+
+// YouTubeAPIKey est la seule clé acceptée par YouTubeAPI
+const YouTubeAPIKey = "test-youtube-key"
+
+// YouTubeAPI imite commentThreads.list de l'API YouTube Data v3 : clé obligatoire,
+// maxResults entre 1 et 100, pagination par nextPageToken
+type YouTubeAPI struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	threads  map[string][]models.Comment // par videoID
+	requests int
+}
+
+func NewYouTubeAPI(t *testing.T) *YouTubeAPI {
+	t.Helper()
+	api := &YouTubeAPI{threads: map[string][]models.Comment{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /youtube/v3/commentThreads", api.commentThreads)
+	api.server = httptest.NewServer(mux)
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+// BaseURL est l'endpoint à passer à adapters.YouTubeOptions
+func (a *YouTubeAPI) BaseURL() string {
+	return a.server.URL + "/"
+}
+
+// SetComments enregistre les fils de commentaires de videoID (ChannelID, Content, Author, Date, ReplyCount, LikeCount)
+func (a *YouTubeAPI) SetComments(videoID string, comments []models.Comment) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.threads[videoID] = comments
+}
+
+// Requests retourne le nombre de pages servies
+func (a *YouTubeAPI) Requests() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.requests
+}
+
+func (a *YouTubeAPI) commentThreads(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("key") != YouTubeAPIKey {
+		writeGoogleError(w, http.StatusForbidden, "API key not valid")
+		return
+	}
+	maxResults := 20 // valeur par défaut de l'API
+	if value := query.Get("maxResults"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > 100 {
+			writeGoogleError(w, http.StatusBadRequest, "Invalid value for maxResults")
+			return
+		}
+		maxResults = parsed
+	}
+	offset := 0
+	if token := query.Get("pageToken"); token != "" {
+		parsed, err := strconv.Atoi(token)
+		if err != nil || parsed < 0 {
+			writeGoogleError(w, http.StatusBadRequest, "Invalid page token")
+			return
+		}
+		offset = parsed
+	}
+
+	a.mu.Lock()
+	a.requests++
+	threads, ok := a.threads[query.Get("videoId")]
+	a.mu.Unlock()
+	if !ok {
+		writeGoogleError(w, http.StatusNotFound, "The video identified by the videoId parameter could not be found.")
+		return
+	}
+
+	end := min(offset+maxResults, len(threads))
+	items := []map[string]any{}
+	for _, comment := range threads[min(offset, end):end] {
+		items = append(items, map[string]any{
+			"kind": "youtube#commentThread",
+			"snippet": map[string]any{
+				"channelId":       comment.ChannelID,
+				"videoId":         query.Get("videoId"),
+				"totalReplyCount": comment.ReplyCount,
+				"topLevelComment": map[string]any{
+					"snippet": map[string]any{
+						"textDisplay":       comment.Content,
+						"authorDisplayName": comment.Author,
+						"publishedAt":       comment.Date.UTC().Format(time.RFC3339),
+						"likeCount":         comment.LikeCount,
+					},
+				},
+			},
+		})
+	}
+	response := map[string]any{"kind": "youtube#commentThreadListResponse", "items": items}
+	if end < len(threads) {
+		response["nextPageToken"] = strconv.Itoa(end)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func writeGoogleError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{"code": status, "message": message},
+	})
+}
+
+// GroqAPIKey est la seule clé acceptée par GroqAPI
+const GroqAPIKey = "test-groq-key"
+
+// GroqRequest est le corps d'une requête chat completions reçue par GroqAPI
+type GroqRequest struct {
+	Model       string  `json:"model"`
+	Temperature float64 `json:"temperature"`
+	MaxTokens   int     `json:"max_tokens"`
+	Messages    []struct {
+		Role    string `json:"role"`
+		Content string `json:"content"`
+	} `json:"messages"`
+}
+
+// Prompt retourne le contenu du premier message
+func (r GroqRequest) Prompt() string {
+	if len(r.Messages) == 0 {
+		return ""
+	}
+	return r.Messages[0].Content
+}
+
+// GroqReply produit le statut HTTP et le contenu de la réponse à une requête (call commence à 1)
+type GroqReply func(call int, request GroqRequest) (status int, content string)
+
+// GroqAPI imite l'endpoint chat completions (compatible OpenAI) de Groq
+type GroqAPI struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	reply    GroqReply
+	hang     bool
+	requests []GroqRequest
+}
+
+func NewGroqAPI(t *testing.T) *GroqAPI {
+	t.Helper()
+	api := &GroqAPI{reply: func(call int, request GroqRequest) (int, string) {
+		return http.StatusOK, InsightMarkdown(utils.ParsedInsight{Sentiment: "Positif", Summary: "Réponse de test."})
+	}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /openai/v1/chat/completions", api.chatCompletions)
+	api.server = httptest.NewServer(mux)
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+// URL est l'endpoint à passer à adapters.GroqOptions.BaseURL
+func (a *GroqAPI) URL() string {
+	return a.server.URL + "/openai/v1/chat/completions"
+}
+
+func (a *GroqAPI) OnRequest(reply GroqReply) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.reply = reply
+}
+
+// Hang fait attendre les requêtes suivantes sans réponse, jusqu'à ce que le client abandonne
+// (timeout de l'adapter) : simule une API saturée ou injoignable
+func (a *GroqAPI) Hang() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.hang = true
+}
+
+// Requests retourne les requêtes reçues, dans l'ordre
+func (a *GroqAPI) Requests() []GroqRequest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]GroqRequest(nil), a.requests...)
+}
+
+func (a *GroqAPI) chatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer "+GroqAPIKey {
+		writeJSON(w, http.StatusUnauthorized, map[string]any{"error": map[string]string{"message": "Invalid API Key"}})
+		return
+	}
+	var request GroqRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Model == "" || len(request.Messages) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]any{"error": map[string]string{"message": "invalid request body"}})
+		return
+	}
+
+	a.mu.Lock()
+	a.requests = append(a.requests, request)
+	call, reply, hang := len(a.requests), a.reply, a.hang
+	a.mu.Unlock()
+	if hang {
+		<-r.Context().Done()
+		return
+	}
+
+	status, content := reply(call, request)
+	if status != http.StatusOK {
+		writeJSON(w, status, map[string]any{"error": map[string]string{"message": content}})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"object":  "chat.completion",
+		"model":   request.Model,
+		"choices": []map[string]any{{"index": 0, "message": map[string]string{"role": "assistant", "content": content}, "finish_reason": "stop"}},
+		"usage":   map[string]int{"prompt_tokens": len(strings.Fields(request.Prompt())), "completion_tokens": len(strings.Fields(content))},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,79 @@
+// internal/handlers/alert_handler.go
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/services"
+)
+
+type AlertHandler struct {
+	alertService services.AlertService
+}
+
+func NewAlertHandler(alertService services.AlertService) AlertHandler {
+	return AlertHandler{alertService}
+}
+
+// Créer une règle d'alerte pour l'utilisateur courant
+func (h *AlertHandler) CreateRule(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+
+	rule := new(models.AlertRule)
+	if err := c.BodyParser(rule); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Données invalides"})
+	}
+	// Une règle créée est active par défaut
+	rule.Enabled = true
+
+	if err := h.alertService.CreateRule(c.Context(), userID, rule); err != nil {
+		log.Printf("WARN: Échec création règle d'alerte pour userID %s: %v", userID, err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": err.Error()})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{"status": "success", "data": rule})
+}
+
+// Lister les règles d'alerte de l'utilisateur courant
+func (h *AlertHandler) ListRules(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+
+	rules, err := h.alertService.ListRules(c.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Échec récupération règles d'alerte pour userID %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Impossible de récupérer les règles d'alerte"})
+	}
+	return c.JSON(fiber.Map{"status": "success", "data": rules})
+}
+
+// Supprimer une règle d'alerte de l'utilisateur courant
+func (h *AlertHandler) DeleteRule(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	ruleID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "ID invalide"})
+	}
+
+	if err := h.alertService.DeleteRule(c.Context(), userID, ruleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Règle d'alerte non trouvée"})
+		}
+		log.Printf("ERROR: Échec suppression règle d'alerte %s pour userID %s: %v", ruleID, userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Échec de la suppression"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
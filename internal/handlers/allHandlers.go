@@ -1,15 +1,62 @@
 package handlers
 
-import "github.com/Azertdev/FiberTest/internal/services"
+import (
+	"github.com/Azertdev/FiberTest/internal/middleware"
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/services"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
 
 type AllHandlers struct{
 	UserHandler UserHandler
 	CommentHandler CommentHandler
+	AlertHandler AlertHandler
+	NotificationHandler NotificationHandler
+	InsightHandler InsightHandler
+	OAuthHandler OAuthHandler
+	APIKeyHandler APIKeyHandler
+	HealthHandler HealthHandler
+	JWKSHandler JWKSHandler
 }
 
-func NewAllHandlers(UserHandler services.UserService, CommentHandler services.CommentService) AllHandlers{
+func NewAllHandlers(allServices *services.AllServices) AllHandlers{
 	return AllHandlers{
-		UserHandler: NewUserHandler(UserHandler),
-		CommentHandler: NewCommentHandler(CommentHandler),
+		UserHandler: NewUserHandler(allServices.UserService, allServices.TokenService, allServices.AccountService, allServices.LoginGuardService),
+		CommentHandler: NewCommentHandler(allServices.CommentService),
+		AlertHandler: NewAlertHandler(allServices.AlertService),
+		NotificationHandler: NewNotificationHandler(allServices.NotificationService),
+		InsightHandler: NewInsightHandler(allServices.InsightService),
+		OAuthHandler: NewOAuthHandler(allServices.OAuthService, allServices.TokenService),
+		APIKeyHandler: NewAPIKeyHandler(allServices.APIKeyService),
+		HealthHandler: NewHealthHandler(allServices.HealthService),
+		JWKSHandler: NewJWKSHandler(allServices.TokenService),
 	}
-}
\ No newline at end of file
+}
+
+// currentUserID récupère l'UUID de l'utilisateur authentifié par le middleware JWT
+func currentUserID(c *fiber.Ctx) (uuid.UUID, bool) {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok || principal.UserID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return principal.UserID, true
+}
+
+// scopeUserID retourne le compte dont la requête peut lire les données : le propriétaire validé par
+// middleware.RequireOwnerOrAdmin, sinon ?user_id= pour un admin, sinon l'utilisateur courant.
+func scopeUserID(c *fiber.Ctx) (uuid.UUID, bool) {
+	if ownerID, ok := middleware.ResourceOwner(c); ok {
+		return ownerID, true
+	}
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok || principal.UserID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	if principal.Role == models.RoleAdmin {
+		if requested, err := uuid.Parse(c.Query("user_id")); err == nil {
+			return requested, true
+		}
+	}
+	return principal.UserID, true
+}
@@ -0,0 +1,94 @@
+// internal/handlers/api_key_handler.go
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/services"
+)
+
+type APIKeyHandler struct {
+	apiKeyService services.APIKeyService
+}
+
+func NewAPIKeyHandler(apiKeyService services.APIKeyService) APIKeyHandler {
+	return APIKeyHandler{apiKeyService}
+}
+
+// Créer une clé d'API : la valeur complète n'est retournée qu'ici
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	body := new(models.CreateAPIKeyRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Données invalides"})
+	}
+	if err := models.ValidateRequest(body); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": err.Error()})
+	}
+
+	key, rawKey, err := h.apiKeyService.CreateAPIKey(c.Context(), userID, *body)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAPIKeyScopeForbidden):
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"status": "error", "message": err.Error()})
+		case errors.Is(err, services.ErrAPIKeyExpiryInPast):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": err.Error()})
+		case errors.Is(err, services.ErrTooManyAPIKeys):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"status": "error", "message": err.Error()})
+		}
+		log.Printf("ERROR: Échec création de clé d'API pour userID %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Échec de la création de la clé d'API"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"status": "success",
+		"data":   models.NewAPIKeyResponse(key),
+		"key":    rawKey,
+	})
+}
+
+// Lister les clés d'API de l'utilisateur courant (sans les secrets)
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	keys, err := h.apiKeyService.ListAPIKeys(c.Context(), userID)
+	if err != nil {
+		log.Printf("ERROR: Échec récupération des clés d'API pour userID %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Échec de la récupération des clés d'API"})
+	}
+	responses := make([]models.APIKeyResponse, 0, len(keys))
+	for i := range keys {
+		responses = append(responses, models.NewAPIKeyResponse(&keys[i]))
+	}
+	return c.JSON(fiber.Map{"status": "success", "data": responses})
+}
+
+// Révoquer une clé d'API
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	keyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "ID invalide"})
+	}
+	if err := h.apiKeyService.RevokeAPIKey(c.Context(), userID, keyID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Clé d'API non trouvée"})
+		}
+		log.Printf("ERROR: Échec révocation de la clé d'API %s pour userID %s: %v", keyID, userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Échec de la révocation de la clé d'API"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -2,8 +2,11 @@
 package handlers
 
 import (
-	// "fmt" // Importer fmt pour formater les erreurs
+	"errors"
+	"fmt" // Importer fmt pour formater les erreurs
 	"log" // Importer log pour le logging
+	"strconv"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid" // <-- NÉCESSAIRE pour générer et utiliser des UUIDs
@@ -34,37 +37,38 @@ func (h *CommentHandler) GetComments(c *fiber.Ctx) error { // Gardons votre nom
 		})
 	}
 
-	// --- CORRECTION PRINCIPALE ---
-	// Supprimer : userID := "testUser"
-	// Logique pour obtenir ou générer un UUID valide
-	var finalUserID uuid.UUID // Variable pour l'UUID à utiliser
+	// L'identité vient du JWT : sans utilisateur authentifié, l'insight serait enregistré sous uuid.Nil
+	finalUserID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Utilisateur non authentifié",
+		})
+	}
 
-	// Tenter de récupérer depuis les locals (pour le futur quand l'auth sera là)
-	userIDClaim := c.Locals("userId")
-	if userIDClaim != nil {
-		var ok bool
-		finalUserID, ok = userIDClaim.(uuid.UUID)
-		if !ok {
-			log.Printf("ERROR: userID trouvé dans c.Locals mais type invalide: %T", userIDClaim)
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+	// Politique de fraîcheur : ?max_age=6h (ou en secondes) et ?force=true pour relancer l'analyse
+	policy := services.FreshnessPolicy{Force: c.QueryBool("force", false)}
+	if maxAge := c.Query("max_age"); maxAge != "" {
+		parsedMaxAge, err := parseMaxAge(maxAge)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 				"status":  "error",
-				"message": "Erreur interne (type userID invalide)",
+				"message": "Paramètre 'max_age' invalide (ex: 3600 ou 6h)",
 			})
 		}
-		log.Printf("INFO: Utilisation de userID %s depuis c.Locals", finalUserID)
+		policy.MaxAge = parsedMaxAge
 	}
-	// } else {
-	// 	// Cas actuel : Pas d'authentification, générer un UUID temporaire
-	// 	finalUserID = uuid.New() // Génère un UUID v4 aléatoire
-	// 	log.Printf("WARN: Aucun userID dans c.Locals. Génération d'un UUID temporaire: %s", finalUserID)
-	// 	// Note : Quand l'auth sera obligatoire, retourner 401 ici au lieu de générer.
-	// }
-	// // --- FIN CORRECTION ---
-
 
 	// Appel du service avec le finalUserID (qui est maintenant un uuid.UUID)
-	log.Printf("INFO: Début analyse pour videoID: %s, userID: %s", videoID, finalUserID)
-	insight, err := h.commentService.AnalyzeAndSaveYouTubeComments(c.Context(), finalUserID, videoID)
+	log.Printf("INFO: Début analyse pour videoID: %s, userID: %s (force: %t)", videoID, finalUserID, policy.Force)
+	insight, cached, err := h.commentService.GetOrAnalyzeYouTubeComments(c.Context(), finalUserID, videoID, policy)
+	if errors.Is(err, services.ErrShuttingDown) {
+		c.Set(fiber.HeaderRetryAfter, "30")
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
 	if err != nil {
 		log.Printf("ERROR: Échec AnalyzeAndSaveYouTubeComments pour videoID %s, userID %s: %v", videoID, finalUserID, err)
 		// Réponse d'erreur structurée et plus générique pour le client
@@ -77,12 +81,49 @@ func (h *CommentHandler) GetComments(c *fiber.Ctx) error { // Gardons votre nom
 		})
 	}
 
+	// Un insight est immuable : son ID suffit comme ETag
+	c.Set(fiber.HeaderETag, insightETag(insight.ID))
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if cached {
+		if c.Fresh() { // If-None-Match correspond à l'insight courant
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+		log.Printf("INFO: Insight en cache retourné pour videoID: %s, userID %s. Insight ID: %s", videoID, finalUserID, insight.ID)
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"status":  "success",
+			"message": "Analyse récente retournée depuis le cache.",
+			"cached":  true,
+			"data":    insight,
+		})
+	}
+
 	// Réponse de succès structurée
 	log.Printf("INFO: Analyse réussie pour videoID: %s, userID %s. Insight ID: %s", videoID, finalUserID, insight.ID)
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{ // Utiliser 201 Created est sémantiquement mieux ici
 		"status":  "success",
 		"message": "Analyse terminée et sauvegardée.",
+		"cached":  false,
 		// Renommer "insights" en "data" est plus standard et utiliser l'objet insight directement
 		"data": insight,
 	})
-}
\ No newline at end of file
+}
+
+// parseMaxAge accepte une durée Go ("6h", "30m") ou un nombre de secondes
+func parseMaxAge(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, fmt.Errorf("max_age négatif")
+		}
+		return time.Duration(seconds) * time.Second, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("max_age invalide: %q", value)
+	}
+	return d, nil
+}
+
+// insightETag construit l'ETag (fort) d'un insight
+func insightETag(id uuid.UUID) string {
+	return `"` + id.String() + `"`
+}
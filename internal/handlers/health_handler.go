@@ -0,0 +1,35 @@
+// internal/handlers/health_handler.go
+package handlers
+
+import (
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Azertdev/FiberTest/internal/services"
+)
+
+type HealthHandler struct {
+	healthService services.HealthService
+}
+
+func NewHealthHandler(healthService services.HealthService) HealthHandler {
+	return HealthHandler{healthService}
+}
+
+// Liveness : le processus répond (ne dépend d'aucun service externe, pour éviter des redémarrages en cascade)
+func (h *HealthHandler) Liveness(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"status": "ok"})
+}
+
+// Readiness : l'instance peut recevoir du trafic (base joignable, pas d'arrêt en cours)
+func (h *HealthHandler) Readiness(c *fiber.Ctx) error {
+	if err := h.healthService.Ready(c.Context()); err != nil {
+		log.Printf("WARN: Readiness en échec: %v", err)
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"status":  "error",
+			"message": err.Error(),
+		})
+	}
+	return c.JSON(fiber.Map{"status": "ok"})
+}
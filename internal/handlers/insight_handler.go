@@ -0,0 +1,273 @@
+// internal/handlers/insight_handler.go
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/services"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+type InsightHandler struct {
+	insightService services.InsightService
+}
+
+func NewInsightHandler(insightService services.InsightService) InsightHandler {
+	return InsightHandler{insightService}
+}
+
+// InsightOwner sert de middleware.OwnerLookup pour les routes /insights/:id
+func (h *InsightHandler) InsightOwner(ctx context.Context, insightID uuid.UUID) (uuid.UUID, error) {
+	return h.insightService.InsightOwner(ctx, insightID)
+}
+
+// parseDateParam accepte une date RFC3339 ou AAAA-MM-JJ. endOfDay étend une date simple jusqu'à la fin de la journée.
+func parseDateParam(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+// Lister les insights de l'utilisateur courant
+// Query: page, page_size, video_id, from, to, sort (created_at|video_id), order (asc|desc)
+func (h *InsightHandler) ListInsights(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+
+	from, err := parseDateParam(c.Query("from"), false)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Paramètre 'from' invalide (RFC3339 ou AAAA-MM-JJ)"})
+	}
+	to, err := parseDateParam(c.Query("to"), true)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Paramètre 'to' invalide (RFC3339 ou AAAA-MM-JJ)"})
+	}
+
+	sortBy := c.Query("sort", "created_at")
+	if sortBy != "created_at" && sortBy != "video_id" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Paramètre 'sort' invalide (created_at ou video_id)"})
+	}
+	order := strings.ToLower(c.Query("order", "desc"))
+	if order != "asc" && order != "desc" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Paramètre 'order' invalide (asc ou desc)"})
+	}
+
+	page, pageSize := services.NormalizePagination(c.QueryInt("page", 1), c.QueryInt("page_size", services.DefaultInsightPageSize))
+	filter := repositories.InsightFilter{
+		UserID:   userID,
+		VideoID:  c.Query("video_id"),
+		From:     from,
+		To:       to,
+		SortBy:   sortBy,
+		SortDesc: order == "desc",
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	insights, total, err := h.insightService.ListInsights(c.Context(), filter)
+	if err != nil {
+		log.Printf("ERROR: Échec récupération insights pour userID %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Impossible de récupérer les insights"})
+	}
+
+	return c.JSON(fiber.Map{
+		"status": "success",
+		"data":   insights,
+		"pagination": fiber.Map{
+			"page":      page,
+			"page_size": pageSize,
+			"total":     total,
+		},
+	})
+}
+
+// Récupérer un insight par ID
+func (h *InsightHandler) GetInsight(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	insightID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "ID invalide"})
+	}
+
+	insight, err := h.insightService.GetInsight(c.Context(), userID, insightID)
+	if err != nil {
+		log.Printf("ERROR: Échec récupération insight %s pour userID %s: %v", insightID, userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Impossible de récupérer l'insight"})
+	}
+	if insight == nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Insight non trouvé"})
+	}
+	c.Set(fiber.HeaderETag, insightETag(insight.ID))
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.JSON(fiber.Map{"status": "success", "data": insight})
+}
+
+// Lister l'historique des insights d'une vidéo
+func (h *InsightHandler) GetVideoHistory(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	videoID := c.Params("videoId")
+
+	insights, err := h.insightService.GetVideoHistory(c.Context(), userID, videoID)
+	if err != nil {
+		log.Printf("ERROR: Échec récupération historique videoID %s pour userID %s: %v", videoID, userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Impossible de récupérer l'historique"})
+	}
+	return c.JSON(fiber.Map{"status": "success", "data": insights})
+}
+
+// Supprimer un insight
+func (h *InsightHandler) DeleteInsight(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	insightID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "ID invalide"})
+	}
+
+	if err := h.insightService.DeleteInsight(c.Context(), userID, insightID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Insight non trouvé"})
+		}
+		log.Printf("ERROR: Échec suppression insight %s pour userID %s: %v", insightID, userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Échec de la suppression"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Comparer deux versions de l'insight d'une vidéo
+// Query: from, to (numéros de version ; par défaut la dernière version et celle qui la précède)
+func (h *InsightHandler) DiffVersions(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	videoID := c.Params("videoId")
+	fromVersion := c.QueryInt("from", 0)
+	toVersion := c.QueryInt("to", 0)
+	if fromVersion < 0 || toVersion < 0 || (fromVersion > 0 && toVersion > 0 && fromVersion == toVersion) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Paramètres 'from'/'to' invalides"})
+	}
+
+	diff, err := h.insightService.DiffVersions(c.Context(), userID, videoID, fromVersion, toVersion)
+	if err != nil {
+		if errors.Is(err, services.ErrInsightVersionNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Version d'insight non trouvée"})
+		}
+		log.Printf("ERROR: Échec comparaison des versions de videoID %s pour userID %s: %v", videoID, userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Impossible de comparer les versions"})
+	}
+	return c.JSON(fiber.Map{"status": "success", "data": diff})
+}
+
+// Exporter un insight (?format=csv|json|md|pdf)
+func (h *InsightHandler) ExportInsight(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	insightID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "ID invalide"})
+	}
+	return h.exportInsights(c, userID, []uuid.UUID{insightID})
+}
+
+// Exporter un lot d'insights (?ids=id1,id2,...&format=csv|json|md|pdf)
+func (h *InsightHandler) ExportInsights(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+
+	var insightIDs []uuid.UUID
+	for _, rawID := range strings.Split(c.Query("ids"), ",") {
+		rawID = strings.TrimSpace(rawID)
+		if rawID == "" {
+			continue
+		}
+		insightID, err := uuid.Parse(rawID)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": fmt.Sprintf("ID invalide: %s", rawID)})
+		}
+		insightIDs = append(insightIDs, insightID)
+	}
+	if len(insightIDs) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Paramètre 'ids' manquant"})
+	}
+	if len(insightIDs) > services.MaxInsightExportBatch {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": fmt.Sprintf("Export limité à %d insights", services.MaxInsightExportBatch)})
+	}
+	return h.exportInsights(c, userID, insightIDs)
+}
+
+// exportInsights diffuse le rendu des insights. Le CSV liste les commentaires catégorisés retenus
+// dans l'insight (questions, critiques, points positifs, feedbacks), limités à
+// utils.MergeLimits.MaxListItems par catégorie : ce n'est pas l'export de tous les commentaires analysés.
+func (h *InsightHandler) exportInsights(c *fiber.Ctx, userID uuid.UUID, insightIDs []uuid.UUID) error {
+	format := strings.ToLower(c.Query("format", utils.ExportFormatJSON))
+	contentType, ok := utils.ExportContentTypes[format]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "Paramètre 'format' invalide (csv, json, md ou pdf)"})
+	}
+
+	insights, err := h.insightService.GetInsightsForExport(c.Context(), userID, insightIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrInsightNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Insight non trouvé"})
+		}
+		log.Printf("ERROR: Échec récupération des insights à exporter pour userID %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Impossible d'exporter les insights"})
+	}
+
+	filename := fmt.Sprintf("insights-%s.%s", time.Now().Format("20060102-150405"), format)
+	if len(insights) == 1 {
+		filename = fmt.Sprintf("insight-%s-v%d.%s", insights[0].VideoID, insights[0].Version, format)
+	}
+	c.Attachment(filename)
+	c.Set(fiber.HeaderContentType, contentType)
+
+	// Les insights sont déjà chargés : seul le rendu est envoyé en streaming
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := utils.WriteInsightsExport(w, format, insights); err != nil {
+			log.Printf("ERROR: Échec export %s des insights pour userID %s: %v", format, userID, err)
+		}
+		if err := w.Flush(); err != nil {
+			log.Printf("WARN: Export %s interrompu pour userID %s: %v", format, userID, err)
+		}
+	})
+	return nil
+}
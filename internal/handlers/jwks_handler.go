@@ -0,0 +1,22 @@
+// internal/handlers/jwks_handler.go
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Azertdev/FiberTest/internal/services"
+)
+
+type JWKSHandler struct {
+	tokenService services.TokenService
+}
+
+func NewJWKSHandler(tokenService services.TokenService) JWKSHandler {
+	return JWKSHandler{tokenService}
+}
+
+// GetJWKS publie les clés publiques de vérification des tokens (RS256/EdDSA) pour les autres services internes
+func (h *JWKSHandler) GetJWKS(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(fiber.Map{"keys": h.tokenService.JWKS()})
+}
@@ -0,0 +1,63 @@
+// internal/handlers/notification_handler.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/services"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+}
+
+func NewNotificationHandler(notificationService services.NotificationService) NotificationHandler {
+	return NotificationHandler{notificationService}
+}
+
+// NotificationOwner sert de middleware.OwnerLookup pour les routes /notifications/:id
+func (h *NotificationHandler) NotificationOwner(ctx context.Context, notificationID uuid.UUID) (uuid.UUID, error) {
+	return h.notificationService.NotificationOwner(ctx, notificationID)
+}
+
+// Lister les notifications de l'utilisateur courant (?unread=true pour les non lues)
+func (h *NotificationHandler) ListNotifications(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+
+	notifications, err := h.notificationService.ListNotifications(c.Context(), userID, c.QueryBool("unread", false))
+	if err != nil {
+		log.Printf("ERROR: Échec récupération notifications pour userID %s: %v", userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Impossible de récupérer les notifications"})
+	}
+	return c.JSON(fiber.Map{"status": "success", "data": notifications})
+}
+
+// Marquer une notification comme lue
+func (h *NotificationHandler) MarkAsRead(c *fiber.Ctx) error {
+	userID, ok := scopeUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	notificationID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "ID invalide"})
+	}
+
+	if err := h.notificationService.MarkAsRead(c.Context(), userID, notificationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Notification non trouvée"})
+		}
+		log.Printf("ERROR: Échec mise à jour notification %s pour userID %s: %v", notificationID, userID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Échec de la mise à jour"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -0,0 +1,126 @@
+// internal/handlers/oauth_handler.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/Azertdev/FiberTest/internal/services"
+)
+
+// Cookie portant l'état chiffré du flux OAuth (state, code_verifier PKCE, nonce) jusqu'au callback
+const oauthFlowCookie = "oauth_flow"
+
+type OAuthHandler struct {
+	oauthService services.OAuthService
+	tokenService services.TokenService
+}
+
+func NewOAuthHandler(oauthService services.OAuthService, tokenService services.TokenService) OAuthHandler {
+	return OAuthHandler{oauthService, tokenService}
+}
+
+// Connexion : redirige vers la page d'autorisation du fournisseur
+func (h *OAuthHandler) Login(c *fiber.Ctx) error {
+	authURL, err := h.begin(c, uuid.Nil)
+	if err != nil {
+		return err
+	}
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// Liaison d'un fournisseur au compte connecté : l'URL est retournée en JSON
+// (une navigation ne transporte pas l'en-tête Authorization)
+func (h *OAuthHandler) Link(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	authURL, err := h.begin(c, userID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(fiber.Map{"status": "success", "authorization_url": authURL})
+}
+
+func (h *OAuthHandler) begin(c *fiber.Ctx, linkUserID uuid.UUID) (string, error) {
+	authURL, sealedFlow, err := h.oauthService.BeginLogin(c.Context(), c.Params("provider"), linkUserID)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownOAuthProvider) {
+			return "", c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": err.Error()})
+		}
+		log.Printf("ERROR: Échec du démarrage du flux OAuth %s: %v", c.Params("provider"), err)
+		return "", c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"status": "error", "message": "Fournisseur d'identité indisponible"})
+	}
+	c.Cookie(&fiber.Cookie{
+		Name:     oauthFlowCookie,
+		Value:    sealedFlow,
+		Path:     "/auth/oauth",
+		Expires:  time.Now().Add(services.OAuthFlowLifetime),
+		HTTPOnly: true,
+		Secure:   c.Protocol() == "https",
+		SameSite: fiber.CookieSameSiteLaxMode, // le callback est une navigation depuis le fournisseur
+	})
+	return authURL, nil
+}
+
+// Callback : vérifie l'état, échange le code et délivre les tokens EngageSense
+func (h *OAuthHandler) Callback(c *fiber.Ctx) error {
+	provider := c.Params("provider")
+	sealedFlow := c.Cookies(oauthFlowCookie)
+	c.ClearCookie(oauthFlowCookie)
+
+	if providerError := c.Query("error"); providerError != "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Autorisation refusée: " + providerError})
+	}
+	if c.Query("code") == "" || c.Query("state") == "" || sealedFlow == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": services.ErrInvalidOAuthState.Error()})
+	}
+
+	user, err := h.oauthService.CompleteLogin(c.Context(), provider, c.Query("state"), c.Query("code"), sealedFlow)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnknownOAuthProvider):
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": err.Error()})
+		case errors.Is(err, services.ErrInvalidOAuthState), errors.Is(err, services.ErrOAuthEmailMissing):
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": err.Error()})
+		case errors.Is(err, services.ErrOAuthIdentityLinked), errors.Is(err, services.ErrOAuthAccountUnverified):
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"status": "error", "message": err.Error()})
+		}
+		log.Printf("ERROR: Échec du callback OAuth %s: %v", provider, err)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Échec de la connexion"})
+	}
+
+	tokens, err := h.tokenService.IssueTokens(c.Context(), user)
+	if err != nil {
+		log.Printf("ERROR: Échec création des tokens pour userID %s: %v", user.ID, err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Échec de la création du token"})
+	}
+	return c.JSON(tokens)
+}
+
+// Vidéos de la chaîne YouTube liée (privées et non répertoriées comprises)
+func (h *OAuthHandler) ListMyYouTubeVideos(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+	}
+	maxResults := int64(c.QueryInt("max_results", 25))
+	if maxResults < 1 || maxResults > 50 {
+		maxResults = 25
+	}
+
+	videos, err := h.oauthService.ListMyYouTubeVideos(c.Context(), userID, maxResults)
+	if err != nil {
+		if errors.Is(err, services.ErrNoYouTubeAccess) {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"status": "error", "message": err.Error()})
+		}
+		log.Printf("ERROR: Échec récupération des vidéos YouTube pour userID %s: %v", userID, err)
+		return c.Status(fiber.StatusBadGateway).JSON(fiber.Map{"status": "error", "message": "Échec de la récupération des vidéos YouTube"})
+	}
+	return c.JSON(fiber.Map{"status": "success", "data": videos})
+}
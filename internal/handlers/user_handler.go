@@ -1,82 +1,404 @@
 package handlers
 
 import (
+	"errors"
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/Azertdev/FiberTest/internal/middleware"
 	"github.com/Azertdev/FiberTest/internal/models"
 	"github.com/Azertdev/FiberTest/internal/services"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type UserHandler struct {
-	userService services.UserService
+	userService    services.UserService
+	tokenService   services.TokenService
+	accountService services.AccountService
+	loginGuard     services.LoginGuardService
+}
+
+func NewUserHandler(userService services.UserService, tokenService services.TokenService, accountService services.AccountService, loginGuard services.LoginGuardService) UserHandler {
+	return UserHandler{userService, tokenService, accountService, loginGuard}
 }
 
-func NewUserHandler(userService services.UserService) UserHandler {
-	return UserHandler{userService}
+// createUserRequest : champs acceptés à l'inscription (le rôle n'en fait pas partie)
+type createUserRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email"`
+	Password string `json:"password"`
 }
 
 // Créer un utilisateur
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
-	user := new(models.User)
-	if err := c.BodyParser(user); err != nil {
+	body := new(createUserRequest)
+	if err := c.BodyParser(body); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
 	}
+	user := &models.User{
+		Username: body.Username,
+		Email:    body.Email,
+		Password: body.Password,
+		Role:     models.RoleUser,
+	}
 
 	if err := user.Validate(); err != nil {
 		// Retourner une erreur si la validation échoue
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	err := h.userService.CreateUser(user)
+	err := h.userService.CreateUser(c.Context(), user)
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Échec de la création"})
 	}
+	// Le compte existe : un échec d'envoi se rattrape via /users/me/verify-email
+	if err := h.accountService.SendEmailVerification(c.Context(), user.ID); err != nil {
+		log.Printf("WARN: Échec envoi de l'email de vérification pour userID %s: %v", user.ID, err)
+	}
 	return c.Status(201).JSON("user created succesfully")
 }
 
 // Récupérer tous les utilisateurs
 func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
-	users, err := h.userService.GetAllUsers()
+	users, err := h.userService.GetAllUsers(c.Context())
 	if err != nil {
 		return c.Status(500).JSON(fiber.Map{"error": "Impossible de récupérer les utilisateurs"})
 	}
-	return c.JSON(users)
+	return c.JSON(models.NewUserResponses(users))
 }
 
 // Récupérer un utilisateur par ID
 func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
-	id, err := c.ParamsInt("id")
+	id, err := uuid.Parse(c.Params("id"))
 	if err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "ID invalide"})
 	}
-	user, err := h.userService.GetUserByID(uint(id))
+	user, err := h.userService.GetUserByID(c.Context(), id)
 	if err != nil {
 		return c.Status(404).JSON(fiber.Map{"error": "Utilisateur non trouvé"})
 	}
-	return c.JSON(user)
+	return c.JSON(models.NewUserResponse(user))
+}
+
+type loginRequest struct {
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 func (h *UserHandler) LoginHandler(c *fiber.Ctx) error {
-	user := new(models.User)
+	user := new(loginRequest)
 	if err := c.BodyParser(user); err != nil {
 		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
 	}
-	if err := user.Validate(); err != nil {
+	if err := models.ValidateRequest(user); err != nil {
 		// Retourner une erreur si la validation échoue
 		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
 	}
 
-	userAuth, err := h.userService.AuthenticateUser(user.Username, user.Password)
+	// Protection brute-force : délai progressif puis verrouillage, par compte et par IP
+	if err := h.loginGuard.ReserveLogin(c.Context(), user.Username, c.IP()); err != nil {
+		var throttled *services.LoginThrottledError
+		if errors.As(err, &throttled) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": throttled.Error()})
+		}
+		log.Printf("ERROR: Échec vérification des tentatives de connexion pour '%s': %v", user.Username, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Erreur interne"})
+	}
+
+	userAuth, err := h.userService.AuthenticateUser(c.Context(), user.Username, user.Password)
 	if err != nil {
+		if err := h.loginGuard.RecordLoginFailure(c.Context(), user.Username, c.IP()); err != nil {
+			log.Printf("ERROR: Échec enregistrement de l'échec de connexion pour '%s': %v", user.Username, err)
+		}
 		return c.Status(401).JSON(fiber.Map{"error": "Échec de la connexion"})
 	}
+	if err := h.loginGuard.RecordLoginSuccess(c.Context(), user.Username, c.IP()); err != nil {
+		log.Printf("WARN: Échec réinitialisation des tentatives de connexion pour '%s': %v", user.Username, err)
+	}
 
-	// Générer le token JWT
-	token, err := h.userService.GenerateJWT(userAuth.ID)
+	// Générer le token d'accès JWT et le refresh token
+	tokens, err := h.tokenService.IssueTokens(c.Context(), userAuth)
 	if err != nil {
+		log.Printf("ERROR: Échec création des tokens pour userID %s: %v", userAuth.ID, err)
 		return c.Status(500).JSON(fiber.Map{"error": "Échec de la création du token"})
 	}
 
-	// Renvoyer le token dans la réponse JSON
-	return c.JSON(fiber.Map{"token": token})
+	// Renvoyer les tokens dans la réponse JSON
+	return c.JSON(tokens)
+}
+
+type updateRoleRequest struct {
+	Role string `json:"role"`
+}
+
+// Changer le rôle d'un utilisateur (admin uniquement)
+func (h *UserHandler) UpdateUserRole(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Utilisateur non authentifié"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "ID invalide"})
+	}
+	// Évite qu'un admin se retire lui-même ses droits (et qu'il ne reste plus aucun admin)
+	if id == principal.UserID {
+		return c.Status(400).JSON(fiber.Map{"error": "Impossible de modifier son propre rôle"})
+	}
+	body := new(updateRoleRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+
+	if err := h.userService.UpdateUserRole(c.Context(), id, body.Role); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRole):
+			return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			return c.Status(404).JSON(fiber.Map{"error": "Utilisateur non trouvé"})
+		}
+		log.Printf("ERROR: Échec changement de rôle pour userID %s: %v", id, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Échec de la mise à jour du rôle"})
+	}
+	log.Printf("INFO: Rôle de l'utilisateur %s changé en '%s' par l'admin %s", id, body.Role, principal.UserID)
+	return c.JSON(fiber.Map{"id": id, "role": body.Role})
+}
+
+// --- Self-service (/users/me) ---
+
+// Récupérer le profil de l'utilisateur courant
+func (h *UserHandler) GetMe(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Utilisateur non authentifié"})
+	}
+	user, err := h.userService.GetUserByID(c.Context(), userID)
+	if err != nil {
+		return c.Status(404).JSON(fiber.Map{"error": "Utilisateur non trouvé"})
+	}
+	return c.JSON(models.NewUserResponse(user))
+}
+
+// Modifier le profil de l'utilisateur courant
+func (h *UserHandler) UpdateMe(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Utilisateur non authentifié"})
+	}
+	body := new(models.UpdateProfileRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+	if err := models.ValidateRequest(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	user, err := h.userService.UpdateProfile(c.Context(), userID, *body)
+	if err != nil {
+		return userUpdateError(c, userID, err)
+	}
+	return c.JSON(models.NewUserResponse(user))
+}
+
+// Changer le mot de passe (mot de passe actuel requis). Les autres sessions sont déconnectées.
+func (h *UserHandler) ChangePassword(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Utilisateur non authentifié"})
+	}
+	body := new(models.ChangePasswordRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+	if err := models.ValidateRequest(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.userService.ChangePassword(c.Context(), userID, *body); err != nil {
+		return userUpdateError(c, userID, err)
+	}
+	if err := h.tokenService.RevokeAllSessions(c.Context(), userID); err != nil {
+		log.Printf("WARN: Mot de passe changé mais sessions non révoquées pour userID %s: %v", userID, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Changer l'adresse email (mot de passe actuel requis)
+func (h *UserHandler) ChangeEmail(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Utilisateur non authentifié"})
+	}
+	body := new(models.ChangeEmailRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+	if err := models.ValidateRequest(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	user, err := h.userService.ChangeEmail(c.Context(), userID, *body)
+	if err != nil {
+		return userUpdateError(c, userID, err)
+	}
+	if err := h.accountService.SendEmailVerification(c.Context(), userID); err != nil {
+		log.Printf("WARN: Échec envoi de l'email de vérification pour userID %s: %v", userID, err)
+	}
+	return c.JSON(models.NewUserResponse(user))
+}
+
+// Supprimer son compte et toutes ses données (mot de passe actuel requis)
+func (h *UserHandler) DeleteMe(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Utilisateur non authentifié"})
+	}
+	body := new(models.DeleteAccountRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+	if err := models.ValidateRequest(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.userService.DeleteAccount(c.Context(), userID, body.CurrentPassword); err != nil {
+		return userUpdateError(c, userID, err)
+	}
+	log.Printf("INFO: Compte %s supprimé avec ses données", userID)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// --- Vérification d'email et réinitialisation du mot de passe ---
+
+// Renvoyer le lien de vérification de l'adresse email courante
+func (h *UserHandler) ResendEmailVerification(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Utilisateur non authentifié"})
+	}
+	if err := h.accountService.SendEmailVerification(c.Context(), userID); err != nil {
+		if errors.Is(err, services.ErrEmailAlreadyVerified) {
+			return c.Status(409).JSON(fiber.Map{"error": err.Error()})
+		}
+		log.Printf("ERROR: Échec envoi de l'email de vérification pour userID %s: %v", userID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Échec de l'envoi de l'email"})
+	}
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// Confirmer l'adresse email avec le token reçu
+func (h *UserHandler) ConfirmEmail(c *fiber.Ctx) error {
+	body := new(models.ConfirmEmailRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+	if err := models.ValidateRequest(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.accountService.ConfirmEmail(c.Context(), body.Token); err != nil {
+		return accountTokenError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Demander un lien de réinitialisation du mot de passe (réponse identique que l'adresse existe ou non)
+func (h *UserHandler) RequestPasswordReset(c *fiber.Ctx) error {
+	body := new(models.PasswordResetRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+	if err := models.ValidateRequest(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.accountService.RequestPasswordReset(c.Context(), body.Email); err != nil {
+		log.Printf("ERROR: Échec de la demande de réinitialisation: %v", err)
+	}
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// Choisir un nouveau mot de passe avec le token reçu
+func (h *UserHandler) ConfirmPasswordReset(c *fiber.Ctx) error {
+	body := new(models.ConfirmPasswordResetRequest)
+	if err := c.BodyParser(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+	if err := models.ValidateRequest(body); err != nil {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+
+	if err := h.accountService.ResetPassword(c.Context(), body.Token, body.NewPassword); err != nil {
+		return accountTokenError(c, err)
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func accountTokenError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrInvalidAccountToken) {
+		return c.Status(400).JSON(fiber.Map{"error": err.Error()})
+	}
+	log.Printf("ERROR: Échec de la consommation d'un token de compte: %v", err)
+	return c.Status(500).JSON(fiber.Map{"error": "Erreur interne"})
+}
+
+// userUpdateError traduit les erreurs du self-service en réponses HTTP
+func userUpdateError(c *fiber.Ctx, userID uuid.UUID, err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidPassword):
+		return c.Status(403).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(404).JSON(fiber.Map{"error": "Utilisateur non trouvé"})
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return c.Status(409).JSON(fiber.Map{"error": "Nom d'utilisateur ou email déjà utilisé"})
+	}
+	log.Printf("ERROR: Échec mise à jour du compte %s: %v", userID, err)
+	return c.Status(500).JSON(fiber.Map{"error": "Échec de la mise à jour du compte"})
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// Échanger un refresh token contre une nouvelle paire de tokens (rotation)
+func (h *UserHandler) RefreshHandler(c *fiber.Ctx) error {
+	body := new(refreshRequest)
+	if err := c.BodyParser(body); err != nil || body.RefreshToken == "" {
+		return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+	}
+
+	tokens, err := h.tokenService.Refresh(c.Context(), body.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
+			return c.Status(401).JSON(fiber.Map{"error": "Refresh token invalide"})
+		}
+		log.Printf("ERROR: Échec du renouvellement des tokens: %v", err)
+		return c.Status(500).JSON(fiber.Map{"error": "Échec du renouvellement du token"})
+	}
+	return c.JSON(tokens)
+}
+
+// Déconnexion : révoque le token d'accès courant et la famille du refresh token fourni
+func (h *UserHandler) LogoutHandler(c *fiber.Ctx) error {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{"error": "Utilisateur non authentifié"})
+	}
+	body := new(refreshRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(body); err != nil {
+			return c.Status(400).JSON(fiber.Map{"error": "Données invalides"})
+		}
+	}
+
+	if err := h.tokenService.Logout(c.Context(), principal, body.RefreshToken); err != nil {
+		log.Printf("ERROR: Échec de la déconnexion pour userID %s: %v", principal.UserID, err)
+		return c.Status(500).JSON(fiber.Map{"error": "Échec de la déconnexion"})
+	}
+	return c.SendStatus(fiber.StatusNoContent)
 }
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/Azertdev/FiberTest/internal/services"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// En-tête alternatif pour les clés d'API (l'en-tête "Authorization: Bearer es_..." est aussi accepté)
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator vérifie une clé d'API et retourne l'identité correspondante
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (utils.Principal, error)
+}
+
+// NewAPIKeyMiddleware accepte une clé d'API comme alternative au JWT ; sans clé d'API dans
+// la requête, elle est confiée à jwtMiddleware. À combiner avec RequireScope sur chaque route.
+func NewAPIKeyMiddleware(apiKeys APIKeyAuthenticator, jwtMiddleware fiber.Handler) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rawKey := c.Get(APIKeyHeader)
+		if rawKey == "" {
+			if bearer, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer "); ok && services.IsAPIKeyToken(bearer) {
+				rawKey = bearer
+			}
+		}
+		if rawKey == "" {
+			return jwtMiddleware(c)
+		}
+
+		principal, err := apiKeys.AuthenticateAPIKey(c.Context(), rawKey)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Clé d'API invalide"})
+		}
+		c.Locals(PrincipalKey, principal)
+		return c.Next()
+	}
+}
+
+// RequireScope n'autorise une clé d'API que si elle possède le scope ; un JWT passe toujours.
+// Doit être placé après NewAPIKeyMiddleware.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+		}
+		if !principal.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"status": "error", "message": "Scope requis: " + scope})
+		}
+		return c.Next()
+	}
+}
+
+// RequireJWT refuse les clés d'API sur une route qui ne doit pas être scriptable
+func RequireJWT() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+		}
+		if principal.IsAPIKey() {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"status": "error", "message": "Opération non autorisée avec une clé d'API"})
+		}
+		return c.Next()
+	}
+}
@@ -1,34 +1,63 @@
 package middleware
 
 import (
+	"context"
+	"log"
+
 	"github.com/Azertdev/FiberTest/internal/utils"
 	"github.com/gofiber/fiber/v2"
-	"github.com/golang-jwt/jwt"
 )
 
-// Middleware pour vérifier le JWT dans l'en-tête Authorization
-func JWTMiddleware(c *fiber.Ctx) error {
-	// Récupérer le token depuis l'en-tête Authorization
-	authHeader := c.Get("Authorization")
-	if authHeader == "" || len(authHeader) < 7 || authHeader[:7] != "Bearer " {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Token manquant"})
-	}
+// Clé de c.Locals contenant le utils.Principal de la requête authentifiée
+const PrincipalKey = "principal"
 
-	tokenString := authHeader[7:]
+// AccessTokenVerifier valide un token d'accès et indique s'il a été révoqué (déconnexion) d'après son jti
+type AccessTokenVerifier interface {
+	VerifyAccessToken(tokenString string) (*utils.Claims, error)
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+}
 
-	// Vérification du token
-	token, err := utils.VerifyJWT(tokenString)
-	if err != nil || !token.Valid {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Token invalide"})
-	}
+// NewJWTMiddleware retourne le middleware qui vérifie le JWT de l'en-tête Authorization
+func NewJWTMiddleware(tokens AccessTokenVerifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Récupérer le token depuis l'en-tête Authorization
+		authHeader := c.Get("Authorization")
+		if authHeader == "" || len(authHeader) < 7 || authHeader[:7] != "Bearer " {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Token manquant"})
+		}
+
+		tokenString := authHeader[7:]
 
-	// Extraction des claims
-	if claims, ok := token.Claims.(jwt.MapClaims); ok {
-		// Attacher les informations du token au contexte
-		c.Locals("username", claims["username"])
-		c.Locals("userId", claims["id"]) // Si tu stockes un ID dans le token
+		// Vérification du token
+		claims, err := tokens.VerifyAccessToken(tokenString)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Token invalide"})
+		}
+
+		// Le sujet doit être l'UUID de l'utilisateur
+		principal, err := claims.Principal()
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Token invalide"})
+		}
+
+		// Liste de révocation (tokens d'accès invalidés par une déconnexion)
+		revoked, err := tokens.IsAccessTokenRevoked(c.Context(), principal.TokenID)
+		if err != nil {
+			log.Printf("ERROR: Échec vérification de révocation du token %s: %v", principal.TokenID, err)
+			return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Vérification du token impossible"})
+		}
+		if revoked {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Token révoqué"})
+		}
+
+		// Attacher l'identité typée au contexte
+		c.Locals(PrincipalKey, principal)
 		return c.Next()
 	}
+}
 
-	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Accès non autorisé"})
+// CurrentPrincipal retourne l'identité placée par le middleware JWT (false si la requête n'est pas authentifiée)
+func CurrentPrincipal(c *fiber.Ctx) (utils.Principal, bool) {
+	principal, ok := c.Locals(PrincipalKey).(utils.Principal)
+	return principal, ok
 }
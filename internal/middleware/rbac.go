@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// Clé de c.Locals contenant l'UUID du propriétaire de la ressource autorisée par RequireOwnerOrAdmin
+const ResourceOwnerKey = "resourceOwner"
+
+// OwnerLookup retourne l'UUID du propriétaire de la ressource (gorm.ErrRecordNotFound si elle n'existe pas)
+type OwnerLookup func(ctx context.Context, resourceID uuid.UUID) (uuid.UUID, error)
+
+// RequireRole n'autorise que les utilisateurs authentifiés ayant l'un des rôles donnés.
+// Doit être placé après le middleware JWT.
+func RequireRole(roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+		}
+		for _, role := range roles {
+			if principal.Role == role {
+				return c.Next()
+			}
+		}
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"status": "error", "message": "Accès refusé"})
+	}
+}
+
+// RequireOwnerOrAdmin n'autorise l'accès à la ressource :<param> qu'à son propriétaire ou à un admin.
+// Le propriétaire est placé dans c.Locals(ResourceOwnerKey) pour que le handler limite ses requêtes à ce compte.
+func RequireOwnerOrAdmin(param string, lookup OwnerLookup) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+		}
+		resourceID, err := uuid.Parse(c.Params(param))
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"status": "error", "message": "ID invalide"})
+		}
+
+		ownerID, err := lookup(c.Context(), resourceID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Ressource non trouvée"})
+			}
+			log.Printf("ERROR: Échec récupération du propriétaire de la ressource %s: %v", resourceID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Erreur interne"})
+		}
+
+		// Un utilisateur ne doit pas apprendre l'existence des ressources des autres : 404 plutôt que 403
+		if ownerID != principal.UserID && principal.Role != models.RoleAdmin {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"status": "error", "message": "Ressource non trouvée"})
+		}
+		c.Locals(ResourceOwnerKey, ownerID)
+		return c.Next()
+	}
+}
+
+// ResourceOwner retourne le propriétaire autorisé par RequireOwnerOrAdmin
+func ResourceOwner(c *fiber.Ctx) (uuid.UUID, bool) {
+	ownerID, ok := c.Locals(ResourceOwnerKey).(uuid.UUID)
+	return ownerID, ok
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// EmailVerificationChecker indique si l'utilisateur a confirmé son adresse email
+type EmailVerificationChecker interface {
+	IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+// RequireVerifiedEmail bloque les comptes dont l'adresse email n'a pas été confirmée.
+// Doit être placé après le middleware JWT.
+func RequireVerifiedEmail(checker EmailVerificationChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := CurrentPrincipal(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"status": "error", "message": "Utilisateur non authentifié"})
+		}
+		verified, err := checker.IsEmailVerified(c.Context(), principal.UserID)
+		if err != nil {
+			log.Printf("ERROR: Échec vérification de l'email de userID %s: %v", principal.UserID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"status": "error", "message": "Erreur interne"})
+		}
+		if !verified {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"status": "error", "message": "Adresse email non vérifiée"})
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,302 @@
+// internal/migrations/migrations.go
+package migrations
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Les migrations sont des fichiers sql/<version>_<nom>.up.sql et sql/<version>_<nom>.down.sql,
+// embarqués dans le binaire. Règles :
+//   - ne jamais modifier une migration déjà appliquée : en ajouter une nouvelle ;
+//   - une valeur d'ENUM s'ajoute avec ALTER TYPE ... ADD VALUE IF NOT EXISTS, dans une migration
+//     distincte de celles qui l'utilisent (PostgreSQL refuse une valeur ajoutée dans la même transaction) ;
+//   - un fichier commençant par "-- migrate:no-transaction" est exécuté hors transaction
+//     (CREATE INDEX CONCURRENTLY...) : il doit alors être idempotent (IF NOT EXISTS).
+//
+//go:embed sql/*.sql
+var embedded embed.FS
+
+const noTransactionDirective = "-- migrate:no-transaction"
+
+// advisoryLockKey identifie le verrou pg_advisory_lock partagé par toutes les instances
+const advisoryLockKey int64 = 0x656e676167656d67 // "engagemg"
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+var ErrNothingToRollback = errors.New("aucune migration à annuler")
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// MigrationStatus : une migration connue du binaire et/ou enregistrée en base
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time // nil : en attente
+	Missing   bool       // appliquée en base mais absente du binaire (binaire plus ancien que la base)
+}
+
+// schemaMigration est la ligne enregistrée dans schema_migrations
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string { return "schema_migrations" }
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+// NewMigrator utilise les migrations embarquées dans le binaire
+func NewMigrator(db *gorm.DB) (*Migrator, error) {
+	sqlFS, err := fs.Sub(embedded, "sql")
+	if err != nil {
+		return nil, err
+	}
+	return NewMigratorFromFS(db, sqlFS)
+}
+
+func NewMigratorFromFS(db *gorm.DB, fsys fs.FS) (*Migrator, error) {
+	if db == nil {
+		log.Fatal("ERREUR FATALE: Connexion manquante lors de la création du Migrator")
+	}
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load lit et valide les fichiers de migration (chaque version doit avoir un up et un down)
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("nom de migration invalide: %s (attendu <version>_<nom>.up|down.sql)", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("version %d utilisée par deux migrations: %s et %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			return nil, fmt.Errorf("migration %s: fichiers .up.sql et .down.sql requis", migration)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Status retourne l'état de chaque migration, par version croissante
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		statuses = m.statuses(applied)
+		return nil
+	})
+	return statuses, err
+}
+
+// Up applique les migrations en attente jusqu'à target inclus (0 : toutes)
+func (m *Migrator) Up(ctx context.Context, target int64) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if target > 0 && migration.Version > target {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.run(conn, migration, migration.Up, func(tx *gorm.DB) error {
+				return tx.Create(&schemaMigration{Version: migration.Version, Name: migration.Name, AppliedAt: time.Now()}).Error
+			}); err != nil {
+				return err
+			}
+			log.Printf("INFO: Migration %s appliquée", migration)
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down annule les steps dernières migrations appliquées
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			return ErrNothingToRollback
+		}
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+		if steps > len(versions) {
+			steps = len(versions)
+		}
+
+		for _, version := range versions[:steps] {
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("migration %d appliquée en base mais absente de ce binaire: annulation impossible", version)
+			}
+			if err := m.run(conn, migration, migration.Down, func(tx *gorm.DB) error {
+				return tx.Delete(&schemaMigration{}, version).Error
+			}); err != nil {
+				return err
+			}
+			log.Printf("INFO: Migration %s annulée", migration)
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return done, err
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration, true
+		}
+	}
+	return Migration{}, false
+}
+
+func (m *Migrator) statuses(applied map[int64]schemaMigration) []MigrationStatus {
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	known := make(map[int64]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+		status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	for version, row := range applied {
+		if !known[version] {
+			appliedAt := row.AppliedAt
+			statuses = append(statuses, MigrationStatus{Version: version, Name: row.Name, AppliedAt: &appliedAt, Missing: true})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses
+}
+
+// run exécute le script et l'enregistrement dans la même transaction, sauf directive no-transaction
+func (m *Migrator) run(conn *gorm.DB, migration Migration, script string, record func(tx *gorm.DB) error) error {
+	if strings.HasPrefix(strings.TrimSpace(script), noTransactionDirective) {
+		if err := conn.Exec(script).Error; err != nil {
+			return fmt.Errorf("migration %s: %w", migration, err)
+		}
+		if err := record(conn); err != nil {
+			return fmt.Errorf("migration %s exécutée mais non enregistrée: %w", migration, err)
+		}
+		return nil
+	}
+	return conn.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(script).Error; err != nil {
+			return fmt.Errorf("migration %s: %w", migration, err)
+		}
+		return record(tx)
+	})
+}
+
+// withLock sérialise les migrations entre instances : le verrou consultatif est pris sur une
+// connexion dédiée et libéré avec elle, même en cas d'erreur
+func (m *Migrator) withLock(ctx context.Context, fc func(conn *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", advisoryLockKey).Error; err != nil {
+			return fmt.Errorf("échec de l'acquisition du verrou de migration: %w", err)
+		}
+		defer func() {
+			// Contexte indépendant : le verrou doit être libéré même si ctx est annulé
+			if err := conn.WithContext(context.Background()).Exec("SELECT pg_advisory_unlock(?)", advisoryLockKey).Error; err != nil {
+				log.Printf("WARN: Échec de la libération du verrou de migration: %v", err)
+			}
+		}()
+		if err := conn.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+			version bigint PRIMARY KEY,
+			name text NOT NULL,
+			applied_at timestamptz NOT NULL DEFAULT now()
+		)`).Error; err != nil {
+			return fmt.Errorf("échec de la création de schema_migrations: %w", err)
+		}
+		return fc(conn)
+	})
+}
+
+func appliedMigrations(conn *gorm.DB) (map[int64]schemaMigration, error) {
+	var rows []schemaMigration
+	if err := conn.Order("version").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("échec de la lecture de schema_migrations: %w", err)
+	}
+	applied := make(map[int64]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
@@ -0,0 +1,194 @@
+package migrations
+
+import (
+	"io/fs"
+	"os"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// TEST_POSTGRES_URL active les tests sur PostgreSQL. ATTENTION : le schéma public de cette base
+// est supprimé avant chaque test, ne jamais la faire pointer vers une base réelle. Les tests des
+// repositories utilisent la même base : lancer go test -p 1 ./... pour ne pas les exécuter en parallèle.
+const testPostgresURLEnv = "TEST_POSTGRES_URL"
+
+// baselineSchema reproduit la base créée par l'ancien InitDB (types ENUM puis AutoMigrate des
+// cinq premiers modèles), avant toute migration versionnée
+const baselineSchema = `
+CREATE TYPE user_role AS ENUM ('user', 'admin');
+CREATE TYPE user_platform AS ENUM ('instagram', 'twitter', 'youtube');
+CREATE TYPE subscription_plan AS ENUM ('free', 'pro', 'business');
+CREATE TYPE subscription_Status AS ENUM ('active', 'cancelled');
+CREATE TYPE Notification_Type AS ENUM ('analysis', 'payment', 'alert');
+
+CREATE TABLE users (
+	id uuid DEFAULT gen_random_uuid(),
+	username varchar(50) NOT NULL,
+	email varchar(100) NOT NULL,
+	password text NOT NULL,
+	role user_role NOT NULL DEFAULT 'user',
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id),
+	CONSTRAINT uni_users_username UNIQUE (username),
+	CONSTRAINT uni_users_email UNIQUE (email)
+);
+CREATE TABLE subscriptions (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	plan subscription_plan NOT NULL DEFAULT 'free',
+	status subscription_Status NOT NULL DEFAULT 'active',
+	expires_at timestamptz,
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX idx_subscriptions_user_id ON subscriptions (user_id);
+CREATE TABLE comments (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	video_id varchar(255) NOT NULL,
+	platform user_platform NOT NULL DEFAULT 'youtube',
+	content text NOT NULL,
+	author varchar(255) NOT NULL,
+	date timestamp NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX idx_comments_user_id ON comments (user_id);
+CREATE TABLE insights (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	video_id text NOT NULL,
+	sentiment text,
+	summary text,
+	top_comments jsonb,
+	negative_comments jsonb,
+	question_comments jsonb,
+	feedback_comments jsonb,
+	keywords jsonb,
+	transcript_summary text,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX idx_insights_user_id ON insights (user_id);
+CREATE INDEX idx_insights_video_id ON insights (video_id);
+CREATE TABLE notifications (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	type Notification_Type NOT NULL,
+	message text NOT NULL,
+	is_read boolean DEFAULT false,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX idx_notifications_user_id ON notifications (user_id);
+
+INSERT INTO users (id, username, email, password, created_at)
+VALUES ('00000000-0000-0000-0000-000000000001', 'alice', 'alice@example.com', 'hash', now() - interval '1 day');
+INSERT INTO comments (user_id, video_id, content, author, date)
+VALUES ('00000000-0000-0000-0000-000000000001', 'vid', 'Super vidéo', 'bob', now());
+INSERT INTO insights (user_id, video_id, summary, created_at) VALUES
+	('00000000-0000-0000-0000-000000000001', 'vid', 'première', now() - interval '2 hours'),
+	('00000000-0000-0000-0000-000000000001', 'vid', 'seconde', now() - interval '1 hour');
+`
+
+func openBaselineDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	url := os.Getenv(testPostgresURLEnv)
+	if url == "" {
+		t.Skipf("%s non défini", testPostgresURLEnv)
+	}
+	db, err := gorm.Open(postgres.Open(url), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("ouverture postgres: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	for _, statement := range []string{"DROP SCHEMA public CASCADE", "CREATE SCHEMA public", baselineSchema} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("schéma d'origine: %v", err)
+		}
+	}
+	return db
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	sqlFS, err := fs.Sub(embedded, "sql")
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrations, err := Load(sqlFS)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Fatalf("migration %s : versions non consécutives", migration)
+		}
+	}
+}
+
+func TestMigrationsUpgradeBaselineSchemaThenRollBack(t *testing.T) {
+	db := openBaselineDB(t)
+	ctx := t.Context()
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+
+	applied, err := migrator.Up(ctx, 0)
+	if err != nil {
+		t.Fatalf("Up depuis le schéma d'origine: %v", err)
+	}
+	if len(applied) != len(migrator.migrations) {
+		t.Fatalf("%d migrations appliquées sur %d", len(applied), len(migrator.migrations))
+	}
+
+	// Colonnes ajoutées depuis le schéma d'origine, données existantes conservées
+	var unverified int64
+	db.Raw("SELECT COUNT(*) FROM users WHERE email_verified_at IS NULL").Scan(&unverified)
+	if unverified != 0 {
+		t.Fatalf("%d compte(s) existant(s) non marqué(s) comme vérifié(s)", unverified)
+	}
+	var replies []int64
+	if err := db.Raw("SELECT reply_count FROM comments WHERE channel_id IS NULL").Scan(&replies).Error; err != nil || len(replies) != 1 || replies[0] != 0 {
+		t.Fatalf("colonnes des commentaires: %v, %v", err, replies)
+	}
+	var versions []int64
+	if err := db.Raw("SELECT version FROM insights WHERE channel_id IS NULL AND comment_count IS NULL AND negative_count IS NULL AND unanswered_questions IS NULL ORDER BY created_at").Scan(&versions).Error; err != nil {
+		t.Fatalf("colonnes des insights: %v", err)
+	}
+	if len(versions) != 2 || versions[0] != 1 || versions[1] != 2 {
+		t.Fatalf("insights existants numérotés %v, attendu [1 2]", versions)
+	}
+	var lastVersion int64
+	db.Raw("SELECT last_version FROM insight_version_counters WHERE video_id = 'vid'").Scan(&lastVersion)
+	if lastVersion != 2 {
+		t.Fatalf("compteur de versions à %d, attendu 2", lastVersion)
+	}
+	var indexes int64
+	db.Raw("SELECT COUNT(*) FROM pg_indexes WHERE indexname IN ('idx_insights_channel_id', 'idx_insight_version')").Scan(&indexes)
+	if indexes != 2 {
+		t.Fatalf("%d index créés sur 2", indexes)
+	}
+
+	// Annulation complète : il ne reste que la table de suivi des migrations
+	if _, err := migrator.Down(ctx, len(migrator.migrations)); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	var tables []string
+	db.Raw("SELECT tablename FROM pg_tables WHERE schemaname = 'public' ORDER BY tablename").Scan(&tables)
+	if len(tables) != 1 || tables[0] != "schema_migrations" {
+		t.Fatalf("tables restantes après Down: %v", tables)
+	}
+	if _, err := migrator.Up(ctx, 0); err != nil {
+		t.Fatalf("Up après Down: %v", err)
+	}
+}
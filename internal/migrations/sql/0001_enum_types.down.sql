@@ -0,0 +1,5 @@
+DROP TYPE IF EXISTS notification_type;
+DROP TYPE IF EXISTS subscription_status;
+DROP TYPE IF EXISTS subscription_plan;
+DROP TYPE IF EXISTS user_platform;
+DROP TYPE IF EXISTS user_role;
@@ -0,0 +1,36 @@
+-- Types ENUM. Chaque type est créé s'il n'existe pas, puis chaque valeur est ajoutée par
+-- ALTER TYPE ... ADD VALUE IF NOT EXISTS : une base créée avant l'ajout d'une valeur est mise à niveau.
+-- Une nouvelle valeur ne peut pas être utilisée dans la transaction qui l'ajoute : les migrations
+-- qui s'en servent (DEFAULT, INSERT) doivent venir dans un fichier ultérieur.
+
+DO $$ BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'user_role') THEN
+		CREATE TYPE user_role AS ENUM ('user', 'admin');
+	END IF;
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'user_platform') THEN
+		CREATE TYPE user_platform AS ENUM ('instagram', 'twitter', 'youtube');
+	END IF;
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'subscription_plan') THEN
+		CREATE TYPE subscription_plan AS ENUM ('free', 'pro', 'business');
+	END IF;
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'subscription_status') THEN
+		CREATE TYPE subscription_status AS ENUM ('active', 'cancelled');
+	END IF;
+	IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'notification_type') THEN
+		CREATE TYPE notification_type AS ENUM ('analysis', 'payment', 'alert');
+	END IF;
+END $$;
+
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'user';
+ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'admin';
+ALTER TYPE user_platform ADD VALUE IF NOT EXISTS 'instagram';
+ALTER TYPE user_platform ADD VALUE IF NOT EXISTS 'twitter';
+ALTER TYPE user_platform ADD VALUE IF NOT EXISTS 'youtube';
+ALTER TYPE subscription_plan ADD VALUE IF NOT EXISTS 'free';
+ALTER TYPE subscription_plan ADD VALUE IF NOT EXISTS 'pro';
+ALTER TYPE subscription_plan ADD VALUE IF NOT EXISTS 'business';
+ALTER TYPE subscription_status ADD VALUE IF NOT EXISTS 'active';
+ALTER TYPE subscription_status ADD VALUE IF NOT EXISTS 'cancelled';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'analysis';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'payment';
+ALTER TYPE notification_type ADD VALUE IF NOT EXISTS 'alert';
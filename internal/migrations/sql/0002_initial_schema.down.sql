@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS api_keys;
+DROP TABLE IF EXISTS o_auth_identities;
+DROP TABLE IF EXISTS login_attempts;
+DROP TABLE IF EXISTS account_tokens;
+DROP TABLE IF EXISTS revoked_access_tokens;
+DROP TABLE IF EXISTS refresh_tokens;
+DROP TABLE IF EXISTS alert_rules;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS insights;
+DROP TABLE IF EXISTS comments;
+DROP TABLE IF EXISTS subscriptions;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,204 @@
+-- Schéma initial, identique à celui produit par l'ancien AutoMigrate (mêmes noms de contraintes
+-- et d'index) : sur une base existante, toutes les instructions sont sans effet. Les colonnes
+-- ajoutées depuis le schéma d'origine sont créées par ALTER TABLE avant les index qui les utilisent
+-- (CREATE TABLE IF NOT EXISTS ne modifie pas une table existante).
+
+CREATE TABLE IF NOT EXISTS users (
+	id uuid DEFAULT gen_random_uuid(),
+	username varchar(50) NOT NULL,
+	email varchar(100) NOT NULL,
+	password text NOT NULL,
+	role user_role NOT NULL DEFAULT 'user',
+	email_verified_at timestamptz,
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id),
+	CONSTRAINT uni_users_username UNIQUE (username),
+	CONSTRAINT uni_users_email UNIQUE (email)
+);
+-- Bases antérieures à la vérification des emails : les comptes existants sont considérés comme
+-- vérifiés (sinon tous recevraient un 403 sur /comments) ; seuls les nouveaux comptes confirment
+-- leur adresse
+DO $$ BEGIN
+	IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'users' AND column_name = 'email_verified_at') THEN
+		ALTER TABLE users ADD COLUMN email_verified_at timestamptz;
+		UPDATE users SET email_verified_at = COALESCE(created_at, now());
+	END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	plan subscription_plan NOT NULL DEFAULT 'free',
+	status subscription_status NOT NULL DEFAULT 'active',
+	expires_at timestamptz,
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_id ON subscriptions (user_id);
+
+CREATE TABLE IF NOT EXISTS comments (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	video_id varchar(255) NOT NULL,
+	channel_id varchar(255),
+	platform user_platform NOT NULL DEFAULT 'youtube',
+	content text NOT NULL,
+	author varchar(255) NOT NULL,
+	date timestamp NOT NULL,
+	reply_count bigint DEFAULT 0,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+ALTER TABLE comments
+	ADD COLUMN IF NOT EXISTS channel_id varchar(255),
+	ADD COLUMN IF NOT EXISTS reply_count bigint DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_comments_user_id ON comments (user_id);
+
+CREATE TABLE IF NOT EXISTS insights (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	video_id text NOT NULL,
+	version bigint NOT NULL DEFAULT 1,
+	channel_id text,
+	sentiment text,
+	summary text,
+	top_comments jsonb,
+	negative_comments jsonb,
+	question_comments jsonb,
+	feedback_comments jsonb,
+	keywords jsonb,
+	transcript_summary text,
+	comment_count bigint,
+	negative_count bigint,
+	unanswered_questions bigint,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+ALTER TABLE insights
+	ADD COLUMN IF NOT EXISTS channel_id text,
+	ADD COLUMN IF NOT EXISTS comment_count bigint,
+	ADD COLUMN IF NOT EXISTS negative_count bigint,
+	ADD COLUMN IF NOT EXISTS unanswered_questions bigint;
+-- Bases antérieures au versionnage des insights : numérotation avant la création de l'index unique
+DO $$ BEGIN
+	IF NOT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'insights' AND column_name = 'version') THEN
+		ALTER TABLE insights ADD COLUMN version bigint NOT NULL DEFAULT 1;
+		UPDATE insights SET version = v.rn FROM (
+			SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, video_id ORDER BY created_at) AS rn FROM insights
+		) v WHERE insights.id = v.id;
+	END IF;
+END $$;
+CREATE INDEX IF NOT EXISTS idx_insights_user_id ON insights (user_id);
+CREATE INDEX IF NOT EXISTS idx_insights_channel_id ON insights (channel_id);
+CREATE INDEX IF NOT EXISTS idx_insights_video_id ON insights (video_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_insight_version ON insights (user_id, video_id, version);
+
+CREATE TABLE IF NOT EXISTS notifications (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	type notification_type NOT NULL,
+	message text NOT NULL,
+	is_read boolean DEFAULT false,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	video_id varchar(255),
+	channel_id varchar(255),
+	type varchar(50) NOT NULL,
+	threshold decimal NOT NULL DEFAULT 0,
+	keyword varchar(255),
+	enabled boolean NOT NULL,
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_channel_id ON alert_rules (channel_id);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_video_id ON alert_rules (video_id);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_user_id ON alert_rules (user_id);
+
+CREATE TABLE IF NOT EXISTS refresh_tokens (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	family_id uuid NOT NULL,
+	token_hash char(64) NOT NULL,
+	expires_at timestamptz NOT NULL,
+	used_at timestamptz,
+	revoked_at timestamptz,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user_id ON refresh_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS revoked_access_tokens (
+	jti varchar(64),
+	user_id uuid NOT NULL,
+	expires_at timestamptz NOT NULL,
+	created_at timestamptz,
+	PRIMARY KEY (jti)
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_expires_at ON revoked_access_tokens (expires_at);
+CREATE INDEX IF NOT EXISTS idx_revoked_access_tokens_user_id ON revoked_access_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS account_tokens (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	purpose varchar(32) NOT NULL,
+	token_hash char(64) NOT NULL,
+	expires_at timestamptz NOT NULL,
+	used_at timestamptz,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE INDEX IF NOT EXISTS idx_account_tokens_expires_at ON account_tokens (expires_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_tokens_token_hash ON account_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS idx_account_tokens_user_id ON account_tokens (user_id);
+
+CREATE TABLE IF NOT EXISTS login_attempts (
+	attempt_key varchar(255),
+	failures bigint NOT NULL DEFAULT 0,
+	first_failure_at timestamptz NOT NULL,
+	last_failure_at timestamptz NOT NULL,
+	locked_until timestamptz,
+	PRIMARY KEY (attempt_key)
+);
+CREATE INDEX IF NOT EXISTS idx_login_attempts_last_failure_at ON login_attempts (last_failure_at);
+
+CREATE TABLE IF NOT EXISTS o_auth_identities (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	provider varchar(32) NOT NULL,
+	subject varchar(255) NOT NULL,
+	email varchar(100),
+	encrypted_refresh_token text,
+	scopes text,
+	created_at timestamptz,
+	updated_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_oauth_provider_subject ON o_auth_identities (provider, subject);
+CREATE INDEX IF NOT EXISTS idx_o_auth_identities_user_id ON o_auth_identities (user_id);
+
+CREATE TABLE IF NOT EXISTS api_keys (
+	id uuid DEFAULT gen_random_uuid(),
+	user_id uuid NOT NULL,
+	name varchar(100) NOT NULL,
+	prefix varchar(16) NOT NULL,
+	secret_hash char(64) NOT NULL,
+	scopes text NOT NULL,
+	expires_at timestamptz,
+	last_used_at timestamptz,
+	revoked_at timestamptz,
+	created_at timestamptz,
+	PRIMARY KEY (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_keys_prefix ON api_keys (prefix);
+CREATE INDEX IF NOT EXISTS idx_api_keys_user_id ON api_keys (user_id);
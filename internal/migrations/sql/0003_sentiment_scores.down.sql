@@ -0,0 +1,13 @@
+ALTER TABLE insights
+	DROP COLUMN IF EXISTS sentiment_score,
+	DROP COLUMN IF EXISTS sentiment_confidence,
+	DROP COLUMN IF EXISTS sentiment_positive,
+	DROP COLUMN IF EXISTS sentiment_neutral,
+	DROP COLUMN IF EXISTS sentiment_negative,
+	DROP COLUMN IF EXISTS sentiment_mixed,
+	DROP COLUMN IF EXISTS sentiment_scored;
+
+ALTER TABLE comments
+	DROP COLUMN IF EXISTS like_count,
+	DROP COLUMN IF EXISTS polarity,
+	DROP COLUMN IF EXISTS sentiment_label;
@@ -0,0 +1,13 @@
+ALTER TABLE comments
+	ADD COLUMN IF NOT EXISTS like_count bigint DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS polarity double precision,
+	ADD COLUMN IF NOT EXISTS sentiment_label varchar(10);
+
+ALTER TABLE insights
+	ADD COLUMN IF NOT EXISTS sentiment_score double precision NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS sentiment_confidence double precision NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS sentiment_positive bigint NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS sentiment_neutral bigint NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS sentiment_negative bigint NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS sentiment_mixed bigint NOT NULL DEFAULT 0,
+	ADD COLUMN IF NOT EXISTS sentiment_scored bigint NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+ALTER TABLE insights
+	DROP COLUMN IF EXISTS analysis_engine;
@@ -0,0 +1,2 @@
+ALTER TABLE insights
+	ADD COLUMN IF NOT EXISTS analysis_engine varchar(20) NOT NULL DEFAULT 'llm';
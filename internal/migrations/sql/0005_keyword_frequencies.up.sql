@@ -0,0 +1,2 @@
+ALTER TABLE insights
+	ADD COLUMN IF NOT EXISTS keyword_frequencies jsonb;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS insight_version_counters;
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS insight_version_counters (
+	user_id uuid NOT NULL,
+	video_id text NOT NULL,
+	last_version bigint NOT NULL,
+	PRIMARY KEY (user_id, video_id)
+);
+INSERT INTO insight_version_counters (user_id, video_id, last_version)
+SELECT user_id, video_id, MAX(version) FROM insights GROUP BY user_id, video_id
+ON CONFLICT (user_id, video_id) DO NOTHING;
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// Types de règles d'alerte supportés
+const (
+	AlertRuleNegativeRatio       = "negative_ratio"       // Part de commentaires étiquetés négatifs (Threshold en %), approximative pour les insights sans polarités
+	AlertRuleKeyword             = "keyword"              // Apparition d'un mot-clé (Keyword)
+	AlertRuleUnansweredQuestions = "unanswered_questions" // Nombre de questions sans réponse (Threshold)
+	AlertRuleSentimentScore      = "sentiment_score"      // Score de sentiment inférieur au seuil (Threshold de -1 à 1)
+)
+
+// AlertRule est une règle définie par un utilisateur pour une vidéo ou une chaîne.
+// Si VideoID et ChannelID sont vides, la règle s'applique à toutes ses analyses.
+type AlertRule struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	VideoID   string    `gorm:"type:varchar(255);index" validate:"omitempty,max=255"`
+	ChannelID string    `gorm:"type:varchar(255);index" validate:"omitempty,max=255"`
+	Type      string    `gorm:"type:varchar(50);not null" validate:"required,oneof=negative_ratio keyword unanswered_questions sentiment_score"`
+	Threshold float64   `gorm:"not null;default:0" validate:"gte=-1"` // bornes par type : voir AlertService.CreateRule
+	Keyword   string    `gorm:"type:varchar(255)" validate:"required_if=Type keyword,max=255"`
+	Enabled   bool      `gorm:"type:boolean;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *AlertRule) Validate() error {
+	validate := validator.New()
+	return validate.Struct(r)
+}
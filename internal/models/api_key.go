@@ -0,0 +1,75 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes d'une clé d'API
+const (
+	APIKeyScopeReadInsights = "insights:read"
+	APIKeyScopeRunAnalyses  = "analyses:run"
+	APIKeyScopeAdmin        = "admin"
+)
+
+// APIKeyScopes liste les scopes valides
+var APIKeyScopes = []string{APIKeyScopeReadInsights, APIKeyScopeRunAnalyses, APIKeyScopeAdmin}
+
+// APIKey est une clé d'accès programmatique "es_<prefix>_<secret>". Le préfixe public sert à
+// retrouver la clé ; seul le hash SHA-256 du secret est stocké.
+type APIKey struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Name       string     `gorm:"type:varchar(100);not null"`
+	Prefix     string     `gorm:"type:varchar(16);not null;uniqueIndex"`
+	SecretHash string     `gorm:"type:char(64);not null"`
+	Scopes     string     `gorm:"type:text;not null"` // séparés par des espaces
+	ExpiresAt  *time.Time // nil : pas d'expiration
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// ScopeList retourne les scopes de la clé
+func (k *APIKey) ScopeList() []string {
+	return strings.Fields(k.Scopes)
+}
+
+// IsActive : ni révoquée ni expirée
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APIKeyResponse est la représentation publique d'une clé (jamais le secret)
+type APIKeyResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func NewAPIKeyResponse(k *APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.ScopeList(),
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+		CreatedAt:  k.CreatedAt,
+	}
+}
+
+// CreateAPIKeyRequest : ExpiresAt optionnel (RFC 3339)
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1,dive,oneof=insights:read analyses:run admin"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
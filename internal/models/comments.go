@@ -7,12 +7,18 @@ import (
 )
 
 type Comment struct {
-	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
 	UserID     uuid.UUID `gorm:"type:uuid;not null;index"`
 	VideoID    string    `gorm:"type:varchar(255);not null"`
-	Platform   string    `gorm:"type:user_platform;default:'youtube';not null"`
+	ChannelID  string    `gorm:"type:varchar(255)"` // Chaîne propriétaire de la vidéo
+	Platform   string    `gorm:"type:varchar(20);default:'youtube';not null"`
 	Content    string    `gorm:"type:text;not null"`
 	Author     string    `gorm:"type:varchar(255);not null"`
 	Date       time.Time `gorm:"type:timestamp;not null"`
-	CreatedAt  time.Time
+	ReplyCount int64     `gorm:"default:0"` // Nombre de réponses au commentaire
+	LikeCount  int64     `gorm:"default:0"` // Nombre de "j'aime" (pondère le score de sentiment)
+	// Polarité de -1 à 1 attribuée par l'analyse (nil : commentaire non évalué)
+	Polarity       *float64
+	SentimentLabel string `gorm:"type:varchar(10)"` // SentimentPositive, SentimentNeutral... (vide si non évalué)
+	CreatedAt      time.Time
 }
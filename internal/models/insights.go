@@ -7,17 +7,47 @@ import (
 	"gorm.io/datatypes"
 )
 
+// Moteurs d'analyse d'un insight (Insight.AnalysisEngine)
+const (
+	AnalysisEngineLLM     = "llm"
+	AnalysisEngineLexicon = "lexicon" // analyse hors ligne par lexique
+	AnalysisEngineMixed   = "mixed"   // certains lots analysés hors ligne après un échec du modèle
+)
+
+// KeywordFrequency : mot-clé d'un insight et nombre de commentaires analysés qui le mentionnent
+type KeywordFrequency struct {
+	Keyword   string `json:"keyword"`
+	Frequency int    `json:"frequency"`
+}
+
 type Insight struct {
-	ID                uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
-	UserID            uuid.UUID      `gorm:"type:uuid;not null;index"`
-	VideoID           string         `gorm:"not null;index"` // pour retrouver les insights par vidéo
-	Sentiment         string         // Ex: "Négatif/Neutre"
-	Summary           string         // Résumé du ton général
-	TopComments       datatypes.JSON // []string
-	NegativeComments  datatypes.JSON // []string
-	QuestionComments  datatypes.JSON // []string
-	FeedbackComments  datatypes.JSON // []string ou autres remarques
-	Keywords          datatypes.JSON // []string
-	TranscriptSummary string
-	CreatedAt         time.Time
+	ID                  uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	UserID              uuid.UUID      `gorm:"type:uuid;not null;index;uniqueIndex:idx_insight_version,priority:1"`
+	VideoID             string         `gorm:"not null;index;uniqueIndex:idx_insight_version,priority:2"`     // pour retrouver les insights par vidéo
+	Version             int            `gorm:"not null;default:1;uniqueIndex:idx_insight_version,priority:3"` // 1, 2, 3... par (utilisateur, vidéo)
+	ChannelID           string         `gorm:"index"`                                                         // chaîne de la vidéo (règles d'alerte par chaîne)
+	Sentiment           string         // Ex: "Négatif/Neutre"
+	Summary             string         // Résumé du ton général
+	TopComments         datatypes.JSON // []string
+	NegativeComments    datatypes.JSON // []string
+	QuestionComments    datatypes.JSON // []string
+	FeedbackComments    datatypes.JSON // []string ou autres remarques
+	Keywords            datatypes.JSON // []string
+	KeywordFrequencies  datatypes.JSON // []KeywordFrequency, dans l'ordre de Keywords
+	TranscriptSummary   string
+	CommentCount        int // Nombre de commentaires analysés
+	NegativeCount       int // Nombre de critiques négatives identifiées (avant limitation des listes)
+	UnansweredQuestions int // Questions (commentaires avec "?") restées sans réponse
+	// Sentiment chiffré (colonnes sentiment_score, sentiment_confidence, sentiment_positive...)
+	SentimentStats SentimentStats `gorm:"embedded;embeddedPrefix:sentiment_"`
+	AnalysisEngine string         `gorm:"type:varchar(20);default:'llm';not null"` // AnalysisEngineLLM, AnalysisEngineLexicon ou AnalysisEngineMixed
+	CreatedAt      time.Time
+}
+
+// InsightVersionCounter garde le dernier numéro de version attribué par (utilisateur, vidéo).
+// Il n'est jamais décrémenté : le numéro d'une version supprimée n'est pas réattribué.
+type InsightVersionCounter struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey"`
+	VideoID     string    `gorm:"primaryKey"`
+	LastVersion int       `gorm:"not null"`
 }
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// LoginAttempt compte les échecs de connexion consécutifs pour une clé ("user:<username>" ou "ip:<adresse>")
+type LoginAttempt struct {
+	AttemptKey     string     `gorm:"type:varchar(255);primaryKey"`
+	Failures       int        `gorm:"not null;default:0"`
+	FirstFailureAt time.Time  `gorm:"not null"` // début de la fenêtre de comptage
+	LastFailureAt  time.Time  `gorm:"not null;index"`
+	LockedUntil    *time.Time // verrouillage temporaire après trop d'échecs
+}
@@ -7,9 +7,9 @@ import (
 )
 
 type Notification struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
 	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
-	Type      string    `gorm:"type:Notification_Type;not null"`
+	Type      string    `gorm:"type:varchar(20);not null"`
 	Message   string    `gorm:"type:text;not null"`
 	IsRead    bool      `gorm:"type:boolean;default:false"`
 	CreatedAt time.Time
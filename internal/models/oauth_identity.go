@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scope Google permettant de lire sa propre chaîne YouTube
+const YouTubeReadOnlyScope = "https://www.googleapis.com/auth/youtube.readonly"
+
+// OAuthIdentity relie un compte à une identité externe (Google...) identifiée par (Provider, Subject).
+// Le refresh token du fournisseur est stocké chiffré (utils.SecretBox), jamais en clair.
+type OAuthIdentity struct {
+	ID                    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID                uuid.UUID `gorm:"type:uuid;not null;index"`
+	Provider              string    `gorm:"type:varchar(32);not null;uniqueIndex:idx_oauth_provider_subject"`
+	Subject               string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_oauth_provider_subject"`
+	Email                 string    `gorm:"type:varchar(100)"`
+	EncryptedRefreshToken string    `gorm:"type:text"`
+	Scopes                string    `gorm:"type:text"` // scopes accordés, séparés par des espaces
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
+
+// ExternalIdentity est l'identité vérifiée retournée par un fournisseur OpenID Connect
+type ExternalIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	RefreshToken  string // vide si le fournisseur n'en a pas délivré
+	Scopes        []string
+}
+
+// YouTubeVideo est une vidéo de la chaîne de l'utilisateur (y compris privée ou non répertoriée)
+type YouTubeVideo struct {
+	VideoID       string    `json:"video_id"`
+	ChannelID     string    `json:"channel_id"`
+	Title         string    `json:"title"`
+	PrivacyStatus string    `json:"privacy_status"` // public, unlisted, private
+	PublishedAt   time.Time `json:"published_at"`
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Les modèles doivent fonctionner sur PostgreSQL comme sur SQLite (développement local, tests) :
+// les UUID sont générés côté application (pas de gen_random_uuid()) et les valeurs des ENUM
+// PostgreSQL sont vérifiées avant écriture (SQLite n'a pas de type ENUM).
+
+var ErrInvalidEnumValue = errors.New("valeur non autorisée")
+
+// Valeurs des ENUM user_platform, subscription_plan, subscription_status et notification_type
+const (
+	PlatformInstagram = "instagram"
+	PlatformTwitter   = "twitter"
+	PlatformYouTube   = "youtube"
+
+	PlanFree     = "free"
+	PlanPro      = "pro"
+	PlanBusiness = "business"
+
+	SubscriptionActive    = "active"
+	SubscriptionCancelled = "cancelled"
+
+	NotificationTypeAnalysis = "analysis"
+	NotificationTypePayment  = "payment"
+	NotificationTypeAlert    = "alert"
+)
+
+// All liste les modèles persistés (création du schéma SQLite)
+func All() []any {
+	return []any{
+		&User{}, &Subscription{}, &Comment{}, &Insight{}, &InsightVersionCounter{}, &Notification{}, &AlertRule{},
+		&RefreshToken{}, &RevokedAccessToken{}, &AccountToken{}, &LoginAttempt{}, &OAuthIdentity{}, &APIKey{},
+	}
+}
+
+// ensureID attribue un UUID v4 si l'appelant n'en a pas fourni
+func ensureID(id *uuid.UUID) {
+	if *id == uuid.Nil {
+		*id = uuid.New()
+	}
+}
+
+// checkEnum vérifie qu'une colonne ENUM reçoit une valeur autorisée
+func checkEnum(column, value string, allowed ...string) error {
+	for _, candidate := range allowed {
+		if value == candidate {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: %w: %q", column, ErrInvalidEnumValue, value)
+}
+
+// ValidateRole est utilisé par les mises à jour partielles, qui ne passent pas par les hooks
+func ValidateRole(role string) error {
+	return checkEnum("users.role", role, RoleUser, RoleAdmin)
+}
+
+// Création : UUID, valeurs par défaut des ENUM puis vérification.
+// Mise à jour : seules les valeurs renseignées sont vérifiées (les mises à jour partielles
+// via Model(&T{}).Update(...) passent un modèle vide aux hooks).
+
+func (u *User) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&u.ID)
+	if u.Role == "" {
+		u.Role = RoleUser
+	}
+	return ValidateRole(u.Role)
+}
+
+func (u *User) BeforeUpdate(tx *gorm.DB) error {
+	if u.Role == "" {
+		return nil
+	}
+	return ValidateRole(u.Role)
+}
+
+func (s *Subscription) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&s.ID)
+	if s.Plan == "" {
+		s.Plan = PlanFree
+	}
+	if s.Status == "" {
+		s.Status = SubscriptionActive
+	}
+	return s.BeforeUpdate(tx)
+}
+
+func (s *Subscription) BeforeUpdate(tx *gorm.DB) error {
+	if s.Plan != "" {
+		if err := checkEnum("subscriptions.plan", s.Plan, PlanFree, PlanPro, PlanBusiness); err != nil {
+			return err
+		}
+	}
+	if s.Status != "" {
+		return checkEnum("subscriptions.status", s.Status, SubscriptionActive, SubscriptionCancelled)
+	}
+	return nil
+}
+
+func (c *Comment) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&c.ID)
+	if c.Platform == "" {
+		c.Platform = PlatformYouTube
+	}
+	return c.BeforeUpdate(tx)
+}
+
+func (c *Comment) BeforeUpdate(tx *gorm.DB) error {
+	if c.Platform == "" {
+		return nil
+	}
+	return checkEnum("comments.platform", c.Platform, PlatformInstagram, PlatformTwitter, PlatformYouTube)
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&n.ID)
+	return checkEnum("notifications.type", n.Type, NotificationTypeAnalysis, NotificationTypePayment, NotificationTypeAlert)
+}
+
+func (i *Insight) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&i.ID)
+	return nil
+}
+
+func (r *AlertRule) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&r.ID)
+	return nil
+}
+
+func (t *RefreshToken) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&t.ID)
+	return nil
+}
+
+func (t *AccountToken) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&t.ID)
+	return nil
+}
+
+func (o *OAuthIdentity) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&o.ID)
+	return nil
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	ensureID(&k.ID)
+	return nil
+}
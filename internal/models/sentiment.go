@@ -0,0 +1,22 @@
+// internal/models/sentiment.go
+package models
+
+// Étiquettes de sentiment d'un commentaire (Comment.SentimentLabel)
+const (
+	SentimentPositive = "positive"
+	SentimentNeutral  = "neutral"
+	SentimentNegative = "negative"
+	SentimentMixed    = "mixed" // avis positifs et négatifs dans le même commentaire
+)
+
+// SentimentStats est la mesure chiffrée du sentiment d'un insight, calculée à partir de la
+// polarité de chaque commentaire (voir utils.ComputeSentimentStats)
+type SentimentStats struct {
+	Score      float64 // polarité moyenne pondérée par les likes, de -1 (négatif) à 1 (positif)
+	Confidence float64 // de 0 à 1 : part de commentaires évalués, volume et accord entre commentaires
+	Positive   int     // nombre de commentaires par étiquette
+	Neutral    int
+	Negative   int
+	Mixed      int
+	Scored     int // commentaires évalués (les autres n'ont pas de polarité)
+}
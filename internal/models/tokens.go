@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken est un token opaque de renouvellement. Seul son hash SHA-256 est stocké.
+// Tous les tokens issus d'une même connexion partagent un FamilyID : la réutilisation
+// d'un token déjà échangé révoque toute la famille.
+type RefreshToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	FamilyID  uuid.UUID  `gorm:"type:uuid;not null;index"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time // renseigné lors de l'échange contre un nouveau token
+	RevokedAt *time.Time // renseigné lors d'une déconnexion ou d'une réutilisation détectée
+	CreatedAt time.Time
+}
+
+// RevokedAccessToken est une entrée de la liste de révocation des tokens d'accès (par jti),
+// conservée jusqu'à l'expiration naturelle du token.
+type RevokedAccessToken struct {
+	JTI       string    `gorm:"type:varchar(64);primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;index"`
+	ExpiresAt time.Time `gorm:"not null;index"`
+	CreatedAt time.Time
+}
+
+// Usages d'un AccountToken
+const (
+	AccountTokenEmailVerification = "email_verification"
+	AccountTokenPasswordReset     = "password_reset"
+)
+
+// AccountToken est un token à usage unique envoyé par email (vérification d'adresse,
+// réinitialisation de mot de passe). Seul son hash SHA-256 est stocké.
+type AccountToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index"`
+	Purpose   string     `gorm:"type:varchar(32);not null"`
+	TokenHash string     `gorm:"type:char(64);not null;uniqueIndex"`
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	UsedAt    *time.Time // renseigné à la consommation, ou à l'invalidation par un token plus récent
+	CreatedAt time.Time
+}
@@ -5,27 +5,33 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
-	"gorm.io/gorm"
+)
+
+// Rôles utilisateur (ENUM user_role, vérifié côté application : voir portable.go)
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
 )
 
 type User struct {
-	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
 	Username  string    `gorm:"type:varchar(50);unique;not null" validate:"required,min=3,max=50"`
 	Email     string    `gorm:"type:varchar(100);unique;not null" validate:"required,email"`
 	Password  string    `gorm:"type:text;not null" validate:"required,min=8"`
-	Role      string    `gorm:"type:user_role;default:'user';not null" validate:"required,oneof=admin user"`
+	Role      string    `gorm:"type:varchar(20);default:'user';not null" validate:"required,oneof=admin user"`
+	// EmailVerifiedAt est nil tant que l'adresse n'a pas été confirmée (réinitialisé à chaque changement d'email)
+	EmailVerifiedAt *time.Time
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// IsEmailVerified indique si l'adresse email actuelle a été confirmée
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 func (u *User) Validate() error {
 	validate := validator.New()
 	return validate.Struct(u)
 }
-func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
-	u.ID = uuid.New()
-	return
-}
-
 
-//Oauth with fiber golang
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// UserResponse est la représentation publique d'un utilisateur (jamais le hash du mot de passe)
+type UserResponse struct {
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	Role          string    `json:"role"`
+	EmailVerified bool      `json:"email_verified"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func NewUserResponse(u *User) UserResponse {
+	return UserResponse{
+		ID:            u.ID,
+		Username:      u.Username,
+		Email:         u.Email,
+		Role:          u.Role,
+		EmailVerified: u.IsEmailVerified(),
+		CreatedAt:     u.CreatedAt,
+		UpdatedAt:     u.UpdatedAt,
+	}
+}
+
+func NewUserResponses(users []User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, NewUserResponse(&users[i]))
+	}
+	return responses
+}
+
+// UpdateProfileRequest : champs modifiables par l'utilisateur lui-même
+type UpdateProfileRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50"`
+}
+
+// ChangePasswordRequest exige le mot de passe actuel
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8"`
+}
+
+// ChangeEmailRequest exige le mot de passe actuel
+type ChangeEmailRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewEmail        string `json:"new_email" validate:"required,email,max=100"`
+}
+
+// DeleteAccountRequest exige le mot de passe actuel
+type DeleteAccountRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+}
+
+// PasswordResetRequest : demande d'un lien de réinitialisation
+type PasswordResetRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ConfirmPasswordResetRequest : token reçu par email et nouveau mot de passe
+type ConfirmPasswordResetRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+// ConfirmEmailRequest : token de vérification reçu par email
+type ConfirmEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ValidateRequest valide une requête annotée avec des tags `validate`
+func ValidateRequest(request any) error {
+	validate := validator.New()
+	return validate.Struct(request)
+}
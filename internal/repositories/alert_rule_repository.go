@@ -0,0 +1,72 @@
+// internal/repositories/alert_rule_repository.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// AlertRuleRepository définit les opérations pour les règles d'alerte
+type AlertRuleRepository interface {
+	CreateAlertRule(ctx context.Context, rule *models.AlertRule) error
+	ListAlertRulesByUser(ctx context.Context, userID uuid.UUID) ([]models.AlertRule, error)
+	// ListActiveRulesForInsight retourne les règles actives qui ciblent la vidéo, la chaîne, ou toutes les analyses de l'utilisateur
+	ListActiveRulesForInsight(ctx context.Context, userID uuid.UUID, videoID, channelID string) ([]models.AlertRule, error)
+	DeleteAlertRule(ctx context.Context, userID, ruleID uuid.UUID) error
+}
+
+type alertRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewAlertRuleRepository crée une nouvelle instance de AlertRuleRepository
+func NewAlertRuleRepository(db *gorm.DB) AlertRuleRepository {
+	return &alertRuleRepository{db: db}
+}
+
+func (r *alertRuleRepository) CreateAlertRule(ctx context.Context, rule *models.AlertRule) error {
+	if err := database.Conn(ctx, r.db).Create(rule).Error; err != nil {
+		return fmt.Errorf("échec de la création de la règle d'alerte: %w", err)
+	}
+	return nil
+}
+
+func (r *alertRuleRepository) ListAlertRulesByUser(ctx context.Context, userID uuid.UUID) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	err := database.Conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at DESC").Find(&rules).Error
+	if err != nil {
+		return nil, fmt.Errorf("échec de la récupération des règles d'alerte: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *alertRuleRepository) ListActiveRulesForInsight(ctx context.Context, userID uuid.UUID, videoID, channelID string) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	query := database.Conn(ctx, r.db).Where("user_id = ? AND enabled = ?", userID, true)
+	if channelID != "" {
+		query = query.Where("(video_id = '' AND channel_id = '') OR video_id = ? OR channel_id = ?", videoID, channelID)
+	} else {
+		query = query.Where("(video_id = '' AND channel_id = '') OR video_id = ?", videoID)
+	}
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("échec de la récupération des règles d'alerte actives: %w", err)
+	}
+	return rules, nil
+}
+
+func (r *alertRuleRepository) DeleteAlertRule(ctx context.Context, userID, ruleID uuid.UUID) error {
+	result := database.Conn(ctx, r.db).Where("id = ? AND user_id = ?", ruleID, userID).Delete(&models.AlertRule{})
+	if result.Error != nil {
+		return fmt.Errorf("échec de la suppression de la règle d'alerte: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestAlertRuleRepositoryMatchesRulesForInsight(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAlertRuleRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+		other := createTestUser(t, db, "bob")
+
+		global := &models.AlertRule{UserID: user.ID, Type: "negative_ratio", Threshold: 0.4, Enabled: true}
+		onVideo := &models.AlertRule{UserID: user.ID, VideoID: "v1", Type: "unanswered_questions", Threshold: 5, Enabled: true}
+		onChannel := &models.AlertRule{UserID: user.ID, ChannelID: "ch1", Type: "keyword", Keyword: "remboursement", Enabled: true}
+		disabled := &models.AlertRule{UserID: user.ID, Type: "negative_ratio", Threshold: 0.1, Enabled: false}
+		otherVideo := &models.AlertRule{UserID: user.ID, VideoID: "v2", Type: "negative_ratio", Threshold: 0.2, Enabled: true}
+		foreign := &models.AlertRule{UserID: other.ID, Type: "negative_ratio", Threshold: 0.2, Enabled: true}
+		for _, rule := range []*models.AlertRule{global, onVideo, onChannel, disabled, otherVideo, foreign} {
+			if err := repo.CreateAlertRule(ctx, rule); err != nil {
+				t.Fatalf("CreateAlertRule: %v", err)
+			}
+		}
+
+		rules, err := repo.ListActiveRulesForInsight(ctx, user.ID, "v1", "ch1")
+		if err != nil {
+			t.Fatalf("ListActiveRulesForInsight: %v", err)
+		}
+		matched := map[string]bool{}
+		for _, rule := range rules {
+			matched[rule.ID.String()] = true
+		}
+		if len(rules) != 3 || !matched[global.ID.String()] || !matched[onVideo.ID.String()] || !matched[onChannel.ID.String()] {
+			t.Fatalf("règles inattendues: %d règle(s)", len(rules))
+		}
+
+		// Sans chaîne connue, seules les règles globales et celles de la vidéo s'appliquent
+		if rules, _ := repo.ListActiveRulesForInsight(ctx, user.ID, "v1", ""); len(rules) != 2 {
+			t.Fatalf("sans chaîne: %d règle(s)", len(rules))
+		}
+
+		if err := repo.DeleteAlertRule(ctx, other.ID, global.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("suppression par un autre utilisateur: %v", err)
+		}
+		if err := repo.DeleteAlertRule(ctx, user.ID, global.ID); err != nil {
+			t.Fatalf("DeleteAlertRule: %v", err)
+		}
+	})
+}
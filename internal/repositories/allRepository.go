@@ -6,6 +6,15 @@ type AllRepository struct{
 	UserRepository UserRepository
 	CommentRepository CommentRepository
 	InsightRepository InsightRepository
+	NotificationRepository NotificationRepository
+	AlertRuleRepository AlertRuleRepository
+	TokenRepository TokenRepository
+	LoginAttemptRepository LoginAttemptRepository
+	OAuthIdentityRepository OAuthIdentityRepository
+	APIKeyRepository APIKeyRepository
+	SubscriptionRepository SubscriptionRepository
+	HealthRepository HealthRepository
+	Transactor Transactor
 }
 
 func NewAllRepository(db *gorm.DB) AllRepository{
@@ -13,5 +22,14 @@ func NewAllRepository(db *gorm.DB) AllRepository{
 		UserRepository: NewUserRepository(db),
 		CommentRepository: NewCommentRepository(db),
 		InsightRepository: NewInsightRepository(db),
+		NotificationRepository: NewNotificationRepository(db),
+		AlertRuleRepository: NewAlertRuleRepository(db),
+		TokenRepository: NewTokenRepository(db),
+		LoginAttemptRepository: NewLoginAttemptRepository(db),
+		OAuthIdentityRepository: NewOAuthIdentityRepository(db),
+		APIKeyRepository: NewAPIKeyRepository(db),
+		SubscriptionRepository: NewSubscriptionRepository(db),
+		HealthRepository: NewHealthRepository(db),
+		Transactor: NewTransactor(db),
 	}
 }
\ No newline at end of file
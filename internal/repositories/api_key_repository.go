@@ -0,0 +1,82 @@
+// internal/repositories/api_key_repository.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// APIKeyRepository gère les clés d'API des utilisateurs
+type APIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey) error
+	ListAPIKeysByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	// FindAPIKeyByPrefix retourne nil, nil si aucune clé ne correspond
+	FindAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error)
+	// RevokeAPIKey retourne gorm.ErrRecordNotFound si la clé n'existe pas, n'appartient pas à l'utilisateur ou est déjà révoquée
+	RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) error
+	TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository crée une nouvelle instance de APIKeyRepository
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, key *models.APIKey) error {
+	if err := database.Conn(ctx, r.db).Create(key).Error; err != nil {
+		return fmt.Errorf("échec de la création de la clé d'API: %w", err)
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) ListAPIKeysByUser(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	var keys []models.APIKey
+	if err := database.Conn(ctx, r.db).Where("user_id = ?", userID).Order("created_at DESC").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("échec de la récupération des clés d'API: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *apiKeyRepository) FindAPIKeyByPrefix(ctx context.Context, prefix string) (*models.APIKey, error) {
+	var key models.APIKey
+	result := database.Conn(ctx, r.db).Where("prefix = ?", prefix).First(&key)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("échec de la récupération de la clé d'API: %w", result.Error)
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) error {
+	result := database.Conn(ctx, r.db).Model(&models.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return fmt.Errorf("échec de la révocation de la clé d'API: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, keyID uuid.UUID, usedAt time.Time) error {
+	err := database.Conn(ctx, r.db).Model(&models.APIKey{}).Where("id = ?", keyID).Update("last_used_at", usedAt).Error
+	if err != nil {
+		return fmt.Errorf("échec de la mise à jour de la dernière utilisation de la clé d'API: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestAPIKeyRepositoryLifecycle(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewAPIKeyRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+		intruder := createTestUser(t, db, "mallory")
+
+		key := &models.APIKey{UserID: user.ID, Name: "ci", Prefix: "es_abcdef12", SecretHash: testHash("k"), Scopes: "insights:read"}
+		if err := repo.CreateAPIKey(ctx, key); err != nil {
+			t.Fatalf("CreateAPIKey: %v", err)
+		}
+		duplicate := &models.APIKey{UserID: user.ID, Name: "doublon", Prefix: key.Prefix, SecretHash: testHash("d"), Scopes: "insights:read"}
+		if err := repo.CreateAPIKey(ctx, duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("préfixe dupliqué: attendu ErrDuplicatedKey, obtenu %v", err)
+		}
+
+		found, err := repo.FindAPIKeyByPrefix(ctx, key.Prefix)
+		if err != nil || found == nil || found.ID != key.ID {
+			t.Fatalf("FindAPIKeyByPrefix: %v, %+v", err, found)
+		}
+		if missing, err := repo.FindAPIKeyByPrefix(ctx, "es_inconnu"); err != nil || missing != nil {
+			t.Fatalf("préfixe inconnu: %v, %+v", err, missing)
+		}
+
+		usedAt := time.Now().Truncate(time.Second)
+		if err := repo.TouchAPIKey(ctx, key.ID, usedAt); err != nil {
+			t.Fatalf("TouchAPIKey: %v", err)
+		}
+		if touched, _ := repo.FindAPIKeyByPrefix(ctx, key.Prefix); touched.LastUsedAt == nil || !touched.LastUsedAt.Equal(usedAt) {
+			t.Fatalf("last_used_at non mis à jour: %v", touched.LastUsedAt)
+		}
+
+		if err := repo.RevokeAPIKey(ctx, intruder.ID, key.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("révocation par un autre utilisateur: %v", err)
+		}
+		if err := repo.RevokeAPIKey(ctx, user.ID, key.ID); err != nil {
+			t.Fatalf("RevokeAPIKey: %v", err)
+		}
+		if err := repo.RevokeAPIKey(ctx, user.ID, key.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("double révocation: attendu ErrRecordNotFound, obtenu %v", err)
+		}
+		keys, err := repo.ListAPIKeysByUser(ctx, user.ID)
+		if err != nil || len(keys) != 1 || keys[0].IsActive(time.Now()) {
+			t.Fatalf("ListAPIKeysByUser: %v, %d clé(s)", err, len(keys))
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// TEST_POSTGRES_URL active les tests sur PostgreSQL. ATTENTION : toutes les tables de cette
+// base sont vidées avant chaque test, ne jamais la faire pointer vers une base réelle.
+const testPostgresURLEnv = "TEST_POSTGRES_URL"
+
+// forEachBackend exécute le test sur SQLite (toujours) et sur PostgreSQL (si configuré)
+func forEachBackend(t *testing.T, test func(t *testing.T, db *gorm.DB)) {
+	t.Helper()
+	t.Run("sqlite", func(t *testing.T) {
+		test(t, openTestDB(t, database.DriverSQLite, filepath.Join(t.TempDir(), "test.db")))
+	})
+	t.Run("postgres", func(t *testing.T) {
+		url := os.Getenv(testPostgresURLEnv)
+		if url == "" {
+			t.Skipf("%s non défini", testPostgresURLEnv)
+		}
+		db := openTestDB(t, database.DriverPostgres, url)
+		truncateAll(t, db)
+		test(t, db)
+	})
+}
+
+func openTestDB(t *testing.T, driver, dsn string) *gorm.DB {
+	t.Helper()
+	db, err := database.Open(driver, dsn)
+	if err != nil {
+		t.Fatalf("ouverture %s: %v", driver, err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if _, err := database.Migrate(context.Background(), db); err != nil {
+		t.Fatalf("migration %s: %v", driver, err)
+	}
+	if err := database.UseQueryTimeout(db, 5*time.Second); err != nil {
+		t.Fatalf("délai par requête: %v", err)
+	}
+	// Les "record not found" attendus par les tests ne doivent pas polluer la sortie
+	return db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+}
+
+func truncateAll(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	for _, model := range models.All() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("analyse du modèle %T: %v", model, err)
+		}
+		if err := db.Exec("TRUNCATE TABLE " + stmt.Schema.Table + " CASCADE").Error; err != nil {
+			t.Fatalf("vidage de %s: %v", stmt.Schema.Table, err)
+		}
+	}
+}
+
+// createTestUser insère un utilisateur minimal (le mot de passe n'est pas haché : inutile ici)
+func createTestUser(t *testing.T, db *gorm.DB, username string) *models.User {
+	t.Helper()
+	user := &models.User{Username: username, Email: username + "@example.com", Password: "not-a-hash"}
+	if err := NewUserRepository(db).Create(t.Context(), user); err != nil {
+		t.Fatalf("création de l'utilisateur %s: %v", username, err)
+	}
+	return user
+}
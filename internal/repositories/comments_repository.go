@@ -1,14 +1,23 @@
 package repositories
 
 import (
+	"context"
+
+	"github.com/Azertdev/FiberTest/internal/database"
 	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// Taille des lots d'insertion (limite du nombre de paramètres par requête)
+const commentBatchSize = 500
+
 type CommentRepository interface {
-	FindAllComment() ([]models.Comment, error)
-	FindCommentByID(id uint) (*models.Comment, error)
-	SaveYouTubeComments(comments []models.Comment) error
+	FindAllComment(ctx context.Context) ([]models.Comment, error)
+	FindCommentByID(ctx context.Context, id uuid.UUID) (*models.Comment, error)
+	SaveYouTubeComments(ctx context.Context, comments []models.Comment) error
+	// ReplaceVideoComments remplace les commentaires enregistrés pour la vidéo par ceux de la dernière analyse
+	ReplaceVideoComments(ctx context.Context, userID uuid.UUID, videoID string, comments []models.Comment) error
 }
 
 type CommentRepo struct {
@@ -19,18 +28,34 @@ func NewCommentRepository(db *gorm.DB) CommentRepository {
 	return &CommentRepo{db}
 }
 
-func (r *CommentRepo) FindAllComment() ([]models.Comment, error) {
+func (r *CommentRepo) FindAllComment(ctx context.Context) ([]models.Comment, error) {
 	var comments []models.Comment
-	err := r.db.Find(&comments).Error
+	err := database.Conn(ctx, r.db).Find(&comments).Error
 	return comments, err
 }
 
-func (r *CommentRepo) FindCommentByID(id uint) (*models.Comment, error) {
+func (r *CommentRepo) FindCommentByID(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
 	var comment models.Comment
-	err := r.db.First(&comment, id).Error
+	err := database.Conn(ctx, r.db).Where("id = ?", id).First(&comment).Error
 	return &comment, err
 }
 
-func (r *CommentRepo) SaveYouTubeComments(comments []models.Comment) error {
-	return r.db.Create(&comments).Error
+func (r *CommentRepo) SaveYouTubeComments(ctx context.Context, comments []models.Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	return database.Conn(ctx, r.db).CreateInBatches(&comments, commentBatchSize).Error
+}
+
+func (r *CommentRepo) ReplaceVideoComments(ctx context.Context, userID uuid.UUID, videoID string, comments []models.Comment) error {
+	return database.WithinTransaction(ctx, r.db, func(ctx context.Context) error {
+		if err := database.Conn(ctx, r.db).Where("user_id = ? AND video_id = ?", userID, videoID).Delete(&models.Comment{}).Error; err != nil {
+			return err
+		}
+		for i := range comments {
+			comments[i].UserID = userID
+			comments[i].VideoID = videoID
+		}
+		return r.SaveYouTubeComments(ctx, comments)
+	})
 }
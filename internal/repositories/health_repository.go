@@ -0,0 +1,33 @@
+// internal/repositories/health_repository.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// HealthRepository vérifie la disponibilité de la base (sonde de readiness)
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+}
+
+type healthRepository struct {
+	db *gorm.DB
+}
+
+func NewHealthRepository(db *gorm.DB) HealthRepository {
+	return &healthRepository{db: db}
+}
+
+func (r *healthRepository) Ping(ctx context.Context) error {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return fmt.Errorf("connexion à la base indisponible: %w", err)
+	}
+	if err := sqlDB.PingContext(ctx); err != nil {
+		return fmt.Errorf("la base de données ne répond pas: %w", err)
+	}
+	return nil
+}
@@ -4,19 +4,54 @@ package repositories
 import (
 	"context" // Bonne pratique d'utiliser le contexte
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
-		"github.com/Azertdev/FiberTest/internal/models"
- // Adaptez le chemin d'import
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+	// Adaptez le chemin d'import
 )
 
 // InsightRepository définit les opérations pour les Insights
 type InsightRepository interface {
 	CreateInsight(ctx context.Context, insight *models.Insight) error
 	GetInsightByVideoID(ctx context.Context, userID uuid.UUID, videoID string) (*models.Insight, error)
-	// Ajoutez d'autres méthodes si nécessaire (Update, Delete, List...)
+	GetInsightByID(ctx context.Context, userID, insightID uuid.UUID) (*models.Insight, error)
+	ListInsights(ctx context.Context, filter InsightFilter) ([]models.Insight, int64, error)
+	ListInsightsByVideo(ctx context.Context, userID uuid.UUID, videoID string) ([]models.Insight, error)
+	DeleteInsight(ctx context.Context, userID, insightID uuid.UUID) error
+	GetInsightVersion(ctx context.Context, userID uuid.UUID, videoID string, version int) (*models.Insight, error)
+	GetInsightsByIDs(ctx context.Context, userID uuid.UUID, insightIDs []uuid.UUID) ([]models.Insight, error)
+	// GetInsightOwner retourne le propriétaire de l'insight (gorm.ErrRecordNotFound s'il n'existe pas)
+	GetInsightOwner(ctx context.Context, insightID uuid.UUID) (uuid.UUID, error)
+}
+
+// nextInsightVersionSQL incrémente le compteur de version du couple (utilisateur, vidéo) et
+// retourne le nouveau numéro, en une seule instruction (deux analyses concurrentes obtiennent
+// deux numéros distincts). Un compteur absent part de la dernière version existante.
+const nextInsightVersionSQL = `INSERT INTO insight_version_counters (user_id, video_id, last_version)
+VALUES (?, ?, (SELECT COALESCE(MAX(version), 0) + 1 FROM insights WHERE user_id = ? AND video_id = ?))
+ON CONFLICT (user_id, video_id) DO UPDATE SET last_version = insight_version_counters.last_version + 1
+RETURNING last_version`
+
+// InsightFilter regroupe les critères de pagination, filtrage et tri de ListInsights
+type InsightFilter struct {
+	UserID   uuid.UUID
+	VideoID  string     // optionnel
+	From     *time.Time // optionnel, borne incluse sur created_at
+	To       *time.Time // optionnel, borne incluse sur created_at
+	SortBy   string     // "created_at" (défaut) ou "video_id"
+	SortDesc bool
+	Page     int // commence à 1
+	PageSize int
+}
+
+// Colonnes autorisées pour le tri (évite toute injection via le paramètre de tri)
+var insightSortColumns = map[string]string{
+	"created_at": "created_at",
+	"video_id":   "video_id",
 }
 
 type insightRepository struct {
@@ -28,21 +63,28 @@ func NewInsightRepository(db *gorm.DB) InsightRepository {
 	return &insightRepository{db: db}
 }
 
-// CreateInsight enregistre un nouvel Insight en base de données
+// CreateInsight enregistre un nouvel Insight en base de données en lui attribuant
+// le numéro de version suivant pour le couple (utilisateur, vidéo).
 func (r *insightRepository) CreateInsight(ctx context.Context, insight *models.Insight) error {
-	// GORM gère automatiquement les champs ID (avec default) et CreatedAt
-	result := r.db.WithContext(ctx).Create(insight)
-	if result.Error != nil {
-		// TODO: Gérer les erreurs spécifiques (ex: violation de contrainte unique si vous en ajoutez)
-		return fmt.Errorf("échec de la création de l'insight: %w", result.Error)
-	}
-	return nil
+	// Le numéro vient d'un compteur monotone et non de MAX(version) : après la suppression de la
+	// dernière version, la suivante ne reprend pas son numéro
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(nextInsightVersionSQL, insight.UserID, insight.VideoID, insight.UserID, insight.VideoID).
+			Scan(&insight.Version).Error
+		if err != nil {
+			return fmt.Errorf("échec du calcul de la version de l'insight: %w", err)
+		}
+		if err := tx.Create(insight).Error; err != nil {
+			return fmt.Errorf("échec de la création de l'insight: %w", err)
+		}
+		return nil
+	})
 }
 
-// GetInsightByVideoID récupère un Insight par UserID et VideoID
+// GetInsightByVideoID récupère le dernier Insight par UserID et VideoID
 func (r *insightRepository) GetInsightByVideoID(ctx context.Context, userID uuid.UUID, videoID string) (*models.Insight, error) {
 	var insight models.Insight
-	result := r.db.WithContext(ctx).Where("user_id = ? AND video_id = ?", userID, videoID).First(&insight)
+	result := database.Conn(ctx, r.db).Where("user_id = ? AND video_id = ?", userID, videoID).Order("version DESC").First(&insight)
 
 	if result.Error != nil {
 		if result.Error == gorm.ErrRecordNotFound {
@@ -51,4 +93,117 @@ func (r *insightRepository) GetInsightByVideoID(ctx context.Context, userID uuid
 		return nil, fmt.Errorf("échec de la récupération de l'insight: %w", result.Error)
 	}
 	return &insight, nil
-}
\ No newline at end of file
+}
+
+// GetInsightByID récupère un Insight par son ID, limité à l'utilisateur propriétaire
+func (r *insightRepository) GetInsightByID(ctx context.Context, userID, insightID uuid.UUID) (*models.Insight, error) {
+	var insight models.Insight
+	result := database.Conn(ctx, r.db).Where("id = ? AND user_id = ?", insightID, userID).First(&insight)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("échec de la récupération de l'insight: %w", result.Error)
+	}
+	return &insight, nil
+}
+
+// ListInsights retourne une page d'Insights de l'utilisateur ainsi que le nombre total de résultats
+func (r *insightRepository) ListInsights(ctx context.Context, filter InsightFilter) ([]models.Insight, int64, error) {
+	query := database.Conn(ctx, r.db).Model(&models.Insight{}).Where("user_id = ?", filter.UserID)
+	if filter.VideoID != "" {
+		query = query.Where("video_id = ?", filter.VideoID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("échec du comptage des insights: %w", err)
+	}
+
+	column, ok := insightSortColumns[filter.SortBy]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "ASC"
+	if filter.SortDesc {
+		direction = "DESC"
+	}
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+
+	var insights []models.Insight
+	err := query.Order(column + " " + direction).Order("id").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&insights).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("échec de la récupération des insights: %w", err)
+	}
+	return insights, total, nil
+}
+
+// ListInsightsByVideo retourne l'historique complet des Insights d'une vidéo, de la version la plus récente à la plus ancienne
+func (r *insightRepository) ListInsightsByVideo(ctx context.Context, userID uuid.UUID, videoID string) ([]models.Insight, error) {
+	var insights []models.Insight
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ? AND video_id = ?", userID, videoID).
+		Order("version DESC").
+		Find(&insights).Error
+	if err != nil {
+		return nil, fmt.Errorf("échec de la récupération de l'historique des insights: %w", err)
+	}
+	return insights, nil
+}
+
+// DeleteInsight supprime un Insight de l'utilisateur. Retourne gorm.ErrRecordNotFound s'il n'existe pas.
+func (r *insightRepository) DeleteInsight(ctx context.Context, userID, insightID uuid.UUID) error {
+	result := database.Conn(ctx, r.db).Where("id = ? AND user_id = ?", insightID, userID).Delete(&models.Insight{})
+	if result.Error != nil {
+		return fmt.Errorf("échec de la suppression de l'insight: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetInsightVersion récupère une version précise de l'Insight d'une vidéo (nil, nil si absente)
+func (r *insightRepository) GetInsightVersion(ctx context.Context, userID uuid.UUID, videoID string, version int) (*models.Insight, error) {
+	var insight models.Insight
+	result := database.Conn(ctx, r.db).Where("user_id = ? AND video_id = ? AND version = ?", userID, videoID, version).First(&insight)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("échec de la récupération de la version %d de l'insight: %w", version, result.Error)
+	}
+	return &insight, nil
+}
+
+// GetInsightsByIDs récupère les Insights de l'utilisateur parmi les IDs donnés (les IDs inconnus sont ignorés)
+func (r *insightRepository) GetInsightsByIDs(ctx context.Context, userID uuid.UUID, insightIDs []uuid.UUID) ([]models.Insight, error) {
+	var insights []models.Insight
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ? AND id IN ?", userID, insightIDs).
+		Order("created_at DESC").
+		Find(&insights).Error
+	if err != nil {
+		return nil, fmt.Errorf("échec de la récupération des insights: %w", err)
+	}
+	return insights, nil
+}
+
+func (r *insightRepository) GetInsightOwner(ctx context.Context, insightID uuid.UUID) (uuid.UUID, error) {
+	var insight models.Insight
+	if err := database.Conn(ctx, r.db).Select("user_id").Where("id = ?", insightID).First(&insight).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return insight.UserID, nil
+}
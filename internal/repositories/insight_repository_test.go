@@ -0,0 +1,147 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestInsightRepositoryNumbersVersionsPerVideo(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewInsightRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+		other := createTestUser(t, db, "bob")
+
+		for _, insight := range []*models.Insight{
+			{UserID: user.ID, VideoID: "v1", Summary: "premier"},
+			{UserID: user.ID, VideoID: "v1", Summary: "second"},
+			{UserID: user.ID, VideoID: "v2"},
+			{UserID: other.ID, VideoID: "v1"},
+		} {
+			if err := repo.CreateInsight(ctx, insight); err != nil {
+				t.Fatalf("CreateInsight: %v", err)
+			}
+		}
+
+		latest, err := repo.GetInsightByVideoID(ctx, user.ID, "v1")
+		if err != nil {
+			t.Fatalf("GetInsightByVideoID: %v", err)
+		}
+		if latest.Version != 2 || latest.Summary != "second" {
+			t.Fatalf("dernier insight inattendu: version %d, %q", latest.Version, latest.Summary)
+		}
+		first, err := repo.GetInsightVersion(ctx, user.ID, "v1", 1)
+		if err != nil || first.Summary != "premier" {
+			t.Fatalf("GetInsightVersion(1): %v, %+v", err, first)
+		}
+		history, err := repo.ListInsightsByVideo(ctx, user.ID, "v1")
+		if err != nil || len(history) != 2 || history[0].Version != 2 {
+			t.Fatalf("historique inattendu: %v, %d élément(s)", err, len(history))
+		}
+		// Numérotation indépendante par (utilisateur, vidéo)
+		if own, _ := repo.GetInsightByVideoID(ctx, other.ID, "v1"); own.Version != 1 {
+			t.Fatalf("version de l'autre utilisateur: %d", own.Version)
+		}
+	})
+}
+
+func TestInsightRepositoryDoesNotReuseDeletedVersionNumbers(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewInsightRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+
+		first := &models.Insight{UserID: user.ID, VideoID: "v1"}
+		second := &models.Insight{UserID: user.ID, VideoID: "v1"}
+		for _, insight := range []*models.Insight{first, second} {
+			if err := repo.CreateInsight(ctx, insight); err != nil {
+				t.Fatalf("CreateInsight: %v", err)
+			}
+		}
+		if err := repo.DeleteInsight(ctx, user.ID, second.ID); err != nil {
+			t.Fatalf("DeleteInsight: %v", err)
+		}
+
+		// La version 2 supprimée reste attribuée : la suivante est la 3
+		third := &models.Insight{UserID: user.ID, VideoID: "v1"}
+		if err := repo.CreateInsight(ctx, third); err != nil {
+			t.Fatalf("CreateInsight: %v", err)
+		}
+		if third.Version != 3 {
+			t.Fatalf("version après suppression: %d", third.Version)
+		}
+		if gone, err := repo.GetInsightVersion(ctx, user.ID, "v1", 2); err != nil || gone != nil {
+			t.Fatalf("version 2 supprimée: %v, %+v", err, gone)
+		}
+	})
+}
+
+func TestInsightRepositoryListInsightsFiltersAndPaginates(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewInsightRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+		base := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+		for i, videoID := range []string{"c", "a", "b", "a"} {
+			insight := &models.Insight{UserID: user.ID, VideoID: videoID, CreatedAt: base.Add(time.Duration(i) * 24 * time.Hour)}
+			if err := repo.CreateInsight(ctx, insight); err != nil {
+				t.Fatalf("CreateInsight: %v", err)
+			}
+		}
+
+		page, total, err := repo.ListInsights(ctx, InsightFilter{UserID: user.ID, SortBy: "video_id", Page: 1, PageSize: 3})
+		if err != nil {
+			t.Fatalf("ListInsights: %v", err)
+		}
+		if total != 4 || len(page) != 3 || page[0].VideoID != "a" || page[2].VideoID != "b" {
+			t.Fatalf("page inattendue: total %d, %d élément(s)", total, len(page))
+		}
+
+		from := base.Add(24 * time.Hour)
+		to := base.Add(2 * 24 * time.Hour)
+		ranged, total, err := repo.ListInsights(ctx, InsightFilter{UserID: user.ID, From: &from, To: &to, SortDesc: true, Page: 1, PageSize: 10})
+		if err != nil {
+			t.Fatalf("ListInsights (dates): %v", err)
+		}
+		if total != 2 || ranged[0].VideoID != "b" || ranged[1].VideoID != "a" {
+			t.Fatalf("filtre de dates inattendu: total %d", total)
+		}
+	})
+}
+
+func TestInsightRepositoryScopesAccessToOwner(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewInsightRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+		intruder := createTestUser(t, db, "mallory")
+
+		insight := &models.Insight{UserID: user.ID, VideoID: "v1"}
+		if err := repo.CreateInsight(ctx, insight); err != nil {
+			t.Fatalf("CreateInsight: %v", err)
+		}
+
+		if owner, err := repo.GetInsightOwner(ctx, insight.ID); err != nil || owner != user.ID {
+			t.Fatalf("GetInsightOwner: %v, %s", err, owner)
+		}
+		if _, err := repo.GetInsightOwner(ctx, uuid.New()); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("insight inconnu: attendu ErrRecordNotFound, obtenu %v", err)
+		}
+		if found, _ := repo.GetInsightsByIDs(ctx, intruder.ID, []uuid.UUID{insight.ID}); len(found) != 0 {
+			t.Fatal("insight d'un autre utilisateur retourné")
+		}
+		if err := repo.DeleteInsight(ctx, intruder.ID, insight.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("suppression par un autre utilisateur: %v", err)
+		}
+		if err := repo.DeleteInsight(ctx, user.ID, insight.ID); err != nil {
+			t.Fatalf("DeleteInsight: %v", err)
+		}
+	})
+}
@@ -0,0 +1,220 @@
+// internal/repositories/login_attempt_repository.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// LoginAttemptRepository stocke les tentatives de connexion.
+// Deux implémentations : en mémoire (instance unique) et Postgres (partagée entre les instances d'un cluster).
+type LoginAttemptRepository interface {
+	// GetLoginAttempt retourne nil, nil si aucun échec n'est enregistré pour la clé
+	GetLoginAttempt(ctx context.Context, key string) (*models.LoginAttempt, error)
+	// ReserveLoginAttempt compte une tentative avant la vérification du mot de passe, de façon
+	// atomique, et retourne le compteur ainsi incrémenté : deux tentatives concurrentes obtiennent
+	// deux valeurs distinctes. Le compteur repart de 1 (verrou levé) si le premier échec est
+	// antérieur à windowStart ou si le verrouillage a expiré.
+	ReserveLoginAttempt(ctx context.Context, key string, now, windowStart time.Time) (*models.LoginAttempt, error)
+	// ReleaseLoginAttempt annule une tentative réservée (refusée ou réussie)
+	ReleaseLoginAttempt(ctx context.Context, key string) error
+	// RecordLoginFailure date l'échec d'une tentative réservée, point de départ du délai suivant
+	RecordLoginFailure(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error)
+	LockLoginKey(ctx context.Context, key string, until time.Time) error
+	ResetLoginAttempts(ctx context.Context, key string) error
+	// PurgeLoginAttempts supprime les entrées sans échec récent ni verrouillage actif
+	PurgeLoginAttempts(ctx context.Context, before time.Time) error
+}
+
+// --- Implémentation Postgres ---
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+// NewLoginAttemptRepository crée le store Postgres
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) GetLoginAttempt(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	result := database.Conn(ctx, r.db).Where("attempt_key = ?", key).First(&attempt)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("échec de la récupération des tentatives de connexion: %w", result.Error)
+	}
+	return &attempt, nil
+}
+
+func (r *loginAttemptRepository) ReserveLoginAttempt(ctx context.Context, key string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	// Upsert atomique : plusieurs instances peuvent compter les tentatives d'une même clé en parallèle
+	var attempt models.LoginAttempt
+	err := database.Conn(ctx, r.db).Raw(`
+		INSERT INTO login_attempts (attempt_key, failures, first_failure_at, last_failure_at)
+		VALUES (@key, 1, @now, @now)
+		ON CONFLICT (attempt_key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.first_failure_at < @windowStart OR login_attempts.locked_until <= @now
+				THEN 1 ELSE login_attempts.failures + 1 END,
+			first_failure_at = CASE WHEN login_attempts.first_failure_at < @windowStart OR login_attempts.locked_until <= @now
+				THEN EXCLUDED.first_failure_at ELSE login_attempts.first_failure_at END,
+			last_failure_at = CASE WHEN login_attempts.first_failure_at < @windowStart OR login_attempts.locked_until <= @now
+				THEN EXCLUDED.last_failure_at ELSE login_attempts.last_failure_at END,
+			locked_until = CASE WHEN login_attempts.locked_until <= @now THEN NULL ELSE login_attempts.locked_until END
+		RETURNING *`,
+		map[string]any{"key": key, "now": now, "windowStart": windowStart},
+	).Scan(&attempt).Error
+	if err != nil {
+		return nil, fmt.Errorf("échec de la réservation de la tentative de connexion: %w", err)
+	}
+	return &attempt, nil
+}
+
+func (r *loginAttemptRepository) ReleaseLoginAttempt(ctx context.Context, key string) error {
+	err := database.Conn(ctx, r.db).Model(&models.LoginAttempt{}).
+		Where("attempt_key = ? AND failures > 0", key).
+		Update("failures", gorm.Expr("failures - 1")).Error
+	if err != nil {
+		return fmt.Errorf("échec de l'annulation de la tentative de connexion: %w", err)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) RecordLoginFailure(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	err := database.Conn(ctx, r.db).Raw(
+		"UPDATE login_attempts SET last_failure_at = ? WHERE attempt_key = ? RETURNING *", now, key,
+	).Scan(&attempt).Error
+	if err != nil {
+		return nil, fmt.Errorf("échec de l'enregistrement de l'échec de connexion: %w", err)
+	}
+	if attempt.AttemptKey == "" {
+		return nil, fmt.Errorf("échec de l'enregistrement de l'échec de connexion: aucune tentative réservée pour %s", key)
+	}
+	return &attempt, nil
+}
+
+func (r *loginAttemptRepository) LockLoginKey(ctx context.Context, key string, until time.Time) error {
+	err := database.Conn(ctx, r.db).Model(&models.LoginAttempt{}).
+		Where("attempt_key = ?", key).
+		Update("locked_until", until).Error
+	if err != nil {
+		return fmt.Errorf("échec du verrouillage de la connexion: %w", err)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	if err := database.Conn(ctx, r.db).Where("attempt_key = ?", key).Delete(&models.LoginAttempt{}).Error; err != nil {
+		return fmt.Errorf("échec de la réinitialisation des tentatives de connexion: %w", err)
+	}
+	return nil
+}
+
+func (r *loginAttemptRepository) PurgeLoginAttempts(ctx context.Context, before time.Time) error {
+	err := database.Conn(ctx, r.db).
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, before).
+		Delete(&models.LoginAttempt{}).Error
+	if err != nil {
+		return fmt.Errorf("échec de la purge des tentatives de connexion: %w", err)
+	}
+	return nil
+}
+
+// --- Implémentation en mémoire ---
+
+type memoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]models.LoginAttempt
+}
+
+// NewMemoryLoginAttemptRepository crée un store local au processus (perdu au redémarrage, non partagé)
+func NewMemoryLoginAttemptRepository() LoginAttemptRepository {
+	return &memoryLoginAttemptRepository{attempts: make(map[string]models.LoginAttempt)}
+}
+
+func (r *memoryLoginAttemptRepository) GetLoginAttempt(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	return &attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) ReserveLoginAttempt(ctx context.Context, key string, now, windowStart time.Time) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[key]
+	lockExpired := attempt.LockedUntil != nil && !attempt.LockedUntil.After(now)
+	if !ok || attempt.FirstFailureAt.Before(windowStart) || lockExpired {
+		lockedUntil := attempt.LockedUntil
+		if lockExpired {
+			lockedUntil = nil
+		}
+		attempt = models.LoginAttempt{AttemptKey: key, FirstFailureAt: now, LastFailureAt: now, LockedUntil: lockedUntil}
+	}
+	attempt.Failures++
+	r.attempts[key] = attempt
+	return &attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) ReleaseLoginAttempt(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if attempt, ok := r.attempts[key]; ok && attempt.Failures > 0 {
+		attempt.Failures--
+		r.attempts[key] = attempt
+	}
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) RecordLoginFailure(ctx context.Context, key string, now time.Time) (*models.LoginAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempt, ok := r.attempts[key]
+	if !ok {
+		return nil, fmt.Errorf("échec de l'enregistrement de l'échec de connexion: aucune tentative réservée pour %s", key)
+	}
+	attempt.LastFailureAt = now
+	r.attempts[key] = attempt
+	return &attempt, nil
+}
+
+func (r *memoryLoginAttemptRepository) LockLoginKey(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if attempt, ok := r.attempts[key]; ok {
+		attempt.LockedUntil = &until
+		r.attempts[key] = attempt
+	}
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, key)
+	return nil
+}
+
+func (r *memoryLoginAttemptRepository) PurgeLoginAttempts(ctx context.Context, before time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for key, attempt := range r.attempts {
+		if attempt.LastFailureAt.Before(before) && (attempt.LockedUntil == nil || attempt.LockedUntil.Before(before)) {
+			delete(r.attempts, key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Les deux implémentations (base et mémoire) doivent se comporter à l'identique
+func forEachLoginAttemptStore(t *testing.T, test func(t *testing.T, repo LoginAttemptRepository)) {
+	t.Run("memory", func(t *testing.T) { test(t, NewMemoryLoginAttemptRepository()) })
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) { test(t, NewLoginAttemptRepository(db)) })
+}
+
+func TestLoginAttemptRepositoryCountsFailuresWithinWindow(t *testing.T) {
+	forEachLoginAttemptStore(t, func(t *testing.T, repo LoginAttemptRepository) {
+		ctx := t.Context()
+		start := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+		for i := 1; i <= 3; i++ {
+			now := start.Add(time.Duration(i) * time.Minute)
+			attempt, err := repo.ReserveLoginAttempt(ctx, "user:alice", now, start)
+			if err != nil {
+				t.Fatalf("ReserveLoginAttempt: %v", err)
+			}
+			if attempt.Failures != i {
+				t.Fatalf("échec %d: compteur à %d", i, attempt.Failures)
+			}
+		}
+
+		// Premier échec antérieur à la nouvelle fenêtre : le compteur repart de 1
+		later := start.Add(time.Hour)
+		attempt, err := repo.ReserveLoginAttempt(ctx, "user:alice", later, later.Add(-15*time.Minute))
+		if err != nil {
+			t.Fatalf("ReserveLoginAttempt: %v", err)
+		}
+		if attempt.Failures != 1 || !attempt.FirstFailureAt.Equal(later) {
+			t.Fatalf("fenêtre non réinitialisée: %d échec(s), premier à %s", attempt.Failures, attempt.FirstFailureAt)
+		}
+	})
+}
+
+func TestLoginAttemptRepositoryLockResetAndPurge(t *testing.T) {
+	forEachLoginAttemptStore(t, func(t *testing.T, repo LoginAttemptRepository) {
+		ctx := t.Context()
+		now := time.Now().Truncate(time.Second)
+
+		if _, err := repo.ReserveLoginAttempt(ctx, "ip:10.0.0.1", now.Add(-2*time.Hour), now.Add(-3*time.Hour)); err != nil {
+			t.Fatalf("ReserveLoginAttempt: %v", err)
+		}
+		if _, err := repo.ReserveLoginAttempt(ctx, "user:locked", now.Add(-2*time.Hour), now.Add(-3*time.Hour)); err != nil {
+			t.Fatalf("ReserveLoginAttempt: %v", err)
+		}
+		until := now.Add(time.Hour)
+		if err := repo.LockLoginKey(ctx, "user:locked", until); err != nil {
+			t.Fatalf("LockLoginKey: %v", err)
+		}
+		locked, err := repo.GetLoginAttempt(ctx, "user:locked")
+		if err != nil || locked == nil || locked.LockedUntil == nil || !locked.LockedUntil.Equal(until) {
+			t.Fatalf("verrouillage non enregistré: %v, %+v", err, locked)
+		}
+
+		// Purge : l'entrée ancienne disparaît, l'entrée verrouillée reste
+		if err := repo.PurgeLoginAttempts(ctx, now.Add(-time.Hour)); err != nil {
+			t.Fatalf("PurgeLoginAttempts: %v", err)
+		}
+		if attempt, _ := repo.GetLoginAttempt(ctx, "ip:10.0.0.1"); attempt != nil {
+			t.Fatal("entrée expirée non purgée")
+		}
+		if attempt, _ := repo.GetLoginAttempt(ctx, "user:locked"); attempt == nil {
+			t.Fatal("entrée verrouillée purgée")
+		}
+
+		if err := repo.ResetLoginAttempts(ctx, "user:locked"); err != nil {
+			t.Fatalf("ResetLoginAttempts: %v", err)
+		}
+		if attempt, _ := repo.GetLoginAttempt(ctx, "user:locked"); attempt != nil {
+			t.Fatal("entrée non réinitialisée")
+		}
+	})
+}
+
+func TestLoginAttemptRepositoryReleaseRecordAndLockExpiry(t *testing.T) {
+	forEachLoginAttemptStore(t, func(t *testing.T, repo LoginAttemptRepository) {
+		ctx := t.Context()
+		start := time.Now().Add(-time.Hour).Truncate(time.Second)
+		windowStart := start.Add(-time.Hour)
+
+		if _, err := repo.RecordLoginFailure(ctx, "user:alice", start); err == nil {
+			t.Fatal("échec enregistré sans tentative réservée")
+		}
+		for i := 0; i < 2; i++ {
+			if _, err := repo.ReserveLoginAttempt(ctx, "user:alice", start, windowStart); err != nil {
+				t.Fatalf("ReserveLoginAttempt: %v", err)
+			}
+		}
+		if err := repo.ReleaseLoginAttempt(ctx, "user:alice"); err != nil {
+			t.Fatalf("ReleaseLoginAttempt: %v", err)
+		}
+		failedAt := start.Add(time.Minute)
+		attempt, err := repo.RecordLoginFailure(ctx, "user:alice", failedAt)
+		if err != nil {
+			t.Fatalf("RecordLoginFailure: %v", err)
+		}
+		if attempt.Failures != 1 || !attempt.LastFailureAt.Equal(failedAt) {
+			t.Fatalf("tentative annulée ou échec non daté: %d échec(s), dernier à %s", attempt.Failures, attempt.LastFailureAt)
+		}
+
+		// Verrou expiré : la réservation suivante repart de zéro et lève le verrou
+		if err := repo.LockLoginKey(ctx, "user:alice", start.Add(2*time.Minute)); err != nil {
+			t.Fatalf("LockLoginKey: %v", err)
+		}
+		attempt, err = repo.ReserveLoginAttempt(ctx, "user:alice", start.Add(3*time.Minute), windowStart)
+		if err != nil {
+			t.Fatalf("ReserveLoginAttempt: %v", err)
+		}
+		if attempt.Failures != 1 || attempt.LockedUntil != nil {
+			t.Fatalf("verrou expiré non levé: %d échec(s), verrou %v", attempt.Failures, attempt.LockedUntil)
+		}
+	})
+}
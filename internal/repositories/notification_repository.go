@@ -0,0 +1,71 @@
+// internal/repositories/notification_repository.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// NotificationRepository définit les opérations pour les Notifications
+type NotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *models.Notification) error
+	ListNotificationsByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]models.Notification, error)
+	MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID) error
+	// GetNotificationOwner retourne le destinataire de la notification (gorm.ErrRecordNotFound si elle n'existe pas)
+	GetNotificationOwner(ctx context.Context, notificationID uuid.UUID) (uuid.UUID, error)
+}
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository crée une nouvelle instance de NotificationRepository
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) CreateNotification(ctx context.Context, notification *models.Notification) error {
+	if err := database.Conn(ctx, r.db).Create(notification).Error; err != nil {
+		return fmt.Errorf("échec de la création de la notification: %w", err)
+	}
+	return nil
+}
+
+func (r *notificationRepository) ListNotificationsByUser(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := database.Conn(ctx, r.db).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+	if err := query.Order("created_at DESC").Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("échec de la récupération des notifications: %w", err)
+	}
+	return notifications, nil
+}
+
+func (r *notificationRepository) MarkNotificationRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	result := database.Conn(ctx, r.db).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		Update("is_read", true)
+	if result.Error != nil {
+		return fmt.Errorf("échec de la mise à jour de la notification: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *notificationRepository) GetNotificationOwner(ctx context.Context, notificationID uuid.UUID) (uuid.UUID, error) {
+	var notification models.Notification
+	if err := database.Conn(ctx, r.db).Select("user_id").Where("id = ?", notificationID).First(&notification).Error; err != nil {
+		return uuid.Nil, err
+	}
+	return notification.UserID, nil
+}
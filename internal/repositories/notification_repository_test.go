@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestNotificationRepositoryReadState(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewNotificationRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+		intruder := createTestUser(t, db, "mallory")
+
+		first := &models.Notification{UserID: user.ID, Type: models.NotificationTypeAnalysis, Message: "premier"}
+		second := &models.Notification{UserID: user.ID, Type: models.NotificationTypeAlert, Message: "second"}
+		for _, notification := range []*models.Notification{first, second} {
+			if err := repo.CreateNotification(ctx, notification); err != nil {
+				t.Fatalf("CreateNotification: %v", err)
+			}
+		}
+		invalid := &models.Notification{UserID: user.ID, Type: "spam", Message: "invalide"}
+		if err := repo.CreateNotification(ctx, invalid); !errors.Is(err, models.ErrInvalidEnumValue) {
+			t.Fatalf("type invalide: attendu ErrInvalidEnumValue, obtenu %v", err)
+		}
+
+		if err := repo.MarkNotificationRead(ctx, intruder.ID, first.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("lecture par un autre utilisateur: %v", err)
+		}
+		if err := repo.MarkNotificationRead(ctx, user.ID, first.ID); err != nil {
+			t.Fatalf("MarkNotificationRead: %v", err)
+		}
+
+		unread, err := repo.ListNotificationsByUser(ctx, user.ID, true)
+		if err != nil || len(unread) != 1 || unread[0].ID != second.ID {
+			t.Fatalf("non lues: %v, %d élément(s)", err, len(unread))
+		}
+		all, err := repo.ListNotificationsByUser(ctx, user.ID, false)
+		if err != nil || len(all) != 2 {
+			t.Fatalf("toutes: %v, %d élément(s)", err, len(all))
+		}
+	})
+}
@@ -0,0 +1,81 @@
+// internal/repositories/oauth_identity_repository.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// OAuthIdentityRepository gère les identités externes (Google...) liées aux comptes
+type OAuthIdentityRepository interface {
+	// FindIdentity retourne nil, nil si l'identité n'est liée à aucun compte
+	FindIdentity(ctx context.Context, provider, subject string) (*models.OAuthIdentity, error)
+	// FindUserIdentity retourne nil, nil si l'utilisateur n'a pas lié ce fournisseur
+	FindUserIdentity(ctx context.Context, userID uuid.UUID, provider string) (*models.OAuthIdentity, error)
+	CreateIdentity(ctx context.Context, identity *models.OAuthIdentity) error
+	UpdateIdentity(ctx context.Context, identity *models.OAuthIdentity) error
+	// CreateUserWithIdentity crée le compte et son identité externe dans une transaction
+	CreateUserWithIdentity(ctx context.Context, user *models.User, identity *models.OAuthIdentity) error
+}
+
+type oauthIdentityRepository struct {
+	db *gorm.DB
+}
+
+// NewOAuthIdentityRepository crée une nouvelle instance de OAuthIdentityRepository
+func NewOAuthIdentityRepository(db *gorm.DB) OAuthIdentityRepository {
+	return &oauthIdentityRepository{db: db}
+}
+
+func (r *oauthIdentityRepository) FindIdentity(ctx context.Context, provider, subject string) (*models.OAuthIdentity, error) {
+	return r.findOne(ctx, "provider = ? AND subject = ?", provider, subject)
+}
+
+func (r *oauthIdentityRepository) FindUserIdentity(ctx context.Context, userID uuid.UUID, provider string) (*models.OAuthIdentity, error) {
+	return r.findOne(ctx, "user_id = ? AND provider = ?", userID, provider)
+}
+
+func (r *oauthIdentityRepository) findOne(ctx context.Context, query string, args ...any) (*models.OAuthIdentity, error) {
+	var identity models.OAuthIdentity
+	result := database.Conn(ctx, r.db).Where(query, args...).First(&identity)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("échec de la récupération de l'identité externe: %w", result.Error)
+	}
+	return &identity, nil
+}
+
+func (r *oauthIdentityRepository) CreateIdentity(ctx context.Context, identity *models.OAuthIdentity) error {
+	if err := database.Conn(ctx, r.db).Create(identity).Error; err != nil {
+		return fmt.Errorf("échec de la création de l'identité externe: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthIdentityRepository) UpdateIdentity(ctx context.Context, identity *models.OAuthIdentity) error {
+	if err := database.Conn(ctx, r.db).Save(identity).Error; err != nil {
+		return fmt.Errorf("échec de la mise à jour de l'identité externe: %w", err)
+	}
+	return nil
+}
+
+func (r *oauthIdentityRepository) CreateUserWithIdentity(ctx context.Context, user *models.User, identity *models.OAuthIdentity) error {
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return fmt.Errorf("échec de la création du compte: %w", err)
+		}
+		identity.UserID = user.ID
+		if err := tx.Create(identity).Error; err != nil {
+			return fmt.Errorf("échec de la création de l'identité externe: %w", err)
+		}
+		return nil
+	})
+}
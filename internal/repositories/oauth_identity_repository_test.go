@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestOAuthIdentityRepositoryCreatesUserWithIdentity(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewOAuthIdentityRepository(db)
+		ctx := t.Context()
+
+		user := &models.User{Username: "alice", Email: "alice@example.com"}
+		identity := &models.OAuthIdentity{Provider: "google", Subject: "sub-1", Email: user.Email}
+		if err := repo.CreateUserWithIdentity(ctx, user, identity); err != nil {
+			t.Fatalf("CreateUserWithIdentity: %v", err)
+		}
+
+		found, err := repo.FindIdentity(ctx, "google", "sub-1")
+		if err != nil || found == nil || found.UserID != user.ID {
+			t.Fatalf("FindIdentity: %v, %+v", err, found)
+		}
+		if linked, err := repo.FindUserIdentity(ctx, user.ID, "google"); err != nil || linked == nil {
+			t.Fatalf("FindUserIdentity: %v", err)
+		}
+
+		// Le même sujet ne peut être lié qu'une fois ; la transaction annule la création du compte
+		second := &models.User{Username: "alice2", Email: "alice2@example.com"}
+		duplicate := &models.OAuthIdentity{Provider: "google", Subject: "sub-1"}
+		if err := repo.CreateUserWithIdentity(ctx, second, duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("sujet dupliqué: attendu ErrDuplicatedKey, obtenu %v", err)
+		}
+		var count int64
+		db.Model(&models.User{}).Where("username = ?", "alice2").Count(&count)
+		if count != 0 {
+			t.Fatal("compte créé malgré l'échec de la liaison")
+		}
+	})
+}
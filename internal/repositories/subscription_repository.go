@@ -0,0 +1,54 @@
+// internal/repositories/subscription_repository.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+type SubscriptionRepository interface {
+	CreateSubscription(ctx context.Context, subscription *models.Subscription) error
+	// GetActivePlan retourne le plan de l'abonnement actif le plus récent (non expiré ; une
+	// date d'expiration nulle signifie sans échéance), ou models.PlanFree si l'utilisateur n'en a pas
+	GetActivePlan(ctx context.Context, userID uuid.UUID) (string, error)
+}
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func (r *subscriptionRepository) CreateSubscription(ctx context.Context, subscription *models.Subscription) error {
+	if err := database.Conn(ctx, r.db).Create(subscription).Error; err != nil {
+		return fmt.Errorf("échec de la création de l'abonnement: %w", err)
+	}
+	return nil
+}
+
+func (r *subscriptionRepository) GetActivePlan(ctx context.Context, userID uuid.UUID) (string, error) {
+	var subscriptions []models.Subscription
+	err := database.Conn(ctx, r.db).
+		Where("user_id = ? AND status = ?", userID, models.SubscriptionActive).
+		Order("created_at DESC").
+		Find(&subscriptions).Error
+	if err != nil {
+		return "", fmt.Errorf("échec de la récupération de l'abonnement: %w", err)
+	}
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		if subscription.ExpiresAt.IsZero() || subscription.ExpiresAt.After(now) {
+			return subscription.Plan, nil
+		}
+	}
+	return models.PlanFree, nil
+}
@@ -0,0 +1,45 @@
+// internal/repositories/subscription_repository_test.go
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestSubscriptionRepositoryGetActivePlan(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewSubscriptionRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+
+		if plan, err := repo.GetActivePlan(ctx, user.ID); err != nil || plan != models.PlanFree {
+			t.Fatalf("sans abonnement: %q, %v", plan, err)
+		}
+
+		now := time.Now()
+		subscriptions := []*models.Subscription{
+			{UserID: user.ID, Plan: models.PlanBusiness, Status: models.SubscriptionCancelled, CreatedAt: now.Add(-time.Minute)},
+			{UserID: user.ID, Plan: models.PlanPro, ExpiresAt: now.Add(-time.Hour), CreatedAt: now.Add(-2 * time.Minute)},
+		}
+		for _, subscription := range subscriptions {
+			if err := repo.CreateSubscription(ctx, subscription); err != nil {
+				t.Fatalf("CreateSubscription: %v", err)
+			}
+		}
+		if plan, err := repo.GetActivePlan(ctx, user.ID); err != nil || plan != models.PlanFree {
+			t.Fatalf("abonnements annulé et expiré: %q, %v", plan, err)
+		}
+
+		unlimited := &models.Subscription{UserID: user.ID, Plan: models.PlanPro, CreatedAt: now.Add(-3 * time.Minute)}
+		if err := repo.CreateSubscription(ctx, unlimited); err != nil {
+			t.Fatalf("CreateSubscription: %v", err)
+		}
+		if plan, err := repo.GetActivePlan(ctx, user.ID); err != nil || plan != models.PlanPro {
+			t.Fatalf("abonnement sans échéance: %q, %v", plan, err)
+		}
+	})
+}
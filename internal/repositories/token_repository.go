@@ -0,0 +1,169 @@
+// internal/repositories/token_repository.go
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// TokenRepository gère les refresh tokens, la liste de révocation des tokens d'accès
+// et les tokens à usage unique envoyés par email
+type TokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error
+	FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	// MarkRefreshTokenUsed retourne false si le token avait déjà été utilisé (échange concurrent ou rejeu)
+	MarkRefreshTokenUsed(ctx context.Context, tokenID uuid.UUID, usedAt time.Time) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error
+	RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error
+	RevokeAccessToken(ctx context.Context, revoked *models.RevokedAccessToken) error
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	PurgeExpiredTokens(ctx context.Context, before time.Time) error
+
+	CreateAccountToken(ctx context.Context, token *models.AccountToken) error
+	// ConsumeAccountToken marque le token comme utilisé et le retourne ; nil, nil s'il est inconnu, expiré ou déjà utilisé
+	ConsumeAccountToken(ctx context.Context, tokenHash, purpose string, now time.Time) (*models.AccountToken, error)
+	// InvalidateAccountTokens rend inutilisables les tokens encore valides de l'utilisateur pour cet usage
+	InvalidateAccountTokens(ctx context.Context, userID uuid.UUID, purpose string) error
+}
+
+type tokenRepository struct {
+	db *gorm.DB
+}
+
+// NewTokenRepository crée une nouvelle instance de TokenRepository
+func NewTokenRepository(db *gorm.DB) TokenRepository {
+	return &tokenRepository{db: db}
+}
+
+func (r *tokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	if err := database.Conn(ctx, r.db).Create(token).Error; err != nil {
+		return fmt.Errorf("échec de la création du refresh token: %w", err)
+	}
+	return nil
+}
+
+// FindRefreshTokenByHash retourne nil, nil si aucun token ne correspond
+func (r *tokenRepository) FindRefreshTokenByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	result := database.Conn(ctx, r.db).Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("échec de la récupération du refresh token: %w", result.Error)
+	}
+	return &token, nil
+}
+
+func (r *tokenRepository) MarkRefreshTokenUsed(ctx context.Context, tokenID uuid.UUID, usedAt time.Time) (bool, error) {
+	// Mise à jour conditionnelle : un seul échange peut réussir pour un même token
+	result := database.Conn(ctx, r.db).Model(&models.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", tokenID).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("échec de la mise à jour du refresh token: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *tokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	err := database.Conn(ctx, r.db).Model(&models.RefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("échec de la révocation de la famille de refresh tokens: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) RevokeUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	err := database.Conn(ctx, r.db).Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("échec de la révocation des refresh tokens de l'utilisateur: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) RevokeAccessToken(ctx context.Context, revoked *models.RevokedAccessToken) error {
+	// Une double déconnexion avec le même token ne doit pas échouer
+	err := database.Conn(ctx, r.db).Where(models.RevokedAccessToken{JTI: revoked.JTI}).FirstOrCreate(revoked).Error
+	if err != nil {
+		return fmt.Errorf("échec de la révocation du token d'accès: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	var count int64
+	err := database.Conn(ctx, r.db).Model(&models.RevokedAccessToken{}).Where("jti = ?", jti).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("échec de la vérification de révocation du token: %w", err)
+	}
+	return count > 0, nil
+}
+
+// PurgeExpiredTokens supprime les entrées devenues inutiles (tokens expirés avant 'before')
+func (r *tokenRepository) PurgeExpiredTokens(ctx context.Context, before time.Time) error {
+	if err := database.Conn(ctx, r.db).Where("expires_at < ?", before).Delete(&models.RevokedAccessToken{}).Error; err != nil {
+		return fmt.Errorf("échec de la purge de la liste de révocation: %w", err)
+	}
+	if err := database.Conn(ctx, r.db).Where("expires_at < ?", before).Delete(&models.RefreshToken{}).Error; err != nil {
+		return fmt.Errorf("échec de la purge des refresh tokens: %w", err)
+	}
+	if err := database.Conn(ctx, r.db).Where("expires_at < ?", before).Delete(&models.AccountToken{}).Error; err != nil {
+		return fmt.Errorf("échec de la purge des tokens de compte: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) CreateAccountToken(ctx context.Context, token *models.AccountToken) error {
+	if err := database.Conn(ctx, r.db).Create(token).Error; err != nil {
+		return fmt.Errorf("échec de la création du token de compte: %w", err)
+	}
+	return nil
+}
+
+func (r *tokenRepository) ConsumeAccountToken(ctx context.Context, tokenHash, purpose string, now time.Time) (*models.AccountToken, error) {
+	var token models.AccountToken
+	result := database.Conn(ctx, r.db).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", tokenHash, purpose, now).
+		First(&token)
+	if result.Error != nil {
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("échec de la récupération du token de compte: %w", result.Error)
+	}
+
+	// Mise à jour conditionnelle : deux confirmations concurrentes ne peuvent pas réussir toutes les deux
+	update := database.Conn(ctx, r.db).Model(&models.AccountToken{}).
+		Where("id = ? AND used_at IS NULL", token.ID).
+		Update("used_at", now)
+	if update.Error != nil {
+		return nil, fmt.Errorf("échec de la consommation du token de compte: %w", update.Error)
+	}
+	if update.RowsAffected == 0 {
+		return nil, nil
+	}
+	token.UsedAt = &now
+	return &token, nil
+}
+
+func (r *tokenRepository) InvalidateAccountTokens(ctx context.Context, userID uuid.UUID, purpose string) error {
+	err := database.Conn(ctx, r.db).Model(&models.AccountToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+	if err != nil {
+		return fmt.Errorf("échec de l'invalidation des tokens de compte: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func testHash(seed string) string {
+	return strings.Repeat(seed, 64)[:64]
+}
+
+func TestTokenRepositoryRefreshTokenCanBeUsedOnce(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewTokenRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+
+		token := &models.RefreshToken{UserID: user.ID, FamilyID: uuid.New(), TokenHash: testHash("a"), ExpiresAt: time.Now().Add(time.Hour)}
+		if err := repo.CreateRefreshToken(ctx, token); err != nil {
+			t.Fatalf("CreateRefreshToken: %v", err)
+		}
+		found, err := repo.FindRefreshTokenByHash(ctx, token.TokenHash)
+		if err != nil || found == nil || found.ID != token.ID {
+			t.Fatalf("FindRefreshTokenByHash: %v, %+v", err, found)
+		}
+
+		if ok, err := repo.MarkRefreshTokenUsed(ctx, token.ID, time.Now()); err != nil || !ok {
+			t.Fatalf("premier échange refusé: %v", err)
+		}
+		if ok, err := repo.MarkRefreshTokenUsed(ctx, token.ID, time.Now()); err != nil || ok {
+			t.Fatalf("rejeu accepté: %v", err)
+		}
+
+		if err := repo.RevokeRefreshTokenFamily(ctx, token.FamilyID); err != nil {
+			t.Fatalf("RevokeRefreshTokenFamily: %v", err)
+		}
+		if revoked, _ := repo.FindRefreshTokenByHash(ctx, token.TokenHash); revoked.RevokedAt == nil {
+			t.Fatal("famille non révoquée")
+		}
+	})
+}
+
+func TestTokenRepositoryAccessTokenRevocationAndPurge(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewTokenRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+		now := time.Now()
+
+		if err := repo.RevokeAccessToken(ctx, &models.RevokedAccessToken{JTI: "expired", UserID: user.ID, ExpiresAt: now.Add(-time.Minute)}); err != nil {
+			t.Fatalf("RevokeAccessToken: %v", err)
+		}
+		if err := repo.RevokeAccessToken(ctx, &models.RevokedAccessToken{JTI: "active", UserID: user.ID, ExpiresAt: now.Add(time.Hour)}); err != nil {
+			t.Fatalf("RevokeAccessToken: %v", err)
+		}
+		if revoked, err := repo.IsAccessTokenRevoked(ctx, "active"); err != nil || !revoked {
+			t.Fatalf("IsAccessTokenRevoked(active): %v, %t", err, revoked)
+		}
+
+		if err := repo.PurgeExpiredTokens(ctx, now); err != nil {
+			t.Fatalf("PurgeExpiredTokens: %v", err)
+		}
+		if revoked, _ := repo.IsAccessTokenRevoked(ctx, "expired"); revoked {
+			t.Fatal("entrée expirée non purgée")
+		}
+		if revoked, _ := repo.IsAccessTokenRevoked(ctx, "active"); !revoked {
+			t.Fatal("entrée encore valide purgée")
+		}
+	})
+}
+
+func TestTokenRepositoryAccountTokens(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewTokenRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+		now := time.Now()
+
+		valid := &models.AccountToken{UserID: user.ID, Purpose: models.AccountTokenPasswordReset, TokenHash: testHash("v"), ExpiresAt: now.Add(time.Hour)}
+		expired := &models.AccountToken{UserID: user.ID, Purpose: models.AccountTokenPasswordReset, TokenHash: testHash("e"), ExpiresAt: now.Add(-time.Minute)}
+		for _, token := range []*models.AccountToken{valid, expired} {
+			if err := repo.CreateAccountToken(ctx, token); err != nil {
+				t.Fatalf("CreateAccountToken: %v", err)
+			}
+		}
+
+		if token, err := repo.ConsumeAccountToken(ctx, expired.TokenHash, models.AccountTokenPasswordReset, now); err != nil || token != nil {
+			t.Fatalf("token expiré consommé: %v", err)
+		}
+		if token, err := repo.ConsumeAccountToken(ctx, valid.TokenHash, models.AccountTokenEmailVerification, now); err != nil || token != nil {
+			t.Fatalf("token consommé pour un autre usage: %v", err)
+		}
+		token, err := repo.ConsumeAccountToken(ctx, valid.TokenHash, models.AccountTokenPasswordReset, now)
+		if err != nil || token == nil || token.UserID != user.ID {
+			t.Fatalf("ConsumeAccountToken: %v, %+v", err, token)
+		}
+		if again, _ := repo.ConsumeAccountToken(ctx, valid.TokenHash, models.AccountTokenPasswordReset, now); again != nil {
+			t.Fatal("token consommé deux fois")
+		}
+
+		pending := &models.AccountToken{UserID: user.ID, Purpose: models.AccountTokenEmailVerification, TokenHash: testHash("p"), ExpiresAt: now.Add(time.Hour)}
+		if err := repo.CreateAccountToken(ctx, pending); err != nil {
+			t.Fatalf("CreateAccountToken: %v", err)
+		}
+		if err := repo.InvalidateAccountTokens(ctx, user.ID, models.AccountTokenEmailVerification); err != nil {
+			t.Fatalf("InvalidateAccountTokens: %v", err)
+		}
+		if token, _ := repo.ConsumeAccountToken(ctx, pending.TokenHash, models.AccountTokenEmailVerification, now); token != nil {
+			t.Fatal("token invalidé encore utilisable")
+		}
+	})
+}
@@ -0,0 +1,28 @@
+// internal/repositories/transactor.go
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+)
+
+// Transactor regroupe plusieurs écritures (éventuellement sur des repositories différents) dans
+// une même transaction : les repositories appelés avec le ctx reçu par fn y participent.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return database.WithinTransaction(ctx, t.db, fn)
+}
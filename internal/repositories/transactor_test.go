@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestTransactorCommitsCommentsAndInsightTogether(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		transactor := NewTransactor(db)
+		comments := NewCommentRepository(db)
+		insights := NewInsightRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+
+		save := func(ctx context.Context, contents ...string) error {
+			batch := make([]models.Comment, 0, len(contents))
+			for _, content := range contents {
+				batch = append(batch, models.Comment{Content: content, Author: "a", Date: time.Now()})
+			}
+			return transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				if err := comments.ReplaceVideoComments(ctx, user.ID, "v1", batch); err != nil {
+					return err
+				}
+				return insights.CreateInsight(ctx, &models.Insight{UserID: user.ID, VideoID: "v1"})
+			})
+		}
+
+		if err := save(ctx, "un", "deux"); err != nil {
+			t.Fatalf("première analyse: %v", err)
+		}
+		// Une analyse plus récente remplace les commentaires de la précédente
+		if err := save(ctx, "trois"); err != nil {
+			t.Fatalf("seconde analyse: %v", err)
+		}
+		stored, err := comments.FindAllComment(ctx)
+		if err != nil || len(stored) != 1 || stored[0].Content != "trois" || stored[0].UserID != user.ID {
+			t.Fatalf("commentaires inattendus: %v, %d élément(s)", err, len(stored))
+		}
+		if latest, _ := insights.GetInsightByVideoID(ctx, user.ID, "v1"); latest == nil || latest.Version != 2 {
+			t.Fatalf("insight inattendu: %+v", latest)
+		}
+	})
+}
+
+func TestTransactorRollsBackOnError(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		transactor := NewTransactor(db)
+		comments := NewCommentRepository(db)
+		insights := NewInsightRepository(db)
+		ctx := t.Context()
+		user := createTestUser(t, db, "alice")
+
+		errBoom := errors.New("échec simulé")
+		err := transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			batch := []models.Comment{{Content: "orphelin", Author: "a", Date: time.Now()}}
+			if err := comments.ReplaceVideoComments(ctx, user.ID, "v1", batch); err != nil {
+				return err
+			}
+			if err := insights.CreateInsight(ctx, &models.Insight{UserID: user.ID, VideoID: "v1"}); err != nil {
+				return err
+			}
+			return errBoom
+		})
+		if !errors.Is(err, errBoom) {
+			t.Fatalf("attendu l'erreur de fn, obtenu %v", err)
+		}
+
+		if stored, _ := comments.FindAllComment(ctx); len(stored) != 0 {
+			t.Fatalf("%d commentaire(s) orphelin(s) après annulation", len(stored))
+		}
+		if latest, _ := insights.GetInsightByVideoID(ctx, user.ID, "v1"); latest != nil {
+			t.Fatal("insight conservé après annulation")
+		}
+	})
+}
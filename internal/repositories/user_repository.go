@@ -1,16 +1,26 @@
 package repositories
 
 import (
+	"context"
+
+	"github.com/Azertdev/FiberTest/internal/database"
 	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 type UserRepository interface {
-	Create(user *models.User) error
-	FindAll() ([]models.User, error)
-	FindByID(id uint) (*models.User, error)
-	AuthenticateUser(username, password string)(*models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	FindAll(ctx context.Context) ([]models.User, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByUsername(ctx context.Context, username string) (*models.User, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, role string) error
+	UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]any) error
+	// DeleteWithRelations supprime le compte et toutes ses données dans une transaction
+	DeleteWithRelations(ctx context.Context, id uuid.UUID) error
+	AuthenticateUser(ctx context.Context, username, password string)(*models.User, error)
 }
 
 type UserRepo struct {
@@ -21,28 +31,102 @@ func NewUserRepository(db *gorm.DB) UserRepository{
 	return &UserRepo{db}
 }
 
-func (r *UserRepo) Create(user *models.User) error {
-	return r.db.Create(user).Error
+func (r *UserRepo) Create(ctx context.Context, user *models.User) error {
+	return database.Conn(ctx, r.db).Create(user).Error
 }
 
-func (r *UserRepo) FindAll() ([]models.User, error) {
+func (r *UserRepo) FindAll(ctx context.Context) ([]models.User, error) {
 	var users []models.User
-	err := r.db.Find(&users).Error
+	err := database.Conn(ctx, r.db).Find(&users).Error
 	return users, err
 }
 
-func (r *UserRepo) FindByID(id uint) (*models.User, error) {
+func (r *UserRepo) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	var user models.User
+	err := database.Conn(ctx, r.db).Where("id = ?", id).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepo) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := database.Conn(ctx, r.db).Where("LOWER(email) = LOWER(?)", email).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepo) FindByUsername(ctx context.Context, username string) (*models.User, error) {
 	var user models.User
-	err := r.db.First(&user, id).Error
+	err := database.Conn(ctx, r.db).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepo) AuthenticateUser(username, password string) (*models.User, error) {
+func (r *UserRepo) UpdateRole(ctx context.Context, id uuid.UUID, role string) error {
+	if err := models.ValidateRole(role); err != nil {
+		return err
+	}
+	result := database.Conn(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]any) error {
+	result := database.Conn(ctx, r.db).Model(&models.User{}).Where("id = ?", id).Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *UserRepo) DeleteWithRelations(ctx context.Context, id uuid.UUID) error {
+	return database.Conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Données rattachées à l'utilisateur (pas de clés étrangères en base : suppression explicite)
+		related := []any{
+			&models.Comment{},
+			&models.Insight{},
+			&models.Notification{},
+			&models.Subscription{},
+			&models.AlertRule{},
+			&models.RefreshToken{},
+			&models.RevokedAccessToken{},
+			&models.AccountToken{},
+			&models.OAuthIdentity{},
+			&models.APIKey{},
+		}
+		for _, model := range related {
+			if err := tx.Where("user_id = ?", id).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Where("id = ?", id).Delete(&models.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+}
+
+func (r *UserRepo) AuthenticateUser(ctx context.Context, username, password string) (*models.User, error) {
 	var user models.User
-	if err := r.db.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := database.Conn(ctx, r.db).Where("username = ?", username).First(&user).Error; err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestUserRepositoryCreateAssignsIDAndDefaultRole(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		user := createTestUser(t, db, "alice")
+		if user.ID == uuid.Nil {
+			t.Fatal("UUID non généré côté application")
+		}
+
+		found, err := NewUserRepository(db).FindByID(t.Context(), user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Role != models.RoleUser || found.Username != "alice" {
+			t.Fatalf("utilisateur relu inattendu: %+v", found)
+		}
+	})
+}
+
+func TestUserRepositoryRejectsDuplicateAndInvalidRole(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewUserRepository(db)
+		createTestUser(t, db, "alice")
+
+		duplicate := &models.User{Username: "alice", Email: "other@example.com", Password: "x"}
+		if err := repo.Create(t.Context(), duplicate); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			t.Fatalf("doublon de username: attendu ErrDuplicatedKey, obtenu %v", err)
+		}
+
+		invalid := &models.User{Username: "bob", Email: "bob@example.com", Password: "x", Role: "root"}
+		if err := repo.Create(t.Context(), invalid); !errors.Is(err, models.ErrInvalidEnumValue) {
+			t.Fatalf("rôle invalide: attendu ErrInvalidEnumValue, obtenu %v", err)
+		}
+	})
+}
+
+func TestUserRepositoryFindByEmailIsCaseInsensitive(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		user := createTestUser(t, db, "alice")
+
+		found, err := NewUserRepository(db).FindByEmail(t.Context(), "ALICE@Example.com")
+		if err != nil {
+			t.Fatalf("FindByEmail: %v", err)
+		}
+		if found.ID != user.ID {
+			t.Fatalf("mauvais utilisateur: %s", found.ID)
+		}
+	})
+}
+
+func TestUserRepositoryUpdateRole(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewUserRepository(db)
+		user := createTestUser(t, db, "alice")
+
+		if err := repo.UpdateRole(t.Context(), user.ID, models.RoleAdmin); err != nil {
+			t.Fatalf("UpdateRole: %v", err)
+		}
+		found, _ := repo.FindByID(t.Context(), user.ID)
+		if found.Role != models.RoleAdmin {
+			t.Fatalf("rôle non mis à jour: %q", found.Role)
+		}
+		if err := repo.UpdateRole(t.Context(), user.ID, "root"); !errors.Is(err, models.ErrInvalidEnumValue) {
+			t.Fatalf("rôle invalide: attendu ErrInvalidEnumValue, obtenu %v", err)
+		}
+		if err := repo.UpdateRole(t.Context(), uuid.New(), models.RoleAdmin); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("utilisateur inconnu: attendu ErrRecordNotFound, obtenu %v", err)
+		}
+	})
+}
+
+func TestUserRepositoryDeleteWithRelations(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, db *gorm.DB) {
+		repo := NewUserRepository(db)
+		user := createTestUser(t, db, "alice")
+		other := createTestUser(t, db, "bob")
+		ctx := t.Context()
+
+		notifications := NewNotificationRepository(db)
+		for _, owner := range []uuid.UUID{user.ID, other.ID} {
+			if err := notifications.CreateNotification(ctx, &models.Notification{UserID: owner, Type: models.NotificationTypeAlert, Message: "m"}); err != nil {
+				t.Fatalf("CreateNotification: %v", err)
+			}
+		}
+
+		if err := repo.DeleteWithRelations(t.Context(), user.ID); err != nil {
+			t.Fatalf("DeleteWithRelations: %v", err)
+		}
+		if _, err := repo.FindByID(t.Context(), user.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("utilisateur toujours présent: %v", err)
+		}
+		if remaining, _ := notifications.ListNotificationsByUser(ctx, user.ID, false); len(remaining) != 0 {
+			t.Fatalf("%d notification(s) orpheline(s)", len(remaining))
+		}
+		if kept, _ := notifications.ListNotificationsByUser(ctx, other.ID, false); len(kept) != 1 {
+			t.Fatalf("les données d'un autre utilisateur ont été supprimées")
+		}
+		if err := repo.DeleteWithRelations(t.Context(), user.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Fatalf("second appel: attendu ErrRecordNotFound, obtenu %v", err)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/Azertdev/FiberTest/internal/handlers"
+	"github.com/Azertdev/FiberTest/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupAlertRoutes(app *fiber.App, alertHandler handlers.AlertHandler, notificationHandler handlers.NotificationHandler, authMiddleware fiber.Handler) {
+	alertGroup := app.Group("/alerts", authMiddleware)
+	alertGroup.Post("/", alertHandler.CreateRule)
+	alertGroup.Get("/", alertHandler.ListRules)
+	alertGroup.Delete("/:id", alertHandler.DeleteRule)
+
+	notificationGroup := app.Group("/notifications", authMiddleware)
+	notificationGroup.Get("/", notificationHandler.ListNotifications)
+	notificationGroup.Patch("/:id/read", middleware.RequireOwnerOrAdmin("id", notificationHandler.NotificationOwner), notificationHandler.MarkAsRead)
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+)
+
+func TestMarkNotificationAsReadIsLimitedToItsOwnerOrAnAdmin(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, aliceToken := h.createVerifiedUser(t, "alice")
+	_, bobToken := h.createVerifiedUser(t, "bob")
+	_, adminToken := h.createAdmin(t, "root")
+	notifications := repositories.NewNotificationRepository(h.DB)
+	create := func() string {
+		t.Helper()
+		notification := &models.Notification{UserID: alice.ID, Type: models.NotificationTypeAlert, Message: "Sentiment en baisse"}
+		if err := notifications.CreateNotification(t.Context(), notification); err != nil {
+			t.Fatalf("création de la notification: %v", err)
+		}
+		return "/notifications/" + notification.ID.String() + "/read"
+	}
+
+	// Bob n'apprend pas l'existence de la notification d'Alice : 404 plutôt que 403
+	path := create()
+	expectStatus(t, h.send(t, http.MethodPatch, path, bobToken, nil), http.StatusNotFound)
+	var unread []models.Notification
+	decodeData(t, h.get(t, "/notifications/?unread=true", aliceToken), http.StatusOK, &unread)
+	if len(unread) != 1 {
+		t.Fatalf("%d notification(s) non lue(s) après le refus, attendu 1", len(unread))
+	}
+
+	expectStatus(t, h.send(t, http.MethodPatch, path, aliceToken, nil), http.StatusNoContent)
+	expectStatus(t, h.send(t, http.MethodPatch, create(), adminToken, nil), http.StatusNoContent)
+	decodeData(t, h.get(t, "/notifications/?unread=true", aliceToken), http.StatusOK, &unread)
+	if len(unread) != 0 {
+		t.Fatalf("%d notification(s) encore non lue(s)", len(unread))
+	}
+	expectStatus(t, h.send(t, http.MethodPatch, "/notifications/00000000-0000-0000-0000-000000000001/read", adminToken, nil), http.StatusNotFound)
+}
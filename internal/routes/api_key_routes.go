@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/Azertdev/FiberTest/internal/handlers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// La gestion des clés exige un JWT : une clé d'API ne peut pas en créer d'autres
+func SetupAPIKeyRoutes(app *fiber.App, apiKeyHandler handlers.APIKeyHandler, authMiddleware fiber.Handler) {
+	apiKeyGroup := app.Group("/api-keys", authMiddleware)
+	apiKeyGroup.Post("/", apiKeyHandler.CreateAPIKey)
+	apiKeyGroup.Get("/", apiKeyHandler.ListAPIKeys)
+	apiKeyGroup.Delete("/:id", apiKeyHandler.RevokeAPIKey)
+}
@@ -0,0 +1,97 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+)
+
+// expectStatus vérifie le statut et la forme {"status": "error", "message": ...} des refus
+func expectStatus(t *testing.T, response *http.Response, expected int) {
+	t.Helper()
+	defer response.Body.Close()
+	if response.StatusCode != expected {
+		t.Fatalf("statut %d, attendu %d", response.StatusCode, expected)
+	}
+	if expected < http.StatusBadRequest {
+		return
+	}
+	var body struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil || body.Status != "error" || body.Message == "" {
+		t.Fatalf("corps d'erreur inattendu: %v, %+v", err, body)
+	}
+}
+
+func (h *testHarness) createAPIKey(t *testing.T, user *models.User, scopes ...string) (*models.APIKey, string) {
+	t.Helper()
+	key, rawKey, err := h.Services.APIKeyService.CreateAPIKey(t.Context(), user.ID, models.CreateAPIKeyRequest{Name: "script", Scopes: scopes})
+	if err != nil {
+		t.Fatalf("création de la clé d'API: %v", err)
+	}
+	return key, rawKey
+}
+
+func TestAPIKeyAuthenticationAndScopes(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	user, accessToken := h.createVerifiedUser(t, "alice")
+	_, readKey := h.createAPIKey(t, user, models.APIKeyScopeReadInsights)
+
+	expectStatus(t, h.get(t, "/insights/", readKey), http.StatusOK)
+	// Scope absent : la clé ne lance pas d'analyse
+	expectStatus(t, h.get(t, "/comments/vid?max_results=5", readKey), http.StatusForbidden)
+	// Une clé inconnue n'est pas confiée au middleware JWT
+	expectStatus(t, h.get(t, "/insights/", readKey+"x"), http.StatusUnauthorized)
+
+	// Sans clé d'API, le JWT est vérifié
+	expectStatus(t, h.get(t, "/insights/", accessToken), http.StatusOK)
+	response := h.get(t, "/insights/", "pas-un-jwt")
+	response.Body.Close()
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("JWT invalide accepté: %d", response.StatusCode)
+	}
+}
+
+func TestAPIKeyRevokedOrExpiredIsRejected(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	user, _ := h.createVerifiedUser(t, "alice")
+
+	revoked, revokedKey := h.createAPIKey(t, user, models.APIKeyScopeReadInsights)
+	expectStatus(t, h.get(t, "/insights/", revokedKey), http.StatusOK)
+	if err := h.Services.APIKeyService.RevokeAPIKey(t.Context(), user.ID, revoked.ID); err != nil {
+		t.Fatalf("révocation: %v", err)
+	}
+	expectStatus(t, h.get(t, "/insights/", revokedKey), http.StatusUnauthorized)
+
+	expired, expiredKey := h.createAPIKey(t, user, models.APIKeyScopeReadInsights)
+	if err := h.DB.Model(&models.APIKey{}).Where("id = ?", expired.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("expiration: %v", err)
+	}
+	expectStatus(t, h.get(t, "/insights/", expiredKey), http.StatusUnauthorized)
+}
+
+func TestAPIKeyAdminScopeFollowsTheCurrentRole(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	admin, _ := h.createVerifiedUser(t, "root")
+	users := repositories.NewUserRepository(h.DB)
+	if err := users.UpdateRole(t.Context(), admin.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("promotion: %v", err)
+	}
+	_, adminKey := h.createAPIKey(t, admin, models.APIKeyScopeAdmin)
+	expectStatus(t, h.get(t, "/users/", adminKey), http.StatusOK)
+
+	// Rétrogradé : la requête suivante avec la même clé est refusée
+	if err := users.UpdateRole(t.Context(), admin.ID, models.RoleUser); err != nil {
+		t.Fatalf("rétrogradation: %v", err)
+	}
+	expectStatus(t, h.get(t, "/users/", adminKey), http.StatusForbidden)
+}
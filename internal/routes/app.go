@@ -0,0 +1,37 @@
+// internal/routes/app.go
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+	"github.com/gofiber/helmet/v2"
+
+	"github.com/Azertdev/FiberTest/internal/handlers"
+	"github.com/Azertdev/FiberTest/internal/middleware"
+	"github.com/Azertdev/FiberTest/internal/services"
+)
+
+// NewApp assemble l'application Fiber (middlewares globaux et routes) à partir des services
+// déjà construits : utilisé par main et par les tests de bout en bout.
+func NewApp(allServices *services.AllServices, allHandlers handlers.AllHandlers) *fiber.App {
+	app := fiber.New()
+
+	// Sondes avant les middlewares : pas de log à chaque appel de l'orchestrateur
+	SetupHealthRoutes(app, allHandlers.HealthHandler)
+	app.Use(cors.New())   // Autoriser les requêtes Cross-Origin (configurez selon vos besoins)
+	app.Use(logger.New()) // Logger les requêtes HTTP
+	app.Use(helmet.New())
+
+	authMiddleware := middleware.NewJWTMiddleware(allServices.TokenService)
+	// Routes scriptables : clé d'API (avec le scope requis) ou JWT
+	apiAuthMiddleware := middleware.NewAPIKeyMiddleware(allServices.APIKeyService, authMiddleware)
+	SetupJWKSRoutes(app, allHandlers.JWKSHandler)
+	SetupUserRoutes(app, allHandlers.UserHandler, authMiddleware, apiAuthMiddleware)
+	SetupCommentsRoutes(app, allHandlers.CommentHandler, apiAuthMiddleware, middleware.RequireVerifiedEmail(allServices.AccountService))
+	SetupInsightRoutes(app, allHandlers.InsightHandler, apiAuthMiddleware)
+	SetupAlertRoutes(app, allHandlers.AlertHandler, allHandlers.NotificationHandler, authMiddleware)
+	SetupOAuthRoutes(app, allHandlers.OAuthHandler, authMiddleware)
+	SetupAPIKeyRoutes(app, allHandlers.APIKeyHandler, authMiddleware)
+	return app
+}
@@ -3,11 +3,14 @@ package routes
 import (
 	"github.com/Azertdev/FiberTest/internal/handlers"
 	"github.com/Azertdev/FiberTest/internal/middleware"
+	"github.com/Azertdev/FiberTest/internal/models"
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupCommentsRoutes(app *fiber.App, commentsHandler handlers.CommentHandler) {
-	commentGroup := app.Group("/comments",middleware.JWTMiddleware)
+// verifiedEmail bloque le lancement d'analyses par les comptes dont l'email n'est pas confirmé.
+// apiAuthMiddleware accepte un JWT ou une clé d'API avec le scope analyses:run.
+func SetupCommentsRoutes(app *fiber.App, commentsHandler handlers.CommentHandler, apiAuthMiddleware, verifiedEmail fiber.Handler) {
+	commentGroup := app.Group("/comments",apiAuthMiddleware, middleware.RequireScope(models.APIKeyScopeRunAnalyses), verifiedEmail)
 	commentGroup.Get("/", commentsHandler.GetComments)
 	// userGroup.Get("/", userHandler.GetAllUsers)
 	// userGroup.Get("/:id", userHandler.GetUserByID)
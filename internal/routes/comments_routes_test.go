@@ -0,0 +1,491 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/adapters"
+	"github.com/Azertdev/FiberTest/internal/fakes"
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+type commentsResponse struct {
+	Status  string         `json:"status"`
+	Message string         `json:"message"`
+	Cached  bool           `json:"cached"`
+	Data    models.Insight `json:"data"`
+}
+
+func decodeComments(t *testing.T, response *http.Response, expectedStatus int) commentsResponse {
+	t.Helper()
+	defer response.Body.Close()
+	var body commentsResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatalf("réponse illisible (statut %d): %v", response.StatusCode, err)
+	}
+	if response.StatusCode != expectedStatus {
+		t.Fatalf("statut %d (%s), attendu %d", response.StatusCode, body.Message, expectedStatus)
+	}
+	return body
+}
+
+// Commentaires neutres pour le lexique : "vidéo" revient presque partout, "son" dans un tiers
+var videoCommentContents = []string{
+	"Le son de cette vidéo sature",
+	"Vidéo regardée en entier",
+	"Nouvelle vidéo, même souci de son",
+	"Vidéo partagée",
+	"Vidéo ajoutée à ma playlist",
+	"Le son coupe à 3:00 dans la vidéo",
+	"Vidéo visionnée deux fois",
+	"Le son baisse vers la fin de la vidéo",
+}
+
+func videoComments(count int) []models.Comment {
+	comments := make([]models.Comment, count)
+	for i := range comments {
+		comments[i] = models.Comment{
+			ChannelID: "UC1",
+			Content:   videoCommentContents[i%len(videoCommentContents)],
+			Author:    fmt.Sprintf("auteur%d", i+1),
+			Date:      time.Date(2026, 3, 1, 10, i, 0, 0, time.UTC),
+		}
+	}
+	// Une question sans réponse et une question déjà traitée
+	comments[1].Content = "Quand sort la suite ?"
+	comments[4].Content = "Quel micro utilises-tu ?"
+	comments[4].ReplyCount = 2
+	return comments
+}
+
+func jsonList(t *testing.T, raw []byte) []string {
+	t.Helper()
+	var items []string
+	if err := json.Unmarshal(raw, &items); err != nil {
+		t.Fatalf("liste JSON invalide %s: %v", raw, err)
+	}
+	return items
+}
+
+func TestGetCommentsAnalyzesInChunksAndPersists(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	user, token := h.createVerifiedUser(t, "alice")
+	h.YouTube.SetComments("v1", videoComments(8))
+	h.Transcripts.Set("v1", "transcription de la vidéo")
+	h.Groq.SetSummary("La vidéo présente un micro.", nil)
+
+	// 8 commentaires en lots de 3 : le deuxième lot échoue, les deux autres sont fusionnés
+	h.Groq.OnAnalyze(func(call int, comments []string) (string, error) {
+		switch call {
+		case 2:
+			return "", errors.New("limite de tokens atteinte")
+		case 1:
+			return fakes.InsightMarkdown(utils.ParsedInsight{
+				Sentiment:        "Positif",
+				Summary:          "Les spectateurs apprécient le son.",
+				QuestionComments: []string{"Quand sort la suite ?"},
+				NegativeComments: []string{"auteur3: image floue"},
+				Keywords:         []string{"Son", "micro"},
+			}), nil
+		default:
+			return fakes.InsightMarkdown(utils.ParsedInsight{
+				Sentiment:        "Positif",
+				Summary:          "Demandes de suite.",
+				NegativeComments: []string{"auteur3: image floue", "auteur8: trop long"},
+				Keywords:         []string{"son"},
+			}), nil
+		}
+	})
+
+	body := decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusCreated)
+	if body.Cached {
+		t.Fatal("première analyse servie depuis le cache")
+	}
+
+	// Découpage
+	calls := h.Groq.AnalyzeCalls()
+	sizes := make([]int, len(calls))
+	for i, call := range calls {
+		sizes[i] = len(call)
+	}
+	if !slices.Equal(sizes, []int{3, 3, 2}) {
+		t.Fatalf("lots envoyés: %v, attendu [3 3 2]", sizes)
+	}
+	if !strings.Contains(calls[0][1], `Auteur: auteur2 | Date: 2026-03-01 | Commentaire: "Quand sort la suite ?"`) {
+		t.Fatalf("commentaire mal formaté: %q", calls[0][1])
+	}
+
+	// Fusion des lots réussis
+	insight := body.Data
+	if insight.Sentiment != "Positif" || insight.Summary != "Les spectateurs apprécient le son." {
+		t.Fatalf("fusion inattendue: sentiment %q, résumé %q", insight.Sentiment, insight.Summary)
+	}
+	// Mots-clés du modèle ("Son" et "son" regroupés) et extraits des commentaires : "vidéo", citée
+	// par 6 commentaires sur 8, passe derrière "son" (4 sur 8)
+	if keywords := jsonList(t, insight.Keywords); !slices.Equal(keywords, []string{"son", "vidéo", "micro"}) {
+		t.Fatalf("mots-clés: %v", keywords)
+	}
+	var frequencies []models.KeywordFrequency
+	if err := json.Unmarshal(insight.KeywordFrequencies, &frequencies); err != nil || !slices.Equal(frequencies, []models.KeywordFrequency{{Keyword: "son", Frequency: 4}, {Keyword: "vidéo", Frequency: 6}, {Keyword: "micro", Frequency: 1}}) {
+		t.Fatalf("fréquences des mots-clés: %+v (%v)", frequencies, err)
+	}
+	if negatives := jsonList(t, insight.NegativeComments); len(negatives) != 2 || insight.NegativeCount != 2 {
+		t.Fatalf("critiques dédoublonnées: %v (compte %d)", negatives, insight.NegativeCount)
+	}
+	if insight.TranscriptSummary != "La vidéo présente un micro." || insight.CommentCount != 8 || insight.UnansweredQuestions != 1 || insight.ChannelID != "UC1" {
+		t.Fatalf("insight inattendu: %+v", insight)
+	}
+
+	// Persistance : l'insight et les commentaires analysés
+	var stored models.Insight
+	if err := h.DB.Where("id = ?", insight.ID).First(&stored).Error; err != nil {
+		t.Fatalf("insight non enregistré: %v", err)
+	}
+	if stored.UserID != user.ID || stored.Version != 1 {
+		t.Fatalf("insight enregistré inattendu: utilisateur %s, version %d", stored.UserID, stored.Version)
+	}
+	var savedComments []models.Comment
+	h.DB.Where("user_id = ? AND video_id = ?", user.ID, "v1").Order("date").Find(&savedComments)
+	if len(savedComments) != 8 || savedComments[1].Content != "Quand sort la suite ?" {
+		t.Fatalf("%d commentaire(s) enregistré(s)", len(savedComments))
+	}
+}
+
+func TestGetCommentsBuildsGlobalSummaryFromEveryChunk(t *testing.T) {
+	t.Parallel()
+	options := testAnalysisOptions
+	options.MetaSummary = true
+	h := newTestHarnessWithOptions(t, options)
+	_, token := h.createVerifiedUser(t, "alice")
+	h.YouTube.SetComments("v1", videoComments(7))
+	h.Groq.OnAnalyze(func(call int, comments []string) (string, error) {
+		sentiment := "Positif"
+		if call == 3 {
+			sentiment = "Négatif"
+		}
+		return fakes.InsightMarkdown(utils.ParsedInsight{
+			Sentiment: sentiment,
+			Summary:   fmt.Sprintf("Résumé du lot %d.", call),
+			Keywords:  []string{"son"},
+		}), nil
+	})
+	h.Groq.OnMetaSummary(func(input utils.MetaSummaryInput) (string, error) {
+		return "## 1. Sentiment Général\nMajoritairement positif\n\n## 2. Résumé Général des Commentaires\nSynthèse des trois lots.\n", nil
+	})
+
+	insight := decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusCreated).Data
+	if insight.Sentiment != "Majoritairement positif" || insight.Summary != "Synthèse des trois lots." {
+		t.Fatalf("synthèse globale non appliquée: sentiment %q, résumé %q", insight.Sentiment, insight.Summary)
+	}
+
+	calls := h.Groq.MetaSummaryCalls()
+	if len(calls) != 1 {
+		t.Fatalf("%d synthèse(s) globale(s), attendu 1", len(calls))
+	}
+	input := calls[0]
+	if input.CommentCount != 7 || !slices.Equal(input.ChunkSummaries, []string{"Résumé du lot 1.", "Résumé du lot 2.", "Résumé du lot 3."}) || !slices.Equal(input.TopKeywords, []string{"son", "vidéo"}) {
+		t.Fatalf("entrée de synthèse inattendue: %+v", input)
+	}
+	if !slices.Equal(input.SentimentCounts, []utils.SentimentCount{{Sentiment: "Positif", Chunks: 2}, {Sentiment: "Négatif", Chunks: 1}}) {
+		t.Fatalf("répartition des sentiments: %+v", input.SentimentCounts)
+	}
+}
+
+func TestGetCommentsKeepsHeuristicSummaryWhenGlobalSummaryFails(t *testing.T) {
+	t.Parallel()
+	options := testAnalysisOptions
+	options.MetaSummary = true
+	h := newTestHarnessWithOptions(t, options)
+	_, token := h.createVerifiedUser(t, "alice")
+	h.YouTube.SetComments("v1", videoComments(6))
+
+	failures := []struct {
+		name  string
+		reply fakes.MetaSummaryFunc
+	}{
+		{"erreur du modèle", func(utils.MetaSummaryInput) (string, error) { return "", errors.New("service indisponible") }},
+		{"réponse inexploitable", func(utils.MetaSummaryInput) (string, error) { return "Je ne peux pas répondre.", nil }},
+	}
+	for _, failure := range failures {
+		h.Groq.OnMetaSummary(failure.reply)
+		firstChunk := len(h.Groq.AnalyzeCalls()) + 1
+		insight := decodeComments(t, h.get(t, "/comments?video_id=v1&force=true", token), http.StatusCreated).Data
+		// Heuristique : résumé du premier lot de l'analyse
+		if insight.Sentiment != "Positif" || insight.Summary != fmt.Sprintf("Lot %d : 3 commentaires analysés.", firstChunk) {
+			t.Fatalf("%s : résumé heuristique non conservé: sentiment %q, résumé %q", failure.name, insight.Sentiment, insight.Summary)
+		}
+	}
+}
+
+func TestGetCommentsScoresSentimentAndFiresScoreAlert(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	user, token := h.createVerifiedUser(t, "alice")
+	comments := videoComments(5)
+	comments[2].LikeCount = 50
+	h.YouTube.SetComments("v1", comments)
+	rule := &models.AlertRule{Type: models.AlertRuleSentimentScore, Threshold: 0, Enabled: true}
+	if err := h.Services.AlertService.CreateRule(t.Context(), user.ID, rule); err != nil {
+		t.Fatalf("création de la règle: %v", err)
+	}
+
+	// Lot 1 : neutre, non noté, négatif (très aimé) ; lot 2 : positif, non noté
+	h.Groq.OnAnalyze(func(call int, batch []string) (string, error) {
+		scores := map[int]utils.CommentScore{1: utils.NewCommentScore(0.6, "")}
+		if call == 1 {
+			scores = map[int]utils.CommentScore{
+				1: utils.NewCommentScore(0, ""),
+				3: utils.NewCommentScore(-0.8, ""),
+				9: utils.NewCommentScore(1, ""), // numéro hors du lot : ignoré
+			}
+		}
+		return fakes.InsightMarkdown(utils.ParsedInsight{Sentiment: "Partagé", Summary: "Avis partagés.", CommentScores: scores}), nil
+	})
+
+	insight := decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusCreated).Data
+	stats := insight.SentimentStats
+	if stats.Scored != 3 || stats.Positive != 1 || stats.Neutral != 1 || stats.Negative != 1 || stats.Mixed != 0 {
+		t.Fatalf("répartition inattendue: %+v", stats)
+	}
+	// Le commentaire négatif pèse 1 + ln(51) ≈ 4,9 fois plus que les autres
+	if stats.Score >= 0 || stats.Confidence <= 0 || stats.Confidence >= 1 {
+		t.Fatalf("score %v, confiance %v", stats.Score, stats.Confidence)
+	}
+
+	var stored models.Insight
+	if err := h.DB.Where("id = ?", insight.ID).First(&stored).Error; err != nil || stored.SentimentStats != stats {
+		t.Fatalf("statistiques non enregistrées: %+v (%v)", stored.SentimentStats, err)
+	}
+	var negative models.Comment
+	if err := h.DB.Where("video_id = ? AND author = ?", "v1", "auteur3").First(&negative).Error; err != nil {
+		t.Fatalf("commentaire non enregistré: %v", err)
+	}
+	if negative.Polarity == nil || *negative.Polarity != -0.8 || negative.SentimentLabel != models.SentimentNegative || negative.LikeCount != 50 {
+		t.Fatalf("polarité du commentaire non enregistrée: %+v", negative)
+	}
+	var unscored int64
+	h.DB.Model(&models.Comment{}).Where("video_id = ? AND polarity IS NULL", "v1").Count(&unscored)
+	if unscored != 2 {
+		t.Fatalf("%d commentaire(s) sans polarité, attendu 2", unscored)
+	}
+
+	var alerts int64
+	h.DB.Model(&models.Notification{}).Where("user_id = ? AND type = ?", user.ID, models.NotificationTypeAlert).Count(&alerts)
+	if alerts != 1 {
+		t.Fatalf("%d alerte(s) de score, attendu 1", alerts)
+	}
+}
+
+func TestGetCommentsServesCacheThenReanalyzesOnForce(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	user, token := h.createVerifiedUser(t, "alice")
+	h.YouTube.SetComments("v1", videoComments(6))
+	// Pas de transcription : l'analyse se poursuit sans contexte
+
+	first := decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusCreated)
+	if first.Data.TranscriptSummary == "" || h.Groq.SummaryCalls() != 0 {
+		t.Fatalf("transcription absente mal gérée: %q, %d résumé(s)", first.Data.TranscriptSummary, h.Groq.SummaryCalls())
+	}
+	analyzed := len(h.Groq.AnalyzeCalls())
+
+	cached := decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusOK)
+	if !cached.Cached || cached.Data.ID != first.Data.ID || len(h.Groq.AnalyzeCalls()) != analyzed {
+		t.Fatal("insight récent non servi depuis le cache")
+	}
+
+	h.YouTube.SetComments("v1", videoComments(7))
+	forced := decodeComments(t, h.get(t, "/comments?video_id=v1&force=true", token), http.StatusCreated)
+	if forced.Cached || forced.Data.Version != 2 || forced.Data.CommentCount != 7 {
+		t.Fatalf("nouvelle analyse inattendue: version %d, %d commentaire(s)", forced.Data.Version, forced.Data.CommentCount)
+	}
+	// Les commentaires enregistrés sont ceux de la dernière analyse
+	var count int64
+	h.DB.Model(&models.Comment{}).Where("user_id = ? AND video_id = ?", user.ID, "v1").Count(&count)
+	if count != 7 {
+		t.Fatalf("%d commentaire(s) enregistré(s), attendu 7", count)
+	}
+}
+
+func TestGetCommentsAnswersNotModifiedForTheCachedInsight(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	_, token := h.createVerifiedUser(t, "alice")
+	h.YouTube.SetComments("v1", videoComments(5))
+
+	response := h.get(t, "/comments?video_id=v1", token)
+	etag := response.Header.Get("ETag")
+	first := decodeComments(t, response, http.StatusCreated)
+	if etag != `"`+first.Data.ID.String()+`"` {
+		t.Fatalf("ETag %q pour l'insight %s", etag, first.Data.ID)
+	}
+
+	conditional := func(path string) *http.Response {
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Header.Set("If-None-Match", etag)
+		return h.do(t, request, token)
+	}
+	if response := conditional("/comments?video_id=v1"); response.StatusCode != http.StatusNotModified {
+		t.Fatalf("insight en cache inchangé: statut %d, attendu 304", response.StatusCode)
+	}
+	// Nouvelle analyse : nouvel insight, donc réponse complète malgré l'ancien ETag
+	forced := decodeComments(t, conditional("/comments?video_id=v1&force=true"), http.StatusCreated)
+	if forced.Data.ID == first.Data.ID {
+		t.Fatal("analyse forcée servie depuis le cache")
+	}
+}
+
+func TestGetCommentsFailsWithoutPersistingWhenEveryChunkFails(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	_, token := h.createVerifiedUser(t, "alice")
+	h.YouTube.SetComments("v1", videoComments(7))
+	h.Groq.OnAnalyze(func(call int, comments []string) (string, error) {
+		return "", errors.New("service indisponible")
+	})
+
+	decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusInternalServerError)
+	if calls := len(h.Groq.AnalyzeCalls()); calls != 3 {
+		t.Fatalf("%d lot(s) tenté(s), attendu 3", calls)
+	}
+	var insights, comments int64
+	h.DB.Model(&models.Insight{}).Count(&insights)
+	h.DB.Model(&models.Comment{}).Count(&comments)
+	if insights != 0 || comments != 0 {
+		t.Fatalf("données enregistrées malgré l'échec: %d insight(s), %d commentaire(s)", insights, comments)
+	}
+}
+
+func TestGetCommentsFallsBackToOfflineAnalysisWhenGroqFails(t *testing.T) {
+	t.Parallel()
+	options := testAnalysisOptions
+	options.OfflineFallback = true
+	h := newTestHarnessWithOptions(t, options)
+	_, token := h.createVerifiedUser(t, "alice")
+	comments := videoComments(7)
+	comments[0].Content = "Super vidéo, merci beaucoup !"
+	comments[3].Content = "Le son est horrible, vraiment nul."
+	h.YouTube.SetComments("v1", comments)
+	h.Groq.OnAnalyze(func(call int, comments []string) (string, error) {
+		return "", errors.New("service indisponible")
+	})
+
+	insight := decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusCreated).Data
+	if insight.AnalysisEngine != models.AnalysisEngineLexicon || len(h.Groq.AnalyzeCalls()) != 3 {
+		t.Fatalf("moteur %q après %d appel(s) Groq", insight.AnalysisEngine, len(h.Groq.AnalyzeCalls()))
+	}
+	if stats := insight.SentimentStats; stats.Scored != 7 || stats.Positive != 1 || stats.Negative != 1 {
+		t.Fatalf("polarités hors ligne inattendues: %+v", stats)
+	}
+	if !slices.Contains(jsonList(t, insight.NegativeComments), "auteur4: Le son est horrible, vraiment nul.") {
+		t.Fatalf("critique non classée: %s", insight.NegativeComments)
+	}
+
+	// Un seul lot en échec : les autres restent analysés par le modèle
+	failing := len(h.Groq.AnalyzeCalls()) + 2
+	h.Groq.OnAnalyze(func(call int, comments []string) (string, error) {
+		if call == failing {
+			return "", errors.New("service indisponible")
+		}
+		return fakes.InsightMarkdown(utils.ParsedInsight{Sentiment: "Positif", Summary: "Lot analysé.", TopComments: comments}), nil
+	})
+	mixed := decodeComments(t, h.get(t, "/comments?video_id=v1&force=true", token), http.StatusCreated).Data
+	if mixed.AnalysisEngine != models.AnalysisEngineMixed {
+		t.Fatalf("moteur %q, attendu %q", mixed.AnalysisEngine, models.AnalysisEngineMixed)
+	}
+	var stored models.Insight
+	if err := h.DB.Where("id = ?", mixed.ID).First(&stored).Error; err != nil || stored.AnalysisEngine != models.AnalysisEngineMixed {
+		t.Fatalf("moteur non enregistré: %q (%v)", stored.AnalysisEngine, err)
+	}
+}
+
+func TestGetCommentsSendsRemainingChunksOfflineOnceGroqTimesOut(t *testing.T) {
+	t.Parallel()
+	// Vrai adapter sur l'API simulée : le premier lot est analysé, puis l'API ne répond plus
+	api := fakes.NewGroqAPI(t)
+	api.OnRequest(func(call int, request fakes.GroqRequest) (int, string) {
+		api.Hang()
+		return http.StatusOK, fakes.InsightMarkdown(utils.ParsedInsight{Sentiment: "Positif", Summary: "Lot analysé.", TopComments: []string{"commentaire 1"}})
+	})
+	groqOptions := adapters.DefaultGroqOptions
+	groqOptions.BaseURL = api.URL()
+	groqOptions.Timeout = 200 * time.Millisecond
+
+	options := testAnalysisOptions
+	options.OfflineFallback = true
+	options.MetaSummary = true
+	h := newTestHarnessWithGroq(t, options, adapters.NewGroqAdapter(fakes.GroqAPIKey, groqOptions))
+	_, token := h.createVerifiedUser(t, "alice")
+	h.YouTube.SetComments("v1", videoComments(7))
+
+	started := time.Now()
+	insight := decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusCreated).Data
+	// Un seul timeout : le lot 3 et la synthèse globale ne sont plus envoyés à Groq
+	if requests := len(api.Requests()); requests != 2 {
+		t.Fatalf("%d requête(s) envoyée(s) à Groq, attendu 2 (lot 1, lot 2 sans réponse)", requests)
+	}
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Fatalf("analyse en %s : les lots restants ont attendu Groq", elapsed)
+	}
+	if insight.AnalysisEngine != models.AnalysisEngineMixed {
+		t.Fatalf("moteur %q, attendu %q", insight.AnalysisEngine, models.AnalysisEngineMixed)
+	}
+}
+
+func TestGetCommentsAnalyzesOfflineForPlansWithoutAI(t *testing.T) {
+	t.Parallel()
+	options := testAnalysisOptions
+	options.MetaSummary = true
+	options.OfflinePlans = []string{models.PlanFree}
+	h := newTestHarnessWithOptions(t, options)
+	user, token := h.createVerifiedUser(t, "alice")
+	h.YouTube.SetComments("v1", videoComments(7))
+	h.Transcripts.Set("v1", "Bonjour à tous.")
+
+	// Sans abonnement : plan gratuit, aucun appel au modèle
+	insight := decodeComments(t, h.get(t, "/comments?video_id=v1", token), http.StatusCreated).Data
+	if insight.AnalysisEngine != models.AnalysisEngineLexicon || insight.TranscriptSummary != adapters.OfflineTranscriptSummary {
+		t.Fatalf("moteur %q, résumé de transcription %q", insight.AnalysisEngine, insight.TranscriptSummary)
+	}
+	if len(h.Groq.AnalyzeCalls()) != 0 || h.Groq.SummaryCalls() != 0 || len(h.Groq.MetaSummaryCalls()) != 0 {
+		t.Fatal("le modèle a été appelé pour un plan sans analyse IA")
+	}
+	if !strings.Contains(insight.Summary, "hors ligne") || insight.SentimentStats.Scored != 7 {
+		t.Fatalf("synthèse hors ligne inattendue: %q, %+v", insight.Summary, insight.SentimentStats)
+	}
+
+	subscription := &models.Subscription{UserID: user.ID, Plan: models.PlanPro}
+	if err := repositories.NewSubscriptionRepository(h.DB).CreateSubscription(t.Context(), subscription); err != nil {
+		t.Fatalf("création de l'abonnement: %v", err)
+	}
+	upgraded := decodeComments(t, h.get(t, "/comments?video_id=v1&force=true", token), http.StatusCreated).Data
+	if upgraded.AnalysisEngine != models.AnalysisEngineLLM || len(h.Groq.AnalyzeCalls()) != 3 {
+		t.Fatalf("plan pro : moteur %q, %d appel(s) Groq", upgraded.AnalysisEngine, len(h.Groq.AnalyzeCalls()))
+	}
+}
+
+func TestGetCommentsRequiresVideoIDAndAuthentication(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	_, token := h.createVerifiedUser(t, "alice")
+
+	decodeComments(t, h.get(t, "/comments", token), http.StatusBadRequest)
+	if response := h.get(t, "/comments?video_id=v1", "token-invalide"); response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("token invalide: statut %d", response.StatusCode)
+	}
+	if calls := h.YouTube.Calls(); len(calls) != 0 {
+		t.Fatalf("YouTube appelé sans requête valide: %v", calls)
+	}
+}
@@ -0,0 +1,189 @@
+package routes
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Azertdev/FiberTest/internal/adapters"
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/fakes"
+	"github.com/Azertdev/FiberTest/internal/handlers"
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/services"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// testHarness est l'application complète (routes, middlewares, services, repositories) sur une
+// base SQLite jetable, avec YouTube, Groq, les transcriptions et l'envoi d'emails remplacés par des fakes
+type testHarness struct {
+	App         *fiber.App
+	DB          *gorm.DB
+	Services    *services.AllServices
+	Keys        *utils.KeySet // clés de signature des tokens d'accès
+	YouTube     *fakes.YouTube
+	Groq        *fakes.Groq
+	Transcripts *fakes.Transcripts
+	Mailer      *fakes.Mailer
+}
+
+// testAnalysisOptions : petits lots et aucune pause, pour exercer le découpage sans ralentir les tests
+var testAnalysisOptions = services.AnalysisOptions{
+	MaxComments:   100,
+	ChunkSize:     3,
+	DefaultMaxAge: time.Hour,
+	Merge:         utils.DefaultMergeLimits,
+}
+
+func newTestHarness(t *testing.T) *testHarness {
+	t.Helper()
+	return newTestHarnessWithOptions(t, testAnalysisOptions)
+}
+
+// newTestHarnessWithOptions construit le harnais avec des options d'analyse spécifiques
+func newTestHarnessWithOptions(t *testing.T, analysis services.AnalysisOptions) *testHarness {
+	t.Helper()
+	return newTestHarnessWithGroq(t, analysis, nil)
+}
+
+// newTestHarnessWithGroq remplace le fake Groq en mémoire par groq (nil : fake en mémoire),
+// par exemple le vrai adapter branché sur fakes.GroqAPI
+func newTestHarnessWithGroq(t *testing.T, analysis services.AnalysisOptions, groq services.GroqAdapter) *testHarness {
+	t.Helper()
+	db, err := database.Open(database.DriverSQLite, filepath.Join(t.TempDir(), "e2e.db"))
+	if err != nil {
+		t.Fatalf("ouverture de la base: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if _, err := database.Migrate(context.Background(), db); err != nil {
+		t.Fatalf("migration: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+
+	keys, err := utils.NewKeySet("test", []*utils.SigningKey{utils.NewHMACKey("test", []byte("secret-de-test-de-plus-de-32-octets"))})
+	if err != nil {
+		t.Fatalf("clés JWT: %v", err)
+	}
+
+	h := &testHarness{
+		DB:          db,
+		Keys:        keys,
+		YouTube:     fakes.NewYouTube(),
+		Groq:        fakes.NewGroq(),
+		Transcripts: fakes.NewTranscripts(),
+		Mailer:      fakes.NewMailer(),
+	}
+	if groq == nil {
+		groq = h.Groq
+	}
+	h.Services = services.NewAllServices(
+		repositories.NewAllRepository(db),
+		h.YouTube,
+		groq,
+		adapters.NewLexiconAdapter(),
+		h.Transcripts,
+		nil,
+		h.Mailer,
+		nil,
+		nil,
+		keys,
+		services.ServiceOptions{AppBaseURL: "http://localhost:3000", Analysis: analysis, LoginGuard: services.DefaultLoginGuardPolicy},
+	)
+	h.App = NewApp(h.Services, handlers.NewAllHandlers(h.Services))
+	return h
+}
+
+// createVerifiedUser crée un compte à l'adresse confirmée et retourne un token d'accès
+func (h *testHarness) createVerifiedUser(t *testing.T, username string) (*models.User, string) {
+	t.Helper()
+	verifiedAt := time.Now()
+	user := &models.User{Username: username, Email: username + "@example.com", Password: "not-a-hash", EmailVerifiedAt: &verifiedAt}
+	if err := repositories.NewUserRepository(h.DB).Create(t.Context(), user); err != nil {
+		t.Fatalf("création de l'utilisateur: %v", err)
+	}
+	return user, h.issueTokens(t, user).AccessToken
+}
+
+// issueTokens ouvre une session (token d'accès et refresh token), comme une connexion
+func (h *testHarness) issueTokens(t *testing.T, user *models.User) *services.TokenPair {
+	t.Helper()
+	tokens, err := h.Services.TokenService.IssueTokens(t.Context(), user)
+	if err != nil {
+		t.Fatalf("émission des tokens: %v", err)
+	}
+	return tokens
+}
+
+// do exécute la requête sur l'application, sans délai maximal ; accessToken vide : requête anonyme
+func (h *testHarness) do(t *testing.T, request *http.Request, accessToken string) *http.Response {
+	t.Helper()
+	if accessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+accessToken)
+	}
+	response, err := h.App.Test(request, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", request.Method, request.URL, err)
+	}
+	return response
+}
+
+// get exécute une requête authentifiée sur l'application, sans délai maximal
+func (h *testHarness) get(t *testing.T, path, accessToken string) *http.Response {
+	t.Helper()
+	return h.send(t, http.MethodGet, path, accessToken, nil)
+}
+
+// send exécute une requête authentifiée avec body encodé en JSON (nil : corps vide)
+func (h *testHarness) send(t *testing.T, method, path, accessToken string, body any) *http.Response {
+	t.Helper()
+	payload := ""
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("corps de %s %s: %v", method, path, err)
+		}
+		payload = string(encoded)
+	}
+	request, err := http.NewRequest(method, path, strings.NewReader(payload))
+	if err != nil {
+		t.Fatalf("requête %s: %v", path, err)
+	}
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	return h.do(t, request, accessToken)
+}
+
+// decodeData vérifie le statut de la réponse et décode son champ "data" dans data (nil : ignoré)
+func decodeData(t *testing.T, response *http.Response, expectedStatus int, data any) {
+	t.Helper()
+	defer response.Body.Close()
+	var body struct {
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		t.Fatalf("réponse illisible (statut %d): %v", response.StatusCode, err)
+	}
+	if response.StatusCode != expectedStatus {
+		t.Fatalf("statut %d (%s), attendu %d", response.StatusCode, body.Message, expectedStatus)
+	}
+	if data != nil {
+		if err := json.Unmarshal(body.Data, data); err != nil {
+			t.Fatalf("données illisibles %s: %v", body.Data, err)
+		}
+	}
+}
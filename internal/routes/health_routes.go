@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/Azertdev/FiberTest/internal/handlers"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Sondes publiques (orchestrateur, load balancer) : à enregistrer avant les middlewares de logs
+func SetupHealthRoutes(app *fiber.App, healthHandler handlers.HealthHandler) {
+	app.Get("/healthz", healthHandler.Liveness)
+	app.Get("/readyz", healthHandler.Readiness)
+}
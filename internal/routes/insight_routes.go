@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/Azertdev/FiberTest/internal/handlers"
+	"github.com/Azertdev/FiberTest/internal/middleware"
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/gofiber/fiber/v2"
+)
+
+// apiAuthMiddleware accepte un JWT ou une clé d'API : la lecture exige le scope insights:read,
+// la suppression reste réservée aux JWT (aucun scope ne l'autorise)
+func SetupInsightRoutes(app *fiber.App, insightHandler handlers.InsightHandler, apiAuthMiddleware fiber.Handler) {
+	insightGroup := app.Group("/insights", apiAuthMiddleware)
+	readInsights := middleware.RequireScope(models.APIKeyScopeReadInsights)
+	insightGroup.Get("/", readInsights, insightHandler.ListInsights)
+	insightGroup.Get("/export", readInsights, insightHandler.ExportInsights)
+	insightGroup.Get("/video/:videoId", readInsights, insightHandler.GetVideoHistory)
+	insightGroup.Get("/video/:videoId/diff", readInsights, insightHandler.DiffVersions)
+
+	// Accès à un insight précis : propriétaire ou admin
+	ownerOrAdmin := middleware.RequireOwnerOrAdmin("id", insightHandler.InsightOwner)
+	insightGroup.Get("/:id", readInsights, ownerOrAdmin, insightHandler.GetInsight)
+	insightGroup.Get("/:id/export", readInsights, ownerOrAdmin, insightHandler.ExportInsight)
+	insightGroup.Delete("/:id", middleware.RequireJWT(), ownerOrAdmin, insightHandler.DeleteInsight)
+}
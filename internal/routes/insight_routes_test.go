@@ -0,0 +1,319 @@
+package routes
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/services"
+)
+
+// createInsight enregistre un insight (numéroté par le repository) sans passer par l'analyse
+func (h *testHarness) createInsight(t *testing.T, insight *models.Insight) *models.Insight {
+	t.Helper()
+	if err := repositories.NewInsightRepository(h.DB).CreateInsight(t.Context(), insight); err != nil {
+		t.Fatalf("création de l'insight: %v", err)
+	}
+	return insight
+}
+
+func jsonStrings(t *testing.T, items ...string) datatypes.JSON {
+	t.Helper()
+	raw, err := json.Marshal(items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+type insightPage struct {
+	Data       []models.Insight `json:"data"`
+	Pagination struct {
+		Page     int   `json:"page"`
+		PageSize int   `json:"page_size"`
+		Total    int64 `json:"total"`
+	} `json:"pagination"`
+}
+
+func (h *testHarness) listInsights(t *testing.T, query, accessToken string) insightPage {
+	t.Helper()
+	response := h.get(t, "/insights/"+query, accessToken)
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("GET /insights/%s: statut %d", query, response.StatusCode)
+	}
+	var page insightPage
+	if err := json.NewDecoder(response.Body).Decode(&page); err != nil {
+		t.Fatalf("page illisible: %v", err)
+	}
+	return page
+}
+
+func insightVideos(insights []models.Insight) []string {
+	videos := make([]string, len(insights))
+	for i, insight := range insights {
+		videos[i] = insight.VideoID
+	}
+	return videos
+}
+
+func TestListInsightsFiltersSortsAndPaginates(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, token := h.createVerifiedUser(t, "alice")
+	bob, _ := h.createVerifiedUser(t, "bob")
+	day := func(month time.Month) time.Time { return time.Date(2026, month, 1, 12, 0, 0, 0, time.UTC) }
+	h.createInsight(t, &models.Insight{UserID: alice.ID, VideoID: "v3", CreatedAt: day(1)})
+	h.createInsight(t, &models.Insight{UserID: alice.ID, VideoID: "v1", CreatedAt: day(2)})
+	h.createInsight(t, &models.Insight{UserID: alice.ID, VideoID: "v2", CreatedAt: day(3)})
+	h.createInsight(t, &models.Insight{UserID: alice.ID, VideoID: "v1", CreatedAt: day(4)})
+	h.createInsight(t, &models.Insight{UserID: bob.ID, VideoID: "v1", CreatedAt: day(5)})
+
+	// Par défaut : les insights de l'utilisateur courant, du plus récent au plus ancien
+	all := h.listInsights(t, "", token)
+	if all.Pagination.Total != 4 || !slices.Equal(insightVideos(all.Data), []string{"v1", "v2", "v1", "v3"}) {
+		t.Fatalf("liste par défaut: %d au total, %v", all.Pagination.Total, insightVideos(all.Data))
+	}
+	if all.Pagination.Page != 1 || all.Pagination.PageSize != services.DefaultInsightPageSize {
+		t.Fatalf("pagination par défaut: %+v", all.Pagination)
+	}
+
+	cases := []struct {
+		query  string
+		total  int64
+		videos []string
+	}{
+		{"?video_id=v1", 2, []string{"v1", "v1"}},
+		// Une date simple en borne supérieure couvre toute la journée
+		{"?from=2026-02-01&to=2026-03-01", 2, []string{"v2", "v1"}},
+		{"?from=2026-03-01T00:00:00Z", 2, []string{"v1", "v2"}},
+		{"?sort=video_id&order=asc", 4, []string{"v1", "v1", "v2", "v3"}},
+		{"?order=asc&page=2&page_size=3", 4, []string{"v1"}},
+		{"?page=3&page_size=3", 4, []string{}},
+	}
+	for _, tc := range cases {
+		page := h.listInsights(t, tc.query, token)
+		if page.Pagination.Total != tc.total || !slices.Equal(insightVideos(page.Data), tc.videos) {
+			t.Errorf("%s: %d au total, %v ; attendu %d, %v", tc.query, page.Pagination.Total, insightVideos(page.Data), tc.total, tc.videos)
+		}
+	}
+
+	// Bornes de la pagination
+	if page := h.listInsights(t, "?page_size=1000", token); page.Pagination.PageSize != services.MaxInsightPageSize {
+		t.Fatalf("page_size non plafonnée: %d", page.Pagination.PageSize)
+	}
+	if page := h.listInsights(t, "?page=-2&page_size=0", token); page.Pagination.Page != 1 || page.Pagination.PageSize != services.DefaultInsightPageSize || len(page.Data) != 4 {
+		t.Fatalf("pagination invalide non corrigée: %+v, %d insight(s)", page.Pagination, len(page.Data))
+	}
+	for _, query := range []string{"?sort=summary", "?order=up", "?from=hier", "?to=2026-13-01"} {
+		expectStatus(t, h.get(t, "/insights/"+query, token), http.StatusBadRequest)
+	}
+}
+
+func TestInsightOfAnotherUserIsNotFound(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, aliceToken := h.createVerifiedUser(t, "alice")
+	_, bobToken := h.createVerifiedUser(t, "bob")
+	insight := h.createInsight(t, &models.Insight{UserID: alice.ID, VideoID: "v1", Summary: "privé"})
+	path := "/insights/" + insight.ID.String()
+
+	// Bob n'apprend pas l'existence de l'insight d'Alice
+	expectStatus(t, h.get(t, path, bobToken), http.StatusNotFound)
+	expectStatus(t, h.get(t, path+"/export", bobToken), http.StatusNotFound)
+	expectStatus(t, h.send(t, http.MethodDelete, path, bobToken, nil), http.StatusNotFound)
+	var history []models.Insight
+	decodeData(t, h.get(t, "/insights/video/v1", bobToken), http.StatusOK, &history)
+	if len(history) != 0 {
+		t.Fatalf("historique d'Alice visible par Bob: %d insight(s)", len(history))
+	}
+	expectStatus(t, h.get(t, "/insights/video/v1/diff", bobToken), http.StatusNotFound)
+
+	var own models.Insight
+	decodeData(t, h.get(t, path, aliceToken), http.StatusOK, &own)
+	if own.ID != insight.ID || own.Summary != "privé" {
+		t.Fatalf("insight inattendu: %+v", own)
+	}
+	expectStatus(t, h.get(t, "/insights/pas-un-uuid", aliceToken), http.StatusBadRequest)
+	expectStatus(t, h.get(t, "/insights/00000000-0000-0000-0000-000000000001", aliceToken), http.StatusNotFound)
+
+	expectStatus(t, h.send(t, http.MethodDelete, path, aliceToken, nil), http.StatusNoContent)
+	expectStatus(t, h.get(t, path, aliceToken), http.StatusNotFound)
+}
+
+func TestGetInsightAnswersNotModifiedForItsETag(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, token := h.createVerifiedUser(t, "alice")
+	insight := h.createInsight(t, &models.Insight{UserID: alice.ID, VideoID: "v1"})
+	path := "/insights/" + insight.ID.String()
+
+	response := h.get(t, path, token)
+	response.Body.Close()
+	etag := response.Header.Get("ETag")
+	if response.StatusCode != http.StatusOK || etag != `"`+insight.ID.String()+`"` {
+		t.Fatalf("statut %d, ETag %q", response.StatusCode, etag)
+	}
+
+	conditional := func(ifNoneMatch string) *http.Response {
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Header.Set("If-None-Match", ifNoneMatch)
+		return h.do(t, request, token)
+	}
+	if response := conditional(etag); response.StatusCode != http.StatusNotModified {
+		t.Fatalf("If-None-Match identique: statut %d, attendu 304", response.StatusCode)
+	}
+	// Un autre insight (nouvelle version) : la réponse complète est renvoyée
+	expectStatus(t, conditional(`"`+alice.ID.String()+`"`), http.StatusOK)
+}
+
+func TestDiffInsightVersions(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, token := h.createVerifiedUser(t, "alice")
+	h.createInsight(t, &models.Insight{
+		UserID: alice.ID, VideoID: "v1", Sentiment: "Positif", CommentCount: 10,
+		Keywords: jsonStrings(t, "son", "micro"), QuestionComments: jsonStrings(t, "Quand sort la suite ?"),
+	})
+	h.createInsight(t, &models.Insight{
+		UserID: alice.ID, VideoID: "v1", Sentiment: "Positif", CommentCount: 20,
+		Keywords: jsonStrings(t, "son", "montage"), QuestionComments: jsonStrings(t, "Quand sort la suite ?"),
+	})
+	h.createInsight(t, &models.Insight{
+		UserID: alice.ID, VideoID: "v1", Sentiment: "Négatif", CommentCount: 30, NegativeCount: 4,
+		Keywords:         jsonStrings(t, "Son", "image"),
+		QuestionComments: jsonStrings(t, "quand sort  la suite ?", "Quel micro ?"),
+		NegativeComments: jsonStrings(t, "Image floue"),
+	})
+
+	// Par défaut : la dernière version et la précédente
+	var latest services.InsightDiff
+	decodeData(t, h.get(t, "/insights/video/v1/diff", token), http.StatusOK, &latest)
+	if latest.FromVersion != 2 || latest.ToVersion != 3 || !latest.SentimentChanged || latest.CommentCountBefore != 20 || latest.CommentCountAfter != 30 {
+		t.Fatalf("diff par défaut: %+v", latest)
+	}
+	// Comparaison insensible à la casse et aux espaces
+	if !slices.Equal(latest.KeywordsAdded, []string{"image"}) || !slices.Equal(latest.KeywordsRemoved, []string{"montage"}) ||
+		!slices.Equal(latest.NewQuestions, []string{"Quel micro ?"}) || !slices.Equal(latest.NewNegativeComments, []string{"Image floue"}) {
+		t.Fatalf("listes du diff: %+v", latest)
+	}
+
+	var explicit services.InsightDiff
+	decodeData(t, h.get(t, "/insights/video/v1/diff?from=1&to=2", token), http.StatusOK, &explicit)
+	if explicit.FromVersion != 1 || explicit.ToVersion != 2 || explicit.SentimentChanged ||
+		!slices.Equal(explicit.KeywordsAdded, []string{"montage"}) || !slices.Equal(explicit.KeywordsRemoved, []string{"micro"}) || len(explicit.NewQuestions) != 0 {
+		t.Fatalf("diff 1 → 2: %+v", explicit)
+	}
+
+	for _, query := range []string{"?from=2&to=2", "?from=-1"} {
+		expectStatus(t, h.get(t, "/insights/video/v1/diff"+query, token), http.StatusBadRequest)
+	}
+	for _, path := range []string{"/insights/video/v1/diff?to=9", "/insights/video/v1/diff?from=7", "/insights/video/inconnue/diff"} {
+		expectStatus(t, h.get(t, path, token), http.StatusNotFound)
+	}
+}
+
+func TestExportInsightFormats(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, token := h.createVerifiedUser(t, "alice")
+	insight := h.createInsight(t, &models.Insight{
+		UserID: alice.ID, VideoID: "v1", Sentiment: "Positif", Summary: "Le son plaît.", CommentCount: 3,
+		QuestionComments: jsonStrings(t, "Quand sort la suite ?"), NegativeComments: jsonStrings(t, "Image floue", "=1+1"),
+	})
+	second := h.createInsight(t, &models.Insight{UserID: alice.ID, VideoID: "v2", Summary: "Deuxième vidéo."})
+	path := "/insights/" + insight.ID.String() + "/export"
+
+	export := func(path, contentType, filename string) []byte {
+		t.Helper()
+		response := h.get(t, path, token)
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		if err != nil || response.StatusCode != http.StatusOK {
+			t.Fatalf("%s: statut %d, %v", path, response.StatusCode, err)
+		}
+		if got := response.Header.Get("Content-Type"); got != contentType {
+			t.Fatalf("%s: Content-Type %q, attendu %q", path, got, contentType)
+		}
+		if got := response.Header.Get("Content-Disposition"); !strings.HasPrefix(got, "attachment") || !strings.Contains(got, filename) {
+			t.Fatalf("%s: Content-Disposition %q, attendu %q", path, got, filename)
+		}
+		return body
+	}
+
+	records, err := csv.NewReader(bytes.NewReader(export(path+"?format=csv", "text/csv; charset=utf-8", "insight-v1-v1.csv"))).ReadAll()
+	if err != nil || len(records) != 4 || records[0][4] != "category" {
+		t.Fatalf("CSV: %v, %v", err, records)
+	}
+	if records[1][4] != "question" || records[1][5] != "Quand sort la suite ?" || records[3][5] != "'=1+1" {
+		t.Fatalf("lignes du CSV: %v", records[1:])
+	}
+
+	// JSON par défaut : l'insight complet
+	var exported models.Insight
+	if err := json.Unmarshal(export(path, "application/json; charset=utf-8", "insight-v1-v1.json"), &exported); err != nil || exported.ID != insight.ID || exported.Summary != "Le son plaît." {
+		t.Fatalf("JSON: %v, %+v", err, exported)
+	}
+
+	markdown := string(export(path+"?format=MD", "text/markdown; charset=utf-8", "insight-v1-v1.md"))
+	for _, want := range []string{"# Rapport d'analyse – vidéo v1 (version 1)", "## Sentiment général\n\nPositif", "- Quand sort la suite ?"} {
+		if !strings.Contains(markdown, want) {
+			t.Fatalf("Markdown sans %q:\n%s", want, markdown)
+		}
+	}
+
+	if pdf := export(path+"?format=pdf", "application/pdf", "insight-v1-v1.pdf"); !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.Contains(pdf, []byte("%%EOF")) {
+		t.Fatalf("PDF invalide (%d octets)", len(pdf))
+	}
+
+	// Lot : un tableau JSON, dans un fichier horodaté
+	var batch []models.Insight
+	body := export("/insights/export?ids="+insight.ID.String()+","+second.ID.String(), "application/json; charset=utf-8", "insights-")
+	if err := json.Unmarshal(body, &batch); err != nil || len(batch) != 2 {
+		t.Fatalf("export par lot: %v, %d insight(s)", err, len(batch))
+	}
+
+	expectStatus(t, h.get(t, path+"?format=xlsx", token), http.StatusBadRequest)
+	expectStatus(t, h.get(t, "/insights/export?format=csv", token), http.StatusBadRequest)
+	expectStatus(t, h.get(t, "/insights/export?ids=pas-un-uuid", token), http.StatusBadRequest)
+	_, bobToken := h.createVerifiedUser(t, "bob")
+	expectStatus(t, h.get(t, "/insights/export?ids="+insight.ID.String(), bobToken), http.StatusNotFound)
+}
+
+func TestAdminCanReadAnyInsight(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, _ := h.createVerifiedUser(t, "alice")
+	_, bobToken := h.createVerifiedUser(t, "bob")
+	_, adminToken := h.createAdmin(t, "root")
+	insight := h.createInsight(t, &models.Insight{UserID: alice.ID, VideoID: "v1", Summary: "privé"})
+	path := "/insights/" + insight.ID.String()
+
+	expectStatus(t, h.get(t, path, bobToken), http.StatusNotFound)
+	var read models.Insight
+	decodeData(t, h.get(t, path, adminToken), http.StatusOK, &read)
+	if read.ID != insight.ID || read.UserID != alice.ID {
+		t.Fatalf("insight lu par l'admin: %+v", read)
+	}
+	// Hors des routes /:id, l'admin choisit le compte avec ?user_id=
+	page := h.listInsights(t, "?user_id="+alice.ID.String(), adminToken)
+	if page.Pagination.Total != 1 || page.Data[0].ID != insight.ID {
+		t.Fatalf("liste des insights d'Alice par l'admin: %d", page.Pagination.Total)
+	}
+	if page := h.listInsights(t, "?user_id="+alice.ID.String(), bobToken); page.Pagination.Total != 0 {
+		t.Fatalf("?user_id= accepté pour un non-admin: %d insight(s)", page.Pagination.Total)
+	}
+}
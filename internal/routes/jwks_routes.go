@@ -0,0 +1,10 @@
+package routes
+
+import (
+	"github.com/Azertdev/FiberTest/internal/handlers"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupJWKSRoutes(app *fiber.App, jwksHandler handlers.JWKSHandler) {
+	app.Get("/.well-known/jwks.json", jwksHandler.GetJWKS)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/Azertdev/FiberTest/internal/handlers"
+	"github.com/gofiber/fiber/v2"
+)
+
+func SetupOAuthRoutes(app *fiber.App, oauthHandler handlers.OAuthHandler, authMiddleware fiber.Handler) {
+	oauthGroup := app.Group("/auth/oauth/:provider")
+	oauthGroup.Get("/login", oauthHandler.Login)
+	oauthGroup.Get("/link", authMiddleware, oauthHandler.Link)
+	oauthGroup.Get("/callback", oauthHandler.Callback)
+
+	app.Get("/users/me/youtube/videos", authMiddleware, oauthHandler.ListMyYouTubeVideos)
+}
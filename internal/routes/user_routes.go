@@ -3,14 +3,34 @@ package routes
 import (
 	"github.com/Azertdev/FiberTest/internal/handlers"
 	"github.com/Azertdev/FiberTest/internal/middleware"
+	"github.com/Azertdev/FiberTest/internal/models"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupUserRoutes(app *fiber.App, userHandler handlers.UserHandler) {
+// apiAuthMiddleware (JWT ou clé d'API) n'est utilisé que sur les routes d'administration, avec le scope admin
+func SetupUserRoutes(app *fiber.App, userHandler handlers.UserHandler, authMiddleware, apiAuthMiddleware fiber.Handler) {
 	userGroup := app.Group("/users")
 	userGroup.Post("/", userHandler.CreateUser)
-	userGroup.Get("/", middleware.JWTMiddleware, userHandler.GetAllUsers)
-	userGroup.Get("/:id", middleware.JWTMiddleware, userHandler.GetUserByID)
+	// Self-service : déclaré avant /:id
+	userGroup.Get("/me", authMiddleware, userHandler.GetMe)
+	userGroup.Patch("/me", authMiddleware, userHandler.UpdateMe)
+	userGroup.Put("/me/password", authMiddleware, userHandler.ChangePassword)
+	userGroup.Put("/me/email", authMiddleware, userHandler.ChangeEmail)
+	userGroup.Delete("/me", authMiddleware, userHandler.DeleteMe)
+	userGroup.Post("/me/verify-email", authMiddleware, userHandler.ResendEmailVerification)
+
+	// Liens envoyés par email (pas d'authentification : le token fait foi)
+	userGroup.Post("/verify-email/confirm", userHandler.ConfirmEmail)
+	userGroup.Post("/password-reset", userHandler.RequestPasswordReset)
+	userGroup.Post("/password-reset/confirm", userHandler.ConfirmPasswordReset)
+
+	adminOnly := middleware.RequireRole(models.RoleAdmin)
+	adminScope := middleware.RequireScope(models.APIKeyScopeAdmin)
+	userGroup.Get("/", apiAuthMiddleware, adminScope, adminOnly, userHandler.GetAllUsers)
+	userGroup.Get("/:id", apiAuthMiddleware, adminScope, adminOnly, userHandler.GetUserByID)
+	userGroup.Patch("/:id/role", apiAuthMiddleware, adminScope, adminOnly, userHandler.UpdateUserRole)
 	userGroup.Post("/authenticate", userHandler.LoginHandler)
+	userGroup.Post("/refresh", userHandler.RefreshHandler)
+	userGroup.Post("/logout", authMiddleware, userHandler.LogoutHandler)
 }
\ No newline at end of file
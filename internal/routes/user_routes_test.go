@@ -0,0 +1,482 @@
+package routes
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/services"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// signAccessToken signe des claims arbitraires avec la clé active du harnais
+func (h *testHarness) signAccessToken(t *testing.T, claims utils.Claims) string {
+	t.Helper()
+	token, err := h.Keys.Sign(claims)
+	if err != nil {
+		t.Fatalf("signature: %v", err)
+	}
+	return token
+}
+
+// expectUnauthorized vérifie un refus du middleware JWT
+func expectUnauthorized(t *testing.T, response *http.Response) {
+	t.Helper()
+	response.Body.Close()
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("statut %d, attendu 401", response.StatusCode)
+	}
+}
+
+func TestJWTWithMalformedSubjectIsRejected(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	user, _ := h.createVerifiedUser(t, "alice")
+	claims := func(subject, jti string) utils.Claims {
+		now := time.Now()
+		return utils.Claims{Role: "user", RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   subject,
+			Issuer:    utils.JWTIssuer,
+			Audience:  jwt.ClaimStrings{utils.JWTAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+		}}
+	}
+
+	// Token bien formé : accepté
+	valid := h.get(t, "/users/me", h.signAccessToken(t, claims(user.ID.String(), uuid.NewString())))
+	valid.Body.Close()
+	if valid.StatusCode != http.StatusOK {
+		t.Fatalf("token valide refusé: %d", valid.StatusCode)
+	}
+
+	cases := map[string]utils.Claims{
+		"sub non UUID":          claims("not-a-uuid", uuid.NewString()),
+		"sub absent":            claims("", uuid.NewString()),
+		"sub UUID nul":          claims(uuid.Nil.String(), uuid.NewString()),
+		"jti absent":            claims(user.ID.String(), ""),
+		"sub nom d'utilisateur": claims(user.Username, uuid.NewString()),
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			expectUnauthorized(t, h.get(t, "/users/me", h.signAccessToken(t, c)))
+		})
+	}
+}
+
+// refresh échange le refresh token via la route publique ; nil si la route le refuse (401)
+func (h *testHarness) refresh(t *testing.T, refreshToken string) *services.TokenPair {
+	t.Helper()
+	response := h.send(t, http.MethodPost, "/users/refresh", "", map[string]string{"refresh_token": refreshToken})
+	defer response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		var tokens services.TokenPair
+		if err := json.NewDecoder(response.Body).Decode(&tokens); err != nil {
+			t.Fatalf("paire de tokens illisible: %v", err)
+		}
+		return &tokens
+	case http.StatusUnauthorized:
+		return nil
+	}
+	t.Fatalf("POST /users/refresh: statut %d", response.StatusCode)
+	return nil
+}
+
+func TestRefreshRouteRotatesAndRevokesTheFamilyOnReplay(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	user, _ := h.createVerifiedUser(t, "alice")
+	login := h.issueTokens(t, user)
+
+	rotated := h.refresh(t, login.RefreshToken)
+	if rotated == nil || rotated.RefreshToken == login.RefreshToken {
+		t.Fatal("rotation refusée")
+	}
+	expectStatus(t, h.get(t, "/users/me", rotated.AccessToken), http.StatusOK)
+
+	// Le token volé est rejoué : l'attaquant et l'utilisateur légitime perdent la famille
+	if h.refresh(t, login.RefreshToken) != nil {
+		t.Fatal("refresh token rejoué accepté")
+	}
+	if h.refresh(t, rotated.RefreshToken) != nil {
+		t.Fatal("famille non révoquée après le rejeu")
+	}
+}
+
+func TestLogoutDenylistsTheAccessToken(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	user, _ := h.createVerifiedUser(t, "alice")
+	session := h.issueTokens(t, user)
+	other := h.issueTokens(t, user)
+
+	response := h.send(t, http.MethodPost, "/users/logout", session.AccessToken, map[string]string{"refresh_token": session.RefreshToken})
+	response.Body.Close()
+	if response.StatusCode != http.StatusNoContent {
+		t.Fatalf("déconnexion: statut %d", response.StatusCode)
+	}
+
+	// Le jti est en liste de révocation : le token, encore valide, est refusé par le middleware
+	expectUnauthorized(t, h.get(t, "/users/me", session.AccessToken))
+	expectUnauthorized(t, h.send(t, http.MethodPost, "/users/logout", session.AccessToken, nil))
+	if h.refresh(t, session.RefreshToken) != nil {
+		t.Fatal("refresh token accepté après la déconnexion")
+	}
+	// Les autres sessions restent ouvertes
+	expectStatus(t, h.get(t, "/users/me", other.AccessToken), http.StatusOK)
+	if h.refresh(t, other.RefreshToken) == nil {
+		t.Fatal("autre session fermée par la déconnexion")
+	}
+}
+
+// createAdmin crée un compte admin ; le rôle est dans le token, émis après la promotion
+func (h *testHarness) createAdmin(t *testing.T, username string) (*models.User, string) {
+	t.Helper()
+	admin, _ := h.createVerifiedUser(t, username)
+	if err := repositories.NewUserRepository(h.DB).UpdateRole(t.Context(), admin.ID, models.RoleAdmin); err != nil {
+		t.Fatalf("promotion: %v", err)
+	}
+	admin.Role = models.RoleAdmin
+	return admin, h.issueTokens(t, admin).AccessToken
+}
+
+func TestAdminRoutesRequireTheAdminRole(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, aliceToken := h.createVerifiedUser(t, "alice")
+	bob, _ := h.createVerifiedUser(t, "bob")
+	_, adminToken := h.createAdmin(t, "root")
+
+	promote := map[string]string{"role": models.RoleAdmin}
+	requests := []struct {
+		method, path string
+		body         any
+	}{
+		{http.MethodGet, "/users/", nil},
+		{http.MethodGet, "/users/" + bob.ID.String(), nil},
+		{http.MethodPatch, "/users/" + bob.ID.String() + "/role", promote},
+		// Un utilisateur ne s'attribue pas le rôle admin lui-même
+		{http.MethodPatch, "/users/" + alice.ID.String() + "/role", promote},
+	}
+	for _, r := range requests {
+		if response := h.send(t, r.method, r.path, aliceToken, r.body); response.StatusCode != http.StatusForbidden {
+			t.Errorf("%s %s par un utilisateur: statut %d, attendu 403", r.method, r.path, response.StatusCode)
+		}
+	}
+	if role := storedRole(t, h, alice.ID); role != models.RoleUser {
+		t.Fatalf("rôle d'Alice modifié: %q", role)
+	}
+
+	for _, r := range requests[:3] {
+		if response := h.send(t, r.method, r.path, adminToken, r.body); response.StatusCode != http.StatusOK {
+			t.Errorf("%s %s par un admin: statut %d, attendu 200", r.method, r.path, response.StatusCode)
+		}
+	}
+	if role := storedRole(t, h, bob.ID); role != models.RoleAdmin {
+		t.Fatalf("Bob non promu: %q", role)
+	}
+}
+
+func storedRole(t *testing.T, h *testHarness, userID uuid.UUID) string {
+	t.Helper()
+	user, err := repositories.NewUserRepository(h.DB).FindByID(t.Context(), userID)
+	if err != nil {
+		t.Fatalf("lecture du compte: %v", err)
+	}
+	return user.Role
+}
+
+func TestCreateUserIgnoresClientSuppliedRole(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	response := h.send(t, http.MethodPost, "/users/", "", map[string]string{
+		"username": "mallory", "email": "mallory@example.com", "password": "motdepasse-solide", "role": models.RoleAdmin,
+	})
+	response.Body.Close()
+	if response.StatusCode != http.StatusCreated {
+		t.Fatalf("inscription: statut %d", response.StatusCode)
+	}
+	user, err := repositories.NewUserRepository(h.DB).FindByUsername(t.Context(), "mallory")
+	if err != nil {
+		t.Fatalf("compte non créé: %v", err)
+	}
+	if user.Role != models.RoleUser {
+		t.Fatalf("rôle %q choisi par le client", user.Role)
+	}
+}
+
+// setPassword remplace le hash factice du harnais par celui de password
+func (h *testHarness) setPassword(t *testing.T, user *models.User, password string) {
+	t.Helper()
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repositories.NewUserRepository(h.DB).UpdateFields(t.Context(), user.ID, map[string]any{"password": string(hashed)}); err != nil {
+		t.Fatalf("mot de passe: %v", err)
+	}
+}
+
+// loginStatus tente une connexion par /users/authenticate et retourne le statut
+func (h *testHarness) loginStatus(t *testing.T, username, password string) int {
+	t.Helper()
+	response := h.send(t, http.MethodPost, "/users/authenticate", "", map[string]string{"username": username, "password": password})
+	response.Body.Close()
+	return response.StatusCode
+}
+
+func TestChangePasswordRequiresTheCurrentPasswordAndRevokesSessions(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, accessToken := h.createVerifiedUser(t, "alice")
+	h.setPassword(t, alice, "ancien-mot-de-passe")
+	otherDevice := h.issueTokens(t, alice)
+
+	wrong := map[string]string{"current_password": "devine", "new_password": "nouveau-mot-de-passe"}
+	if response := h.send(t, http.MethodPut, "/users/me/password", accessToken, wrong); response.StatusCode != http.StatusForbidden {
+		t.Fatalf("mot de passe actuel erroné: statut %d, attendu 403", response.StatusCode)
+	}
+	if status := h.loginStatus(t, "alice", "ancien-mot-de-passe"); status != http.StatusOK {
+		t.Fatalf("mot de passe modifié malgré le refus: connexion %d", status)
+	}
+	if h.refresh(t, otherDevice.RefreshToken) == nil {
+		t.Fatal("sessions révoquées malgré le refus")
+	}
+
+	otherDevice = h.issueTokens(t, alice)
+	change := map[string]string{"current_password": "ancien-mot-de-passe", "new_password": "nouveau-mot-de-passe"}
+	if response := h.send(t, http.MethodPut, "/users/me/password", accessToken, change); response.StatusCode != http.StatusNoContent {
+		t.Fatalf("changement de mot de passe: statut %d", response.StatusCode)
+	}
+	if h.refresh(t, otherDevice.RefreshToken) != nil {
+		t.Fatal("session d'un autre appareil encore renouvelable")
+	}
+	if status := h.loginStatus(t, "alice", "ancien-mot-de-passe"); status != http.StatusUnauthorized {
+		t.Fatalf("ancien mot de passe: connexion %d, attendu 401", status)
+	}
+	if status := h.loginStatus(t, "alice", "nouveau-mot-de-passe"); status != http.StatusOK {
+		t.Fatalf("nouveau mot de passe: connexion %d", status)
+	}
+}
+
+func TestChangeEmailResetsVerification(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, accessToken := h.createVerifiedUser(t, "alice")
+	h.setPassword(t, alice, "mot-de-passe-alice")
+	users := repositories.NewUserRepository(h.DB)
+
+	wrong := map[string]string{"current_password": "devine", "new_email": "alice@nouveau.example"}
+	if response := h.send(t, http.MethodPut, "/users/me/email", accessToken, wrong); response.StatusCode != http.StatusForbidden {
+		t.Fatalf("mot de passe actuel erroné: statut %d, attendu 403", response.StatusCode)
+	}
+	if stored, _ := users.FindByID(t.Context(), alice.ID); stored.Email != "alice@example.com" || !stored.IsEmailVerified() {
+		t.Fatalf("compte modifié malgré le refus: %s, vérifié %t", stored.Email, stored.IsEmailVerified())
+	}
+
+	change := map[string]string{"current_password": "mot-de-passe-alice", "new_email": "alice@nouveau.example"}
+	response := h.send(t, http.MethodPut, "/users/me/email", accessToken, change)
+	defer response.Body.Close()
+	var me models.UserResponse
+	if err := json.NewDecoder(response.Body).Decode(&me); err != nil || response.StatusCode != http.StatusOK {
+		t.Fatalf("changement d'email: statut %d, %v", response.StatusCode, err)
+	}
+	if me.Email != "alice@nouveau.example" || me.EmailVerified {
+		t.Fatalf("réponse: %+v, attendu la nouvelle adresse non vérifiée", me)
+	}
+	if stored, _ := users.FindByID(t.Context(), alice.ID); stored.EmailVerifiedAt != nil {
+		t.Fatalf("email_verified_at conservé: %v", stored.EmailVerifiedAt)
+	}
+}
+
+func TestDeleteMeRemovesTheAccountAndItsData(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, accessToken := h.createVerifiedUser(t, "alice")
+	bob, _ := h.createVerifiedUser(t, "bob")
+	h.setPassword(t, alice, "mot-de-passe-alice")
+	alertRules := repositories.NewAlertRuleRepository(h.DB)
+	for _, user := range []*models.User{alice, bob} {
+		h.createInsight(t, &models.Insight{UserID: user.ID, VideoID: "v1"})
+		h.createAPIKey(t, user, models.APIKeyScopeReadInsights)
+		if err := alertRules.CreateAlertRule(t.Context(), &models.AlertRule{UserID: user.ID, Type: "negative_ratio", Threshold: 0.4, Enabled: true}); err != nil {
+			t.Fatalf("CreateAlertRule: %v", err)
+		}
+	}
+
+	wrong := map[string]string{"current_password": "devine"}
+	if response := h.send(t, http.MethodDelete, "/users/me", accessToken, wrong); response.StatusCode != http.StatusForbidden {
+		t.Fatalf("mot de passe actuel erroné: statut %d, attendu 403", response.StatusCode)
+	}
+	if response := h.send(t, http.MethodDelete, "/users/me", accessToken, map[string]string{"current_password": "mot-de-passe-alice"}); response.StatusCode != http.StatusNoContent {
+		t.Fatalf("suppression du compte: statut %d", response.StatusCode)
+	}
+
+	if _, err := repositories.NewUserRepository(h.DB).FindByID(t.Context(), alice.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("compte toujours présent: %v", err)
+	}
+	for _, model := range []any{&models.Insight{}, &models.RefreshToken{}, &models.APIKey{}, &models.AlertRule{}} {
+		for user, expected := range map[*models.User]int64{alice: 0, bob: 1} {
+			var count int64
+			if err := h.DB.Model(model).Where("user_id = ?", user.ID).Count(&count).Error; err != nil {
+				t.Fatalf("comptage %T: %v", model, err)
+			}
+			if count != expected {
+				t.Errorf("%T de %s: %d ligne(s), attendu %d", model, user.Username, count, expected)
+			}
+		}
+	}
+}
+
+var mailedTokenPattern = regexp.MustCompile(`\?token=(\S+)`)
+
+// mailedToken extrait le token du dernier lien envoyé à to
+func (h *testHarness) mailedToken(t *testing.T, to string) string {
+	t.Helper()
+	sent := h.Mailer.Sent(to)
+	if len(sent) == 0 {
+		t.Fatalf("aucun email envoyé à %s", to)
+	}
+	match := mailedTokenPattern.FindStringSubmatch(sent[len(sent)-1].Body)
+	if match == nil {
+		t.Fatalf("aucun lien dans l'email: %q", sent[len(sent)-1].Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("lien illisible: %v", err)
+	}
+	return token
+}
+
+// expireAccountTokens fait expirer les liens envoyés à user
+func (h *testHarness) expireAccountTokens(t *testing.T, user *models.User) {
+	t.Helper()
+	if err := h.DB.Model(&models.AccountToken{}).Where("user_id = ?", user.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatalf("expiration des liens: %v", err)
+	}
+}
+
+func TestEmailVerificationIsSingleUseAndUnlocksComments(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	h.YouTube.SetComments("v1", videoComments(5))
+	alice := &models.User{Username: "alice", Email: "alice@example.com", Password: "not-a-hash"}
+	if err := repositories.NewUserRepository(h.DB).Create(t.Context(), alice); err != nil {
+		t.Fatalf("création de l'utilisateur: %v", err)
+	}
+	accessToken := h.issueTokens(t, alice).AccessToken
+
+	expectStatus(t, h.get(t, "/comments?video_id=v1", accessToken), http.StatusForbidden)
+
+	confirm := func(token string) int {
+		t.Helper()
+		response := h.send(t, http.MethodPost, "/users/verify-email/confirm", "", map[string]string{"token": token})
+		response.Body.Close()
+		return response.StatusCode
+	}
+	resend := func() {
+		t.Helper()
+		if response := h.send(t, http.MethodPost, "/users/me/verify-email", accessToken, nil); response.StatusCode != http.StatusAccepted {
+			t.Fatalf("envoi du lien: statut %d", response.StatusCode)
+		}
+	}
+
+	resend()
+	h.expireAccountTokens(t, alice)
+	if status := confirm(h.mailedToken(t, alice.Email)); status != http.StatusBadRequest {
+		t.Fatalf("lien expiré: statut %d, attendu 400", status)
+	}
+	resend()
+	replaced := h.mailedToken(t, alice.Email)
+	resend()
+	// Seul le dernier lien envoyé fonctionne
+	if status := confirm(replaced); status != http.StatusBadRequest {
+		t.Fatalf("lien remplacé: statut %d, attendu 400", status)
+	}
+	expectStatus(t, h.get(t, "/comments?video_id=v1", accessToken), http.StatusForbidden)
+
+	token := h.mailedToken(t, alice.Email)
+	if status := confirm(token); status != http.StatusNoContent {
+		t.Fatalf("confirmation: statut %d", status)
+	}
+	if status := confirm(token); status != http.StatusBadRequest {
+		t.Fatalf("second usage du lien: statut %d, attendu 400", status)
+	}
+	decodeComments(t, h.get(t, "/comments?video_id=v1", accessToken), http.StatusCreated)
+}
+
+func TestPasswordResetIsSingleUseAndRevokesSessions(t *testing.T) {
+	t.Parallel()
+	h := newTestHarness(t)
+	alice, _ := h.createVerifiedUser(t, "alice")
+	h.setPassword(t, alice, "ancien-mot-de-passe")
+	session := h.issueTokens(t, alice)
+
+	requestReset := func(email string) (int, string) {
+		t.Helper()
+		response := h.send(t, http.MethodPost, "/users/password-reset", "", map[string]string{"email": email})
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return response.StatusCode, string(body)
+	}
+	confirm := func(token, password string) int {
+		t.Helper()
+		response := h.send(t, http.MethodPost, "/users/password-reset/confirm", "", map[string]string{"token": token, "new_password": password})
+		response.Body.Close()
+		return response.StatusCode
+	}
+
+	// Adresse inconnue : même réponse, aucun email envoyé
+	knownStatus, knownBody := requestReset(alice.Email)
+	unknownStatus, unknownBody := requestReset("personne@example.com")
+	if knownStatus != http.StatusAccepted || unknownStatus != knownStatus || unknownBody != knownBody {
+		t.Fatalf("réponses distinguables: %d %q / %d %q", knownStatus, knownBody, unknownStatus, unknownBody)
+	}
+	if sent := h.Mailer.Sent("personne@example.com"); len(sent) != 0 {
+		t.Fatalf("%d email(s) envoyé(s) à une adresse inconnue", len(sent))
+	}
+
+	h.expireAccountTokens(t, alice)
+	if status := confirm(h.mailedToken(t, alice.Email), "mot-de-passe-expire"); status != http.StatusBadRequest {
+		t.Fatalf("lien expiré: statut %d, attendu 400", status)
+	}
+	if h.refresh(t, session.RefreshToken) == nil {
+		t.Fatal("sessions révoquées par un lien expiré")
+	}
+
+	requestReset(alice.Email)
+	token := h.mailedToken(t, alice.Email)
+	session = h.issueTokens(t, alice)
+	if status := confirm(token, "nouveau-mot-de-passe"); status != http.StatusNoContent {
+		t.Fatalf("réinitialisation: statut %d", status)
+	}
+	if h.refresh(t, session.RefreshToken) != nil {
+		t.Fatal("session encore renouvelable après la réinitialisation")
+	}
+	if status := confirm(token, "troisieme-mot-de-passe"); status != http.StatusBadRequest {
+		t.Fatalf("second usage du lien: statut %d, attendu 400", status)
+	}
+	if status := h.loginStatus(t, "alice", "ancien-mot-de-passe"); status != http.StatusUnauthorized {
+		t.Fatalf("ancien mot de passe: connexion %d, attendu 401", status)
+	}
+	if status := h.loginStatus(t, "alice", "nouveau-mot-de-passe"); status != http.StatusOK {
+		t.Fatalf("nouveau mot de passe: connexion %d", status)
+	}
+}
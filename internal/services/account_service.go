@@ -0,0 +1,190 @@
+// internal/services/account_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+)
+
+// Durées de validité des liens envoyés par email
+const (
+	EmailVerificationTokenLifetime = 48 * time.Hour
+	PasswordResetTokenLifetime     = time.Hour
+)
+
+var (
+	ErrInvalidAccountToken  = errors.New("lien invalide, expiré ou déjà utilisé")
+	ErrEmailAlreadyVerified = errors.New("adresse email déjà vérifiée")
+)
+
+// AccountService gère la vérification d'adresse email et la réinitialisation du mot de passe
+type AccountService interface {
+	// SendEmailVerification invalide les liens précédents et envoie un nouveau lien de vérification
+	SendEmailVerification(ctx context.Context, userID uuid.UUID) error
+	ConfirmEmail(ctx context.Context, token string) error
+	// RequestPasswordReset n'indique jamais si l'adresse existe (pas d'énumération des comptes)
+	RequestPasswordReset(ctx context.Context, email string) error
+	// ResetPassword change le mot de passe et déconnecte toutes les sessions
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+type accountService struct {
+	tokenRepo  repositories.TokenRepository
+	userRepo   repositories.UserRepository
+	transactor repositories.Transactor // consommation du lien et modification du compte atomiques
+	mailer     Mailer
+	appBaseURL string
+}
+
+func NewAccountService(tokenRepo repositories.TokenRepository, userRepo repositories.UserRepository, transactor repositories.Transactor, mailer Mailer, appBaseURL string) AccountService {
+	if tokenRepo == nil || userRepo == nil || transactor == nil || mailer == nil {
+		log.Fatal("ERREUR FATALE: Dépendances manquantes lors de la création de AccountService")
+	}
+	return &accountService{
+		tokenRepo:  tokenRepo,
+		userRepo:   userRepo,
+		transactor: transactor,
+		mailer:     mailer,
+		appBaseURL: strings.TrimRight(appBaseURL, "/"),
+	}
+}
+
+func (s *accountService) SendEmailVerification(ctx context.Context, userID uuid.UUID) error {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	token, err := s.issueAccountToken(ctx, user.ID, models.AccountTokenEmailVerification, EmailVerificationTokenLifetime)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Bonjour %s,\n\nConfirmez votre adresse email en ouvrant ce lien (valable %s) :\n%s\n\nSi vous n'avez pas créé de compte EngageSense, ignorez ce message.\n",
+		user.Username, EmailVerificationTokenLifetime, s.link("/verify-email", token))
+	return s.mailer.Send(ctx, user.Email, "Confirmez votre adresse email", body)
+}
+
+func (s *accountService) ConfirmEmail(ctx context.Context, token string) error {
+	// Le lien n'est consommé que si la vérification est enregistrée
+	var stored *models.AccountToken
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		stored, err = s.tokenRepo.ConsumeAccountToken(ctx, hashOpaqueToken(token), models.AccountTokenEmailVerification, time.Now())
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return ErrInvalidAccountToken
+		}
+		return s.userRepo.UpdateFields(ctx, stored.UserID, map[string]any{"email_verified_at": time.Now()})
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidAccountToken
+		}
+		return err
+	}
+	log.Printf("INFO: [UserID: %s] Adresse email vérifiée", stored.UserID)
+	return nil
+}
+
+func (s *accountService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("INFO: Demande de réinitialisation pour une adresse inconnue, ignorée")
+			return nil
+		}
+		return err
+	}
+
+	token, err := s.issueAccountToken(ctx, user.ID, models.AccountTokenPasswordReset, PasswordResetTokenLifetime)
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Bonjour %s,\n\nPour choisir un nouveau mot de passe, ouvrez ce lien (valable %s) :\n%s\n\nSi vous n'êtes pas à l'origine de cette demande, ignorez ce message : votre mot de passe reste inchangé.\n",
+		user.Username, PasswordResetTokenLifetime, s.link("/reset-password", token))
+	return s.mailer.Send(ctx, user.Email, "Réinitialisation de votre mot de passe", body)
+}
+
+func (s *accountService) ResetPassword(ctx context.Context, token, newPassword string) error {
+	// Hachage avant la transaction : bcrypt est lent et ne doit pas la prolonger
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	var stored *models.AccountToken
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		stored, err = s.tokenRepo.ConsumeAccountToken(ctx, hashOpaqueToken(token), models.AccountTokenPasswordReset, time.Now())
+		if err != nil {
+			return err
+		}
+		if stored == nil {
+			return ErrInvalidAccountToken
+		}
+		if err := s.userRepo.UpdateFields(ctx, stored.UserID, map[string]any{"password": string(hashedPassword)}); err != nil {
+			return err
+		}
+		// Le mot de passe a pu être compromis : toutes les sessions ouvertes sont fermées
+		return s.tokenRepo.RevokeUserRefreshTokens(ctx, stored.UserID)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidAccountToken
+		}
+		return err
+	}
+	log.Printf("INFO: [UserID: %s] Mot de passe réinitialisé, sessions révoquées", stored.UserID)
+	return nil
+}
+
+func (s *accountService) IsEmailVerified(ctx context.Context, userID uuid.UUID) (bool, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return user.IsEmailVerified(), nil
+}
+
+// issueAccountToken invalide les tokens précédents du même usage : seul le dernier lien envoyé fonctionne
+func (s *accountService) issueAccountToken(ctx context.Context, userID uuid.UUID, purpose string, lifetime time.Duration) (string, error) {
+	if err := s.tokenRepo.InvalidateAccountTokens(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+	token, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	err = s.tokenRepo.CreateAccountToken(ctx, &models.AccountToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashOpaqueToken(token),
+		ExpiresAt: time.Now().Add(lifetime),
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (s *accountService) link(path, token string) string {
+	return s.appBaseURL + path + "?token=" + url.QueryEscape(token)
+}
@@ -0,0 +1,148 @@
+// internal/services/alert_service.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+type AlertService interface {
+	CreateRule(ctx context.Context, userID uuid.UUID, rule *models.AlertRule) error
+	ListRules(ctx context.Context, userID uuid.UUID) ([]models.AlertRule, error)
+	DeleteRule(ctx context.Context, userID, ruleID uuid.UUID) error
+	// EvaluateInsight applique les règles de l'utilisateur à un Insight (après une analyse ou une synchronisation)
+	// et retourne les notifications 'alert' créées.
+	EvaluateInsight(ctx context.Context, insight *models.Insight) ([]models.Notification, error)
+}
+
+type alertService struct {
+	alertRuleRepo       repositories.AlertRuleRepository
+	notificationService NotificationService
+}
+
+func NewAlertService(alertRuleRepo repositories.AlertRuleRepository, notificationService NotificationService) AlertService {
+	if alertRuleRepo == nil || notificationService == nil {
+		log.Fatal("ERREUR FATALE: Dépendances manquantes lors de la création de AlertService")
+	}
+	return &alertService{
+		alertRuleRepo:       alertRuleRepo,
+		notificationService: notificationService,
+	}
+}
+
+func (s *alertService) CreateRule(ctx context.Context, userID uuid.UUID, rule *models.AlertRule) error {
+	rule.ID = uuid.Nil
+	rule.UserID = userID
+	rule.Keyword = strings.TrimSpace(rule.Keyword)
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	switch {
+	case rule.Type == models.AlertRuleNegativeRatio && (rule.Threshold < 0 || rule.Threshold > 100):
+		return fmt.Errorf("le seuil d'une règle negative_ratio est un pourcentage (0-100)")
+	case rule.Type == models.AlertRuleSentimentScore && rule.Threshold > 1:
+		return fmt.Errorf("le seuil d'une règle sentiment_score est un score de -1 à 1")
+	case rule.Type != models.AlertRuleSentimentScore && rule.Threshold < 0:
+		return fmt.Errorf("le seuil d'une règle %s ne peut pas être négatif", rule.Type)
+	}
+	return s.alertRuleRepo.CreateAlertRule(ctx, rule)
+}
+
+func (s *alertService) ListRules(ctx context.Context, userID uuid.UUID) ([]models.AlertRule, error) {
+	return s.alertRuleRepo.ListAlertRulesByUser(ctx, userID)
+}
+
+func (s *alertService) DeleteRule(ctx context.Context, userID, ruleID uuid.UUID) error {
+	return s.alertRuleRepo.DeleteAlertRule(ctx, userID, ruleID)
+}
+
+func (s *alertService) EvaluateInsight(ctx context.Context, insight *models.Insight) ([]models.Notification, error) {
+	rules, err := s.alertRuleRepo.ListActiveRulesForInsight(ctx, insight.UserID, insight.VideoID, insight.ChannelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	log.Printf("INFO: [UserID: %s] Évaluation de %d règle(s) d'alerte pour videoID: %s", insight.UserID, len(rules), insight.VideoID)
+
+	var fired []models.Notification
+	for _, rule := range rules {
+		message, triggered := evaluateAlertRule(rule, insight)
+		if !triggered {
+			continue
+		}
+		notification, err := s.notificationService.Notify(ctx, insight.UserID, NotificationTypeAlert, message)
+		if err != nil {
+			log.Printf("WARN: [UserID: %s] Règle %s déclenchée mais notification non créée: %v", insight.UserID, rule.ID, err)
+			continue
+		}
+		fired = append(fired, *notification)
+	}
+	return fired, nil
+}
+
+// evaluateAlertRule retourne le message d'alerte et true si la règle est déclenchée par l'insight
+func evaluateAlertRule(rule models.AlertRule, insight *models.Insight) (string, bool) {
+	switch rule.Type {
+	case models.AlertRuleNegativeRatio:
+		negative, total := negativeShare(insight)
+		if total == 0 {
+			return "", false
+		}
+		ratio := float64(negative) * 100 / float64(total)
+		if ratio > rule.Threshold {
+			return fmt.Sprintf("Vidéo %s : %.1f%% de commentaires négatifs (%d/%d), seuil de %.1f%% dépassé.",
+				insight.VideoID, ratio, negative, total, rule.Threshold), true
+		}
+	case models.AlertRuleKeyword:
+		keyword := strings.ToLower(strings.TrimSpace(rule.Keyword))
+		if keyword != "" && insightMentions(insight, keyword) {
+			return fmt.Sprintf("Vidéo %s : le mot-clé \"%s\" apparaît dans les commentaires.", insight.VideoID, rule.Keyword), true
+		}
+	case models.AlertRuleUnansweredQuestions:
+		if float64(insight.UnansweredQuestions) > rule.Threshold {
+			return fmt.Sprintf("Vidéo %s : %d questions sans réponse (seuil : %.0f).",
+				insight.VideoID, insight.UnansweredQuestions, rule.Threshold), true
+		}
+	case models.AlertRuleSentimentScore:
+		// Sans commentaire évalué, le score (0) ne mesure rien
+		stats := insight.SentimentStats
+		if stats.Scored > 0 && stats.Score < rule.Threshold {
+			return fmt.Sprintf("Vidéo %s : score de sentiment de %.2f (confiance %.0f%%, %d négatifs sur %d), sous le seuil de %.2f.",
+				insight.VideoID, stats.Score, stats.Confidence*100, stats.Negative, stats.Scored, rule.Threshold), true
+		}
+	}
+	return "", false
+}
+
+// negativeShare retourne le nombre de commentaires négatifs et le nombre de commentaires sur
+// lequel la part est calculée. Les étiquettes par commentaire (SentimentStats) sont exactes ;
+// sans elles (insights antérieurs aux polarités), NegativeCount est la longueur de la liste de
+// critiques produite par le modèle, rapportée à tous les commentaires : une approximation.
+func negativeShare(insight *models.Insight) (negative, total int) {
+	if stats := insight.SentimentStats; stats.Scored > 0 {
+		return stats.Negative, stats.Scored
+	}
+	return insight.NegativeCount, insight.CommentCount
+}
+
+// insightMentions cherche le mot-clé (en minuscule) dans les mots-clés et les listes de commentaires de l'insight
+func insightMentions(insight *models.Insight, keyword string) bool {
+	for _, field := range [][]byte{insight.Keywords, insight.NegativeComments, insight.QuestionComments, insight.TopComments, insight.FeedbackComments} {
+		for _, item := range utils.DecodeStringList(field) {
+			if strings.Contains(strings.ToLower(item), keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
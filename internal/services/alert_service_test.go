@@ -0,0 +1,56 @@
+// internal/services/alert_service_test.go
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"gorm.io/datatypes"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestEvaluateAlertRule(t *testing.T) {
+	labelled := &models.Insight{
+		VideoID:             "vid",
+		CommentCount:        10,
+		NegativeCount:       1, // liste du modèle, ignorée quand les étiquettes existent
+		UnansweredQuestions: 3,
+		SentimentStats:      models.SentimentStats{Score: -0.4, Confidence: 0.6, Negative: 4, Positive: 4, Scored: 8},
+		Keywords:            datatypes.JSON(`["Qualité du son","micro"]`),
+		QuestionComments:    datatypes.JSON(`["Quel Logiciel de montage ?"]`),
+	}
+	legacy := &models.Insight{VideoID: "old", CommentCount: 10, NegativeCount: 3}
+
+	cases := []struct {
+		name      string
+		rule      models.AlertRule
+		insight   *models.Insight
+		triggered bool
+		message   string
+	}{
+		{"part négative sur les étiquettes", models.AlertRule{Type: models.AlertRuleNegativeRatio, Threshold: 40}, labelled, true, "50.0% de commentaires négatifs (4/8)"},
+		{"part négative sous le seuil", models.AlertRule{Type: models.AlertRuleNegativeRatio, Threshold: 50}, labelled, false, ""},
+		{"part négative sans étiquettes", models.AlertRule{Type: models.AlertRuleNegativeRatio, Threshold: 20}, legacy, true, "(3/10)"},
+		{"part négative sans commentaire", models.AlertRule{Type: models.AlertRuleNegativeRatio}, &models.Insight{}, false, ""},
+		{"mot-clé dans les mots-clés", models.AlertRule{Type: models.AlertRuleKeyword, Keyword: "Micro"}, labelled, true, `"Micro"`},
+		{"mot-clé dans les questions", models.AlertRule{Type: models.AlertRuleKeyword, Keyword: "logiciel"}, labelled, true, ""},
+		{"mot-clé absent", models.AlertRule{Type: models.AlertRuleKeyword, Keyword: "lumière"}, labelled, false, ""},
+		{"questions sans réponse", models.AlertRule{Type: models.AlertRuleUnansweredQuestions, Threshold: 2}, labelled, true, "3 questions"},
+		{"questions sous le seuil", models.AlertRule{Type: models.AlertRuleUnansweredQuestions, Threshold: 3}, labelled, false, ""},
+		{"score sous le seuil", models.AlertRule{Type: models.AlertRuleSentimentScore, Threshold: -0.2}, labelled, true, "-0.40"},
+		{"score au-dessus du seuil", models.AlertRule{Type: models.AlertRuleSentimentScore, Threshold: -0.5}, labelled, false, ""},
+		{"score sans commentaire évalué", models.AlertRule{Type: models.AlertRuleSentimentScore, Threshold: 0.5}, legacy, false, ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			message, triggered := evaluateAlertRule(tc.rule, tc.insight)
+			if triggered != tc.triggered {
+				t.Fatalf("déclenchée: %v, message %q", triggered, message)
+			}
+			if !strings.Contains(message, tc.message) {
+				t.Fatalf("message %q, attendu %q", message, tc.message)
+			}
+		})
+	}
+}
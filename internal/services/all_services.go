@@ -4,18 +4,42 @@ import (
 	"log" // Pour la validation des dépendances
 
 	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
 )
 
+// ServiceOptions : réglages de la couche services issus de la configuration (voir config.Load)
+type ServiceOptions struct {
+	AppBaseURL string // URL du front utilisée dans les liens envoyés par email
+	Analysis   AnalysisOptions
+	LoginGuard LoginGuardPolicy
+}
+
 type AllServices struct {
-	UserService    UserService
-	CommentService CommentService
+	UserService         UserService
+	CommentService      CommentService
+	NotificationService NotificationService
+	AlertService        AlertService
+	InsightService      InsightService
+	TokenService        TokenService
+	AccountService      AccountService
+	LoginGuardService   LoginGuardService
+	OAuthService        OAuthService
+	APIKeyService       APIKeyService
+	HealthService       HealthService
 }
 
 func NewAllServices(
 	allRepositories repositories.AllRepository,
 	youtubeAdapter YouTubeAdapter,                  // <- Ajouté (Interface)
 	groqAdapter    GroqAdapter,                     // <- Ajouté (Interface)
+	offlineAnalyzer GroqAdapter,                    // Analyse hors ligne (repli de Groq, plans sans IA)
 	transcriptUtil TranscriptUtil,                  // <- Ajouté (Interface)
+	notificationChannels []NotificationChannel,     // Canaux de diffusion des notifications (log, webhook...)
+	mailer Mailer,                                  // Envoi des emails de vérification / réinitialisation
+	oauthProviders []OIDCProvider,                  // Fournisseurs OpenID Connect configurés (Google...)
+	oauthSecretBox *utils.SecretBox,                // Chiffrement des refresh tokens OAuth stockés
+	jwtKeys *utils.KeySet,                          // Clés de signature des tokens d'accès (voir config.LoadJWTKeySet)
+	options ServiceOptions,                         // Réglages issus de la configuration
 
 ) *AllServices {
 
@@ -25,27 +49,56 @@ func NewAllServices(
 	if allRepositories.InsightRepository == nil {
 		log.Fatal("ERREUR FATALE: InsightRepository manquant lors de la création de AllServices")
 	}
+	if mailer == nil {
+		log.Fatal("ERREUR FATALE: Mailer manquant lors de la création de AllServices")
+	}
 	if youtubeAdapter == nil {
 		log.Fatal("ERREUR FATALE: YouTubeAdapter manquant lors de la création de AllServices")
 	}
 	if groqAdapter == nil {
 		log.Fatal("ERREUR FATALE: GroqAdapter manquant lors de la création de AllServices")
 	}
+	if offlineAnalyzer == nil {
+		log.Fatal("ERREUR FATALE: Analyseur hors ligne manquant lors de la création de AllServices")
+	}
 	if transcriptUtil == nil {
 		log.Fatal("ERREUR FATALE: TranscriptUtil manquant lors de la création de AllServices")
 	}
 	userService := NewUserService(allRepositories.UserRepository)
+	notificationService := NewNotificationService(allRepositories.NotificationRepository, notificationChannels)
+	alertService := NewAlertService(allRepositories.AlertRuleRepository, notificationService)
 
 	commentService := NewCommentService(
-		allRepositories.CommentRepository, // Passez le repo Commentaire (ou nil)
+		allRepositories.CommentRepository,
 		allRepositories.InsightRepository,
+		allRepositories.Transactor,
+		allRepositories.SubscriptionRepository,
 		youtubeAdapter,
 		groqAdapter,
+		offlineAnalyzer,
 		transcriptUtil,
+		alertService,
+		options.Analysis,
 	)
 
+	insightService := NewInsightService(allRepositories.InsightRepository)
+	tokenService := NewTokenService(allRepositories.TokenRepository, allRepositories.UserRepository, jwtKeys)
+	loginGuardService := NewLoginGuardService(allRepositories.LoginAttemptRepository, allRepositories.UserRepository, notificationService, options.LoginGuard)
+	oauthService := NewOAuthService(allRepositories.OAuthIdentityRepository, allRepositories.UserRepository, oauthProviders, oauthSecretBox, youtubeAdapter)
+	apiKeyService := NewAPIKeyService(allRepositories.APIKeyRepository, allRepositories.UserRepository)
+	accountService := NewAccountService(allRepositories.TokenRepository, allRepositories.UserRepository, allRepositories.Transactor, mailer, options.AppBaseURL)
+
 	return &AllServices{
 		UserService:    userService,
 		CommentService: commentService,
+		NotificationService: notificationService,
+		AlertService:        alertService,
+		InsightService:      insightService,
+		TokenService:        tokenService,
+		AccountService:      accountService,
+		LoginGuardService:   loginGuardService,
+		OAuthService:        oauthService,
+		APIKeyService:       apiKeyService,
+		HealthService:       NewHealthService(allRepositories.HealthRepository),
 	}
 }
\ No newline at end of file
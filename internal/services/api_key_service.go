@@ -0,0 +1,177 @@
+// internal/services/api_key_service.go
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// Préfixe des clés d'API : "es_<prefix>_<secret>"
+const APIKeyTokenPrefix = "es_"
+
+// Nombre maximal de clés actives par utilisateur
+const MaxActiveAPIKeysPerUser = 20
+
+// lastUsedResolution : LastUsedAt n'est réécrit qu'une fois par minute au plus (pas d'écriture à chaque requête)
+const lastUsedResolution = time.Minute
+
+var (
+	ErrInvalidAPIKey        = errors.New("clé d'API invalide, expirée ou révoquée")
+	ErrAPIKeyScopeForbidden = errors.New("le scope admin est réservé aux administrateurs")
+	ErrAPIKeyExpiryInPast   = errors.New("la date d'expiration doit être dans le futur")
+	ErrTooManyAPIKeys       = fmt.Errorf("nombre maximal de clés d'API actives atteint (%d)", MaxActiveAPIKeysPerUser)
+)
+
+type APIKeyService interface {
+	// CreateAPIKey retourne la clé enregistrée et sa valeur complète, affichée une seule fois
+	CreateAPIKey(ctx context.Context, userID uuid.UUID, request models.CreateAPIKeyRequest) (*models.APIKey, string, error)
+	ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) error
+	// AuthenticateAPIKey vérifie la clé et retourne l'identité correspondante (scopes de la clé)
+	AuthenticateAPIKey(ctx context.Context, rawKey string) (utils.Principal, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo repositories.APIKeyRepository
+	userRepo   repositories.UserRepository
+}
+
+func NewAPIKeyService(apiKeyRepo repositories.APIKeyRepository, userRepo repositories.UserRepository) APIKeyService {
+	if apiKeyRepo == nil || userRepo == nil {
+		log.Fatal("ERREUR FATALE: Dépendances manquantes lors de la création de APIKeyService")
+	}
+	return &apiKeyService{apiKeyRepo: apiKeyRepo, userRepo: userRepo}
+}
+
+// IsAPIKeyToken distingue une clé d'API d'un JWT dans l'en-tête Authorization
+func IsAPIKeyToken(token string) bool {
+	return strings.HasPrefix(token, APIKeyTokenPrefix)
+}
+
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, userID uuid.UUID, request models.CreateAPIKeyRequest) (*models.APIKey, string, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	scopes := uniqueScopes(request.Scopes)
+	for _, scope := range scopes {
+		if scope == models.APIKeyScopeAdmin && user.Role != models.RoleAdmin {
+			return nil, "", ErrAPIKeyScopeForbidden
+		}
+	}
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return nil, "", ErrAPIKeyExpiryInPast
+	}
+
+	existing, err := s.apiKeyRepo.ListAPIKeysByUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	active := 0
+	for i := range existing {
+		if existing[i].IsActive(time.Now()) {
+			active++
+		}
+	}
+	if active >= MaxActiveAPIKeysPerUser {
+		return nil, "", ErrTooManyAPIKeys
+	}
+
+	prefix, err := randomHex(6)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := newOpaqueToken()
+	if err != nil {
+		return nil, "", err
+	}
+	key := &models.APIKey{
+		UserID:     userID,
+		Name:       request.Name,
+		Prefix:     prefix,
+		SecretHash: hashOpaqueToken(secret),
+		Scopes:     strings.Join(scopes, " "),
+		ExpiresAt:  request.ExpiresAt,
+	}
+	if err := s.apiKeyRepo.CreateAPIKey(ctx, key); err != nil {
+		return nil, "", err
+	}
+	log.Printf("INFO: [UserID: %s] Clé d'API %s créée (scopes: %s)", userID, key.Prefix, key.Scopes)
+	return key, APIKeyTokenPrefix + prefix + "_" + secret, nil
+}
+
+func uniqueScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	unique := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			unique = append(unique, scope)
+		}
+	}
+	return unique
+}
+
+func (s *apiKeyService) ListAPIKeys(ctx context.Context, userID uuid.UUID) ([]models.APIKey, error) {
+	return s.apiKeyRepo.ListAPIKeysByUser(ctx, userID)
+}
+
+func (s *apiKeyService) RevokeAPIKey(ctx context.Context, userID, keyID uuid.UUID) error {
+	return s.apiKeyRepo.RevokeAPIKey(ctx, userID, keyID)
+}
+
+func (s *apiKeyService) AuthenticateAPIKey(ctx context.Context, rawKey string) (utils.Principal, error) {
+	// Format : es_<prefix>_<secret> (le secret base64url peut lui-même contenir "_")
+	parts := strings.SplitN(strings.TrimPrefix(rawKey, APIKeyTokenPrefix), "_", 2)
+	if !IsAPIKeyToken(rawKey) || len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return utils.Principal{}, ErrInvalidAPIKey
+	}
+	key, err := s.apiKeyRepo.FindAPIKeyByPrefix(ctx, parts[0])
+	if err != nil {
+		return utils.Principal{}, err
+	}
+	now := time.Now()
+	if key == nil || !key.IsActive(now) {
+		return utils.Principal{}, ErrInvalidAPIKey
+	}
+	if subtle.ConstantTimeCompare([]byte(hashOpaqueToken(parts[1])), []byte(key.SecretHash)) != 1 {
+		return utils.Principal{}, ErrInvalidAPIKey
+	}
+
+	// Le rôle est relu à chaque requête : un admin rétrogradé perd aussitôt le scope admin de ses clés
+	user, err := s.userRepo.FindByID(ctx, key.UserID)
+	if err != nil {
+		return utils.Principal{}, ErrInvalidAPIKey
+	}
+	principal := utils.Principal{
+		UserID:   user.ID,
+		Role:     models.RoleUser,
+		TokenID:  "apikey:" + key.ID.String(),
+		APIKeyID: key.ID,
+		Scopes:   key.ScopeList(),
+	}
+	if key.ExpiresAt != nil {
+		principal.ExpiresAt = *key.ExpiresAt
+	}
+	if user.Role == models.RoleAdmin && principal.HasScope(models.APIKeyScopeAdmin) {
+		principal.Role = models.RoleAdmin
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > lastUsedResolution {
+		if err := s.apiKeyRepo.TouchAPIKey(ctx, key.ID, now); err != nil {
+			log.Printf("WARN: %v", err)
+		}
+	}
+	return principal, nil
+}
@@ -4,9 +4,12 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"net"
+	"slices"
+	"strings"
 	"time"
 
-	// "errors" // errors n'est plus utilisé directement ici pour les API Keys
 	"fmt"
 	"log"
 
@@ -21,66 +24,173 @@ import (
 )
 
 type CommentService interface {
-	FindAll() ([]models.Comment, error)
-	FindByID(id uint) (*models.Comment, error)
+	FindAll(ctx context.Context) ([]models.Comment, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*models.Comment, error)
 	AnalyzeAndSaveYouTubeComments(ctx context.Context, userID uuid.UUID, videoID string) (*models.Insight, error)
+	// GetOrAnalyzeYouTubeComments retourne le dernier insight s'il est encore frais, sinon relance l'analyse.
+	// Le booléen indique si l'insight retourné provient du cache.
+	GetOrAnalyzeYouTubeComments(ctx context.Context, userID uuid.UUID, videoID string, policy FreshnessPolicy) (*models.Insight, bool, error)
+	// Drain refuse les nouvelles analyses (ErrShuttingDown) et attend la fin de celles en cours,
+	// au plus jusqu'à l'expiration de ctx (les analyses restantes sont alors annulées)
+	Drain(ctx context.Context) error
+}
+
+// DefaultInsightMaxAge est l'âge maximal d'un insight servi depuis la base sans nouvelle analyse
+const DefaultInsightMaxAge = 24 * time.Hour
+
+// FreshnessPolicy contrôle la réutilisation d'un insight déjà calculé
+type FreshnessPolicy struct {
+	MaxAge time.Duration // 0 = DefaultInsightMaxAge
+	Force  bool          // true = toujours relancer l'analyse
+}
+
+// IsFresh indique si l'insight peut être servi sans nouvelle analyse
+func (p FreshnessPolicy) IsFresh(insight *models.Insight, now time.Time) bool {
+	if p.Force || insight == nil {
+		return false
+	}
+	maxAge := p.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultInsightMaxAge
+	}
+	return now.Sub(insight.CreatedAt) < maxAge
+}
+
+// AnalysisOptions règle le pipeline d'analyse (voir config.Load)
+type AnalysisOptions struct {
+	MaxComments   int64             // commentaires récupérés par analyse
+	ChunkSize     int               // commentaires par appel Groq
+	ChunkDelay    time.Duration     // pause entre deux lots (limites de tokens par minute)
+	DefaultMaxAge time.Duration     // âge max d'un insight servi sans nouvelle analyse
+	Merge         utils.MergeLimits // taille des listes de l'insight fusionné
+	// MetaSummary active l'étape de réduction : sentiment et résumé globaux rédigés par le modèle
+	// à partir de tous les lots (sinon : heuristique de MergeParsedInsights)
+	MetaSummary bool
+	// OfflineFallback : un lot dont l'analyse par le modèle échoue est analysé hors ligne (lexique)
+	// au lieu d'être ignoré
+	OfflineFallback bool
+	// OfflinePlans : plans (models.PlanFree...) dont les analyses sont toujours faites hors ligne
+	OfflinePlans []string
+}
+
+var DefaultAnalysisOptions = AnalysisOptions{
+	MaxComments:     2000,
+	ChunkSize:       50,
+	ChunkDelay:      500 * time.Millisecond,
+	DefaultMaxAge:   DefaultInsightMaxAge,
+	Merge:           utils.DefaultMergeLimits,
+	MetaSummary:     false, // un appel au modèle de plus par analyse, soumis aux mêmes limites de tokens
+	OfflineFallback: true,
 }
 
 type commentService struct {
-	commentRepo    repositories.CommentRepository // Conservé si FindAll/FindByID sont utilisés
-	insightRepo    repositories.InsightRepository  // Injection du repo Insight
-	youtubeAdapter YouTubeAdapter                  // Injection de l'adapter YouTube
-	groqAdapter    GroqAdapter                     // Injection de l'adapter Groq
-	transcriptUtil TranscriptUtil                  // Injection de l'utilitaire de transcription
+	commentRepo      repositories.CommentRepository      // Commentaires analysés (enregistrés avec l'insight)
+	insightRepo      repositories.InsightRepository      // Injection du repo Insight
+	transactor       repositories.Transactor             // Commentaires + insight enregistrés atomiquement
+	subscriptionRepo repositories.SubscriptionRepository // Plan de l'utilisateur (AnalysisOptions.OfflinePlans)
+	youtubeAdapter   YouTubeAdapter                      // Injection de l'adapter YouTube
+	groqAdapter      GroqAdapter                         // Injection de l'adapter Groq
+	offlineAnalyzer  GroqAdapter                         // Analyse hors ligne : repli et plans sans IA
+	transcriptUtil   TranscriptUtil                      // Injection de l'utilitaire de transcription
+	alertService     AlertService                        // Évaluation des règles d'alerte (optionnel)
+	options          AnalysisOptions
+	jobs             *jobTracker // analyses en cours (arrêt gracieux)
 }
 
 func NewCommentService(
 	commentRepo repositories.CommentRepository,
 	insightRepo repositories.InsightRepository,
+	transactor repositories.Transactor,
+	subscriptionRepo repositories.SubscriptionRepository,
 	youtubeAdapter YouTubeAdapter,
 	groqAdapter GroqAdapter,
+	offlineAnalyzer GroqAdapter,
 	transcriptUtil TranscriptUtil,
+	alertService AlertService,
+	options AnalysisOptions,
 ) CommentService { // Retourne l'interface
 	// Validation rapide des dépendances critiques
-	if insightRepo == nil || youtubeAdapter == nil || groqAdapter == nil || transcriptUtil == nil {
+	if commentRepo == nil || insightRepo == nil || transactor == nil || subscriptionRepo == nil || youtubeAdapter == nil || groqAdapter == nil || offlineAnalyzer == nil || transcriptUtil == nil {
 		log.Fatal("ERREUR FATALE: Dépendances manquantes lors de la création de CommentService")
 	}
 	return &commentService{
-		commentRepo:    commentRepo,
-		insightRepo:    insightRepo,
-		youtubeAdapter: youtubeAdapter,
-		groqAdapter:    groqAdapter,
-		transcriptUtil: transcriptUtil,
+		commentRepo:      commentRepo,
+		insightRepo:      insightRepo,
+		transactor:       transactor,
+		subscriptionRepo: subscriptionRepo,
+		youtubeAdapter:   youtubeAdapter,
+		groqAdapter:      groqAdapter,
+		offlineAnalyzer:  offlineAnalyzer,
+		transcriptUtil:   transcriptUtil,
+		alertService:     alertService,
+		options:          options,
+		jobs:             newJobTracker(),
 	}
 }
 
 // --- Méthodes existantes (FindAll, FindByID) ---
 
-func (s *commentService) FindAll() ([]models.Comment, error) {
-	if s.commentRepo == nil {
-		return nil, fmt.Errorf("CommentRepository non initialisé dans CommentService")
-	}
-	return s.commentRepo.FindAllComment()
+func (s *commentService) FindAll(ctx context.Context) ([]models.Comment, error) {
+	return s.commentRepo.FindAllComment(ctx)
+}
+
+func (s *commentService) FindByID(ctx context.Context, id uuid.UUID) (*models.Comment, error) {
+	return s.commentRepo.FindCommentByID(ctx, id)
 }
 
-func (s *commentService) FindByID(id uint) (*models.Comment, error) {
-	if s.commentRepo == nil {
-		return nil, fmt.Errorf("CommentRepository non initialisé dans CommentService")
+func (s *commentService) GetOrAnalyzeYouTubeComments(ctx context.Context, userID uuid.UUID, videoID string, policy FreshnessPolicy) (*models.Insight, bool, error) {
+	if policy.MaxAge <= 0 {
+		policy.MaxAge = s.options.DefaultMaxAge
 	}
-	return s.commentRepo.FindCommentByID(id)
+	if !policy.Force {
+		existing, err := s.insightRepo.GetInsightByVideoID(ctx, userID, videoID)
+		if err != nil {
+			// Le cache est une optimisation : en cas d'erreur on relance simplement l'analyse
+			log.Printf("WARN: [UserID: %s] Échec lecture insight en cache pour videoID %s: %v", userID, videoID, err)
+		} else if policy.IsFresh(existing, time.Now()) {
+			log.Printf("INFO: [UserID: %s] Insight en cache servi pour videoID %s (ID: %s, créé le %s)", userID, videoID, existing.ID, existing.CreatedAt.Format(time.RFC3339))
+			return existing, true, nil
+		}
+	}
+
+	insight, err := s.AnalyzeAndSaveYouTubeComments(ctx, userID, videoID)
+	return insight, false, err
+}
+
+func (s *commentService) Drain(ctx context.Context) error {
+	return s.jobs.drain(ctx)
 }
 
 func (s *commentService) AnalyzeAndSaveYouTubeComments(ctx context.Context, userID uuid.UUID, videoID string) (*models.Insight, error) {
+	ctx, finish, err := s.jobs.start(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+	return s.analyzeAndSave(ctx, userID, videoID)
+}
+
+func (s *commentService) analyzeAndSave(ctx context.Context, userID uuid.UUID, videoID string) (*models.Insight, error) {
 
 	// --- Étape 1: Récupération des commentaires ---
 	log.Printf("INFO: [UserID: %s] Récupération des commentaires pour videoID: %s", userID, videoID)
 	// Note: Fetching 100 comments increases the chance of needing chunking.
-	maxCommentsToFetch := int64(2000) // Configurable ?
+	maxCommentsToFetch := s.options.MaxComments
 	commentsData, err := s.youtubeAdapter.GetComments(ctx, videoID, maxCommentsToFetch)
 	if err != nil { return nil, fmt.Errorf("échec récupération commentaires YouTube: %w", err) }
 	if len(commentsData) == 0 { return nil, fmt.Errorf("aucun commentaire trouvé pour videoID %s", videoID) }
 	log.Printf("INFO: [UserID: %s] %d commentaires récupérés pour videoID: %s", userID, len(commentsData), videoID)
 
+	// Plan sans analyse IA : tout le pipeline passe par l'analyse hors ligne
+	analyzer, offlineOnly := s.groqAdapter, s.offlineOnly(ctx, userID)
+	if offlineOnly {
+		analyzer = s.offlineAnalyzer
+		log.Printf("INFO: [UserID: %s] Plan sans analyse IA : analyse hors ligne pour videoID: %s", userID, videoID)
+	}
+
+
+	// Groq injoignable (timeout, erreur réseau) : le reste de l'analyse ne l'attend plus
+	modelUnreachable := false
 
 	// --- Étape 2: Récupération et Résumé de la Transcription (Appel IA Séparé) ---
 	log.Printf("INFO: [UserID: %s] Récupération transcription brute pour videoID: %s", userID, videoID)
@@ -92,10 +202,11 @@ func (s *commentService) AnalyzeAndSaveYouTubeComments(ctx context.Context, user
 		log.Printf("INFO: [UserID: %s] Transcription brute récupérée. Génération du résumé...", userID)
 		// Tronquer AVANT de résumer si trop long pour l'input de SummarizeTranscript
 		// transcriptToSummarize := utils.TruncateTextByWords(rawTranscript, 15000) // Exemple
-		summary, summaryErr := s.groqAdapter.SummarizeTranscript(ctx, rawTranscript) // Utiliser rawTranscript (ou tronqué si besoin)
+		summary, summaryErr := analyzer.SummarizeTranscript(ctx, rawTranscript) // Utiliser rawTranscript (ou tronqué si besoin)
 		if summaryErr != nil {
 			log.Printf("WARN: [UserID: %s] Échec génération résumé transcript: %v", userID, summaryErr)
 			transcriptSummary = "Résumé non généré (erreur IA)."
+			modelUnreachable = !offlineOnly && isTransportError(summaryErr)
 		} else {
 			transcriptSummary = summary
 			log.Printf("INFO: [UserID: %s] Résumé transcript généré.", userID)
@@ -113,8 +224,9 @@ func (s *commentService) AnalyzeAndSaveYouTubeComments(ctx context.Context, user
 
 
 	// --- Étape 3: Chunking et Analyse des Commentaires ---
-	chunkSize := 50 // <-- AJUSTER cette valeur (nombre de commentaires par appel Groq)
+	chunkSize := s.options.ChunkSize // nombre de commentaires par appel Groq
 	var allParsedInsights []*utils.ParsedInsight // Pour stocker les résultats de chaque chunk
+	offlineChunks := 0                           // lots analysés hors ligne (moteur de l'insight)
 	totalChunks := (len(commentsData) + chunkSize - 1) / chunkSize
 
 	log.Printf("INFO: [UserID: %s] Début de l'analyse des commentaires par lots (taille: %d, total: %d) pour videoID: %s", userID, chunkSize, totalChunks, videoID)
@@ -127,19 +239,35 @@ func (s *commentService) AnalyzeAndSaveYouTubeComments(ctx context.Context, user
 		commentChunk := commentsData[i:end] // Le lot actuel de commentaires
 		currentChunkNum := (i / chunkSize) + 1
 
-		// Formatage des commentaires pour CE lot
+		// Formatage des commentaires pour CE lot, numérotés pour la section de polarité
 		var chunkContents []string
-		for _, c := range commentChunk {
+		for n, c := range commentChunk {
 			chunkContents = append(chunkContents, fmt.Sprintf(
-				"Auteur: %s | Date: %s | Commentaire: \"%s\"",
-				c.Author, c.Date.Format("2006-01-02"), c.Content,
+				"[%d] Auteur: %s | Date: %s | Commentaire: \"%s\"",
+				n+1, c.Author, c.Date.Format("2006-01-02"), c.Content,
 			))
 		}
 
 		log.Printf("INFO: [UserID: %s] Analyse du lot %d/%d (taille %d)...", userID, currentChunkNum, totalChunks, len(chunkContents))
 
-		// Appel à Groq pour CE LOT avec le contexte transcript (tronqué)
-		markdownChunkResult, err := s.groqAdapter.AnalyzeComments(ctx, chunkContents, rawTranscript)
+		// Appel à Groq pour CE LOT avec le contexte transcript (tronqué) ; directement hors ligne
+		// si Groq n'a pas répondu à un appel précédent (chaque lot attendrait sinon le timeout)
+		chunkAnalyzer, offlineChunk := analyzer, offlineOnly
+		if modelUnreachable && s.options.OfflineFallback {
+			chunkAnalyzer, offlineChunk = s.offlineAnalyzer, true
+		}
+		markdownChunkResult, err := chunkAnalyzer.AnalyzeComments(ctx, chunkContents, rawTranscript)
+		if err != nil && !offlineChunk && isTransportError(err) {
+			modelUnreachable = true
+		}
+		if err != nil && !offlineChunk && s.options.OfflineFallback {
+			log.Printf("WARN: [UserID: %s] Échec analyse du lot %d/%d pour videoID %s: %v. Analyse hors ligne du lot.", userID, currentChunkNum, totalChunks, videoID, err)
+			if modelUnreachable && currentChunkNum < totalChunks {
+				log.Printf("WARN: [UserID: %s] Groq injoignable : les %d lots restants pour videoID %s sont analysés hors ligne.", userID, totalChunks-currentChunkNum, videoID)
+			}
+			markdownChunkResult, err = s.offlineAnalyzer.AnalyzeComments(ctx, chunkContents, rawTranscript)
+			offlineChunk = true
+		}
 		if err != nil {
 			// Que faire si un lot échoue ? Logguer et continuer ? Ou échouer tout ?
 			// Pour l'instant, on loggue et on continue au lot suivant.
@@ -151,14 +279,23 @@ func (s *commentService) AnalyzeAndSaveYouTubeComments(ctx context.Context, user
 		parsedChunk := utils.ParseInsightResponse(markdownChunkResult)
 		if parsedChunk != nil { // Vérifier si le parsing a réussi
 			allParsedInsights = append(allParsedInsights, parsedChunk)
+			if offlineChunk {
+				offlineChunks++
+			}
+			for _, warning := range parsedChunk.Warnings {
+				log.Printf("WARN: [UserID: %s] Lot %d/%d pour videoID %s: %s", userID, currentChunkNum, totalChunks, videoID, warning)
+			}
+			if unknown := applyCommentScores(commentChunk, parsedChunk.CommentScores); unknown > 0 {
+				log.Printf("WARN: [UserID: %s] Lot %d/%d pour videoID %s: %d polarité(s) pour un numéro de commentaire inconnu", userID, currentChunkNum, totalChunks, videoID, unknown)
+			}
 			log.Printf("INFO: [UserID: %s] Lot %d/%d analysé et parsé avec succès.", userID, currentChunkNum, totalChunks)
 		} else {
 			log.Printf("WARN: [UserID: %s] Échec parsing du résultat du lot %d/%d pour videoID %s. Lot ignoré.", userID, currentChunkNum, totalChunks, videoID)
 		}
 
-		// Optionnel: Pause pour éviter de surcharger les limites TPM trop rapidement
-        if totalChunks > 1 && currentChunkNum < totalChunks { // Ne pas attendre après le dernier chunk
-		    time.Sleep(500 * time.Millisecond) // Attente de 500ms (configurable !)
+		// Optionnel: Pause pour éviter de surcharger les limites TPM trop rapidement (inutile hors ligne)
+        if !offlineChunk && totalChunks > 1 && currentChunkNum < totalChunks { // Ne pas attendre après le dernier chunk
+		    time.Sleep(s.options.ChunkDelay)
         }
 	} // Fin de la boucle des chunks
 
@@ -171,7 +308,31 @@ func (s *commentService) AnalyzeAndSaveYouTubeComments(ctx context.Context, user
 	}
 
 	log.Printf("INFO: [UserID: %s] Fusion des résultats de %d lots analysés pour videoID: %s", userID, len(allParsedInsights), videoID)
-	finalParsedInsight := utils.MergeParsedInsights(allParsedInsights) // Appel de la fonction de fusion (à définir ci-dessous)
+	finalParsedInsight := utils.MergeParsedInsights(allParsedInsights, s.options.Merge)
+	// Mots-clés du modèle complétés par ceux extraits des commentaires, classés par nombre de
+	// commentaires qui les mentionnent, pondéré par leur rareté (voir utils.RankKeywords)
+	commentTexts := make([]string, len(commentsData))
+	for i, c := range commentsData {
+		commentTexts[i] = c.Content
+	}
+	keywordFrequencies := utils.RankKeywords(finalParsedInsight.Keywords, commentTexts, s.options.Merge.MaxKeywords)
+	finalParsedInsight.Keywords = utils.KeywordNames(keywordFrequencies)
+	engine := models.AnalysisEngineLLM
+	switch {
+	case offlineChunks == len(allParsedInsights):
+		engine = models.AnalysisEngineLexicon
+	case offlineChunks > 0:
+		engine = models.AnalysisEngineMixed
+	}
+	// Un seul lot : son résumé couvre déjà tous les commentaires
+	if s.options.MetaSummary && len(allParsedInsights) > 1 {
+		// Aucun lot analysé par le modèle, ou modèle injoignable : la synthèse non plus
+		summarizer := s.groqAdapter
+		if engine == models.AnalysisEngineLexicon || modelUnreachable {
+			summarizer = s.offlineAnalyzer
+		}
+		s.applyMetaSummary(ctx, summarizer, userID, videoID, allParsedInsights, finalParsedInsight, len(commentsData))
+	}
 
 
 	// --- Étape 5: Mapping vers models.Insight (utilise finalParsedInsight) ---
@@ -182,6 +343,16 @@ func (s *commentService) AnalyzeAndSaveYouTubeComments(ctx context.Context, user
 		Sentiment:         finalParsedInsight.Sentiment, // Sentiment issu de la fusion
 		Summary:           finalParsedInsight.Summary,   // Résumé issu de la fusion
 		TranscriptSummary: transcriptSummary,          // Résumé de la transcription (fait séparément)
+		ChannelID:         commentsData[0].ChannelID,
+		CommentCount:      len(commentsData),
+		NegativeCount:     finalParsedInsight.NegativeCount,
+		SentimentStats:    utils.ComputeSentimentStats(commentsData),
+		AnalysisEngine:    engine,
+	}
+	for _, c := range commentsData {
+		if c.ReplyCount == 0 && strings.Contains(c.Content, "?") {
+			newInsight.UnansweredQuestions++
+		}
 	}
 	marshalToJson := func(fieldName string, data interface{}) datatypes.JSON { /* ... (helper identique) ... */
         bytes, err := json.Marshal(data)
@@ -193,15 +364,84 @@ func (s *commentService) AnalyzeAndSaveYouTubeComments(ctx context.Context, user
 	newInsight.QuestionComments = marshalToJson("QuestionComments", finalParsedInsight.QuestionComments)
 	newInsight.FeedbackComments = marshalToJson("FeedbackComments", finalParsedInsight.FeedbackComments)
 	newInsight.Keywords = marshalToJson("Keywords", finalParsedInsight.Keywords)
+	newInsight.KeywordFrequencies = marshalToJson("KeywordFrequencies", keywordFrequencies)
 
 
-	// --- Étape 6: Sauvegarde en base ---
-	log.Printf("INFO: [UserID: %s] Sauvegarde de l'insight fusionné en base pour videoID: %s", userID, videoID)
-	err = s.insightRepo.CreateInsight(ctx, newInsight)
+	// --- Étape 6: Sauvegarde en base (commentaires et insight dans la même transaction) ---
+	log.Printf("INFO: [UserID: %s] Sauvegarde des commentaires et de l'insight fusionné en base pour videoID: %s", userID, videoID)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.commentRepo.ReplaceVideoComments(ctx, userID, videoID, commentsData); err != nil {
+			return fmt.Errorf("échec sauvegarde des commentaires: %w", err)
+		}
+		return s.insightRepo.CreateInsight(ctx, newInsight)
+	})
 	if err != nil { return nil, fmt.Errorf("échec sauvegarde insight fusionné en base: %w", err) }
 
 
-	// --- Étape 7: Retourner l'insight ---
+	// --- Étape 7: Évaluation des règles d'alerte (n'échoue jamais l'analyse) ---
+	if s.alertService != nil {
+		if _, alertErr := s.alertService.EvaluateInsight(ctx, newInsight); alertErr != nil {
+			log.Printf("WARN: [UserID: %s] Échec évaluation des règles d'alerte pour videoID %s: %v", userID, videoID, alertErr)
+		}
+	}
+
+
+	// --- Étape 8: Retourner l'insight ---
 	log.Printf("INFO: [UserID: %s] Insight fusionné sauvegardé avec succès pour videoID %s. ID: %s", userID, videoID, newInsight.ID)
 	return newInsight, nil
 }
+
+// applyMetaSummary remplace le sentiment et le résumé heuristiques de merged par une synthèse
+// globale du modèle. En cas d'échec, merged est laissé tel quel : l'analyse n'échoue jamais ici.
+func (s *commentService) applyMetaSummary(ctx context.Context, summarizer GroqAdapter, userID uuid.UUID, videoID string, partials []*utils.ParsedInsight, merged *utils.ParsedInsight, commentCount int) {
+	input := utils.NewMetaSummaryInput(partials, merged, commentCount)
+	if len(input.ChunkSummaries) == 0 {
+		return
+	}
+	raw, err := summarizer.SummarizeInsights(ctx, input)
+	if err == nil {
+		var sentiment, summary string
+		if sentiment, summary, err = utils.ParseMetaSummary(raw); err == nil {
+			merged.Sentiment, merged.Summary = sentiment, summary
+			log.Printf("INFO: [UserID: %s] Synthèse globale de %d lots générée pour videoID %s.", userID, len(input.ChunkSummaries), videoID)
+			return
+		}
+	}
+	log.Printf("WARN: [UserID: %s] Échec de la synthèse globale pour videoID %s: %v. Résumé heuristique conservé.", userID, videoID, err)
+}
+
+// offlineOnly indique si le plan de l'utilisateur exclut l'analyse IA (AnalysisOptions.OfflinePlans).
+// Si le plan ne peut pas être lu, l'analyse est faite hors ligne : pas d'appel facturé sans droit vérifié.
+func (s *commentService) offlineOnly(ctx context.Context, userID uuid.UUID) bool {
+	if len(s.options.OfflinePlans) == 0 {
+		return false
+	}
+	plan, err := s.subscriptionRepo.GetActivePlan(ctx, userID)
+	if err != nil {
+		log.Printf("WARN: [UserID: %s] Échec lecture du plan: %v. Analyse hors ligne.", userID, err)
+		return true
+	}
+	return slices.Contains(s.options.OfflinePlans, plan)
+}
+
+// isTransportError distingue une panne de Groq (timeout, réseau, annulation) d'une réponse en
+// erreur (quota, requête refusée) : seule la première justifie de ne plus l'appeler pendant l'analyse
+func isTransportError(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || errors.As(err, &netErr)
+}
+
+// applyCommentScores reporte la polarité de chaque commentaire du lot (numérotés à partir de 1)
+// et retourne le nombre de numéros hors du lot
+func applyCommentScores(chunk []models.Comment, scores map[int]utils.CommentScore) (unknown int) {
+	for index, score := range scores {
+		if index < 1 || index > len(chunk) {
+			unknown++
+			continue
+		}
+		polarity := score.Polarity
+		chunk[index-1].Polarity = &polarity
+		chunk[index-1].SentimentLabel = score.Label
+	}
+	return unknown
+}
@@ -0,0 +1,49 @@
+// internal/services/health_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/repositories"
+)
+
+// Délai maximal du ping de la base lors d'une sonde de readiness
+const readinessTimeout = 2 * time.Second
+
+var ErrDraining = errors.New("arrêt en cours")
+
+type HealthService interface {
+	// Ready retourne nil si l'instance peut recevoir du trafic (base joignable, pas d'arrêt en cours)
+	Ready(ctx context.Context) error
+	// SetDraining bascule la readiness en échec : le load balancer retire l'instance avant l'arrêt
+	SetDraining()
+}
+
+type healthService struct {
+	healthRepo repositories.HealthRepository
+	draining   atomic.Bool
+}
+
+func NewHealthService(healthRepo repositories.HealthRepository) HealthService {
+	if healthRepo == nil {
+		log.Fatal("ERREUR FATALE: HealthRepository manquant lors de la création de HealthService")
+	}
+	return &healthService{healthRepo: healthRepo}
+}
+
+func (s *healthService) Ready(ctx context.Context) error {
+	if s.draining.Load() {
+		return ErrDraining
+	}
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+	return s.healthRepo.Ping(ctx)
+}
+
+func (s *healthService) SetDraining() {
+	s.draining.Store(true)
+}
@@ -0,0 +1,49 @@
+// internal/services/helpers_test.go
+package services
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/Azertdev/FiberTest/internal/database"
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+)
+
+// openTestRepositories ouvre une base SQLite jetable et retourne ses repositories
+func openTestRepositories(t *testing.T) (*gorm.DB, repositories.AllRepository) {
+	t.Helper()
+	db, err := database.Open(database.DriverSQLite, filepath.Join(t.TempDir(), "services.db"))
+	if err != nil {
+		t.Fatalf("ouverture de la base: %v", err)
+	}
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	if _, err := database.Migrate(context.Background(), db); err != nil {
+		t.Fatalf("migration: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	return db, repositories.NewAllRepository(db)
+}
+
+// createTestUser insère un utilisateur minimal (le mot de passe n'est pas haché : inutile ici)
+func createTestUser(t *testing.T, repos repositories.AllRepository, username string, verified bool) *models.User {
+	t.Helper()
+	user := &models.User{Username: username, Email: username + "@example.com", Password: "not-a-hash"}
+	if verified {
+		verifiedAt := time.Now()
+		user.EmailVerifiedAt = &verifiedAt
+	}
+	if err := repos.UserRepository.Create(t.Context(), user); err != nil {
+		t.Fatalf("création de l'utilisateur %s: %v", username, err)
+	}
+	return user
+}
@@ -0,0 +1,210 @@
+// internal/services/insight_service.go
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+const (
+	DefaultInsightPageSize = 20
+	MaxInsightPageSize     = 100
+	MaxInsightExportBatch  = 50
+)
+
+// InsightService expose les Insights déjà calculés (lecture seule + suppression), sans relancer d'analyse payante
+type InsightService interface {
+	ListInsights(ctx context.Context, filter repositories.InsightFilter) ([]models.Insight, int64, error)
+	GetInsight(ctx context.Context, userID, insightID uuid.UUID) (*models.Insight, error)
+	GetVideoHistory(ctx context.Context, userID uuid.UUID, videoID string) ([]models.Insight, error)
+	DeleteInsight(ctx context.Context, userID, insightID uuid.UUID) error
+	// DiffVersions compare deux versions de l'insight d'une vidéo. toVersion <= 0 = dernière version,
+	// fromVersion <= 0 = version précédant toVersion.
+	DiffVersions(ctx context.Context, userID uuid.UUID, videoID string, fromVersion, toVersion int) (*InsightDiff, error)
+	// GetInsightsForExport retourne les insights demandés ; ErrInsightNotFound si l'un d'eux n'appartient pas à l'utilisateur
+	GetInsightsForExport(ctx context.Context, userID uuid.UUID, insightIDs []uuid.UUID) ([]models.Insight, error)
+	InsightOwner(ctx context.Context, insightID uuid.UUID) (uuid.UUID, error)
+}
+
+// ErrInsightNotFound est retournée quand un insight demandé n'existe pas pour l'utilisateur
+var ErrInsightNotFound = errors.New("insight introuvable")
+
+// ErrInsightVersionNotFound est retournée quand une des versions demandées n'existe pas
+var ErrInsightVersionNotFound = errors.New("version d'insight introuvable")
+
+// InsightDiff décrit l'évolution de la réception d'une vidéo entre deux analyses
+type InsightDiff struct {
+	VideoID             string    `json:"video_id"`
+	FromVersion         int       `json:"from_version"`
+	ToVersion           int       `json:"to_version"`
+	FromCreatedAt       time.Time `json:"from_created_at"`
+	ToCreatedAt         time.Time `json:"to_created_at"`
+	SentimentBefore     string    `json:"sentiment_before"`
+	SentimentAfter      string    `json:"sentiment_after"`
+	SentimentChanged    bool      `json:"sentiment_changed"`
+	ScoreBefore         float64   `json:"sentiment_score_before"`
+	ScoreAfter          float64   `json:"sentiment_score_after"`
+	CommentCountBefore  int       `json:"comment_count_before"`
+	CommentCountAfter   int       `json:"comment_count_after"`
+	NegativeCountBefore int       `json:"negative_count_before"`
+	NegativeCountAfter  int       `json:"negative_count_after"`
+	KeywordsAdded       []string  `json:"keywords_added"`
+	KeywordsRemoved     []string  `json:"keywords_removed"`
+	NewQuestions        []string  `json:"new_questions"`
+	NewNegativeComments []string  `json:"new_negative_comments"`
+}
+
+// NormalizePagination borne la page (>= 1) et la taille de page (1..MaxInsightPageSize)
+func NormalizePagination(page, pageSize int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = DefaultInsightPageSize
+	}
+	if pageSize > MaxInsightPageSize {
+		pageSize = MaxInsightPageSize
+	}
+	return page, pageSize
+}
+
+type insightService struct {
+	insightRepo repositories.InsightRepository
+}
+
+func NewInsightService(insightRepo repositories.InsightRepository) InsightService {
+	if insightRepo == nil {
+		log.Fatal("ERREUR FATALE: InsightRepository manquant lors de la création de InsightService")
+	}
+	return &insightService{insightRepo: insightRepo}
+}
+
+func (s *insightService) ListInsights(ctx context.Context, filter repositories.InsightFilter) ([]models.Insight, int64, error) {
+	filter.Page, filter.PageSize = NormalizePagination(filter.Page, filter.PageSize)
+	return s.insightRepo.ListInsights(ctx, filter)
+}
+
+// GetInsight retourne nil, nil si l'insight n'existe pas ou n'appartient pas à l'utilisateur
+func (s *insightService) GetInsight(ctx context.Context, userID, insightID uuid.UUID) (*models.Insight, error) {
+	return s.insightRepo.GetInsightByID(ctx, userID, insightID)
+}
+
+func (s *insightService) GetVideoHistory(ctx context.Context, userID uuid.UUID, videoID string) ([]models.Insight, error) {
+	return s.insightRepo.ListInsightsByVideo(ctx, userID, videoID)
+}
+
+func (s *insightService) DeleteInsight(ctx context.Context, userID, insightID uuid.UUID) error {
+	return s.insightRepo.DeleteInsight(ctx, userID, insightID)
+}
+
+func (s *insightService) DiffVersions(ctx context.Context, userID uuid.UUID, videoID string, fromVersion, toVersion int) (*InsightDiff, error) {
+	var to *models.Insight
+	var err error
+	if toVersion <= 0 {
+		to, err = s.insightRepo.GetInsightByVideoID(ctx, userID, videoID)
+	} else {
+		to, err = s.insightRepo.GetInsightVersion(ctx, userID, videoID, toVersion)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		return nil, ErrInsightVersionNotFound
+	}
+
+	if fromVersion <= 0 {
+		fromVersion = to.Version - 1
+	}
+	from, err := s.insightRepo.GetInsightVersion(ctx, userID, videoID, fromVersion)
+	if err != nil {
+		return nil, err
+	}
+	if from == nil {
+		return nil, ErrInsightVersionNotFound
+	}
+
+	diff := DiffInsights(from, to)
+	return &diff, nil
+}
+
+// DiffInsights compare deux insights d'une même vidéo (from = ancien, to = récent)
+func DiffInsights(from, to *models.Insight) InsightDiff {
+	fromKeywords := utils.DecodeStringList(from.Keywords)
+	toKeywords := utils.DecodeStringList(to.Keywords)
+
+	return InsightDiff{
+		VideoID:             to.VideoID,
+		FromVersion:         from.Version,
+		ToVersion:           to.Version,
+		FromCreatedAt:       from.CreatedAt,
+		ToCreatedAt:         to.CreatedAt,
+		SentimentBefore:     from.Sentiment,
+		SentimentAfter:      to.Sentiment,
+		SentimentChanged:    normalizeListItem(from.Sentiment) != normalizeListItem(to.Sentiment),
+		ScoreBefore:         from.SentimentStats.Score,
+		ScoreAfter:          to.SentimentStats.Score,
+		CommentCountBefore:  from.CommentCount,
+		CommentCountAfter:   to.CommentCount,
+		NegativeCountBefore: from.NegativeCount,
+		NegativeCountAfter:  to.NegativeCount,
+		KeywordsAdded:       listDifference(toKeywords, fromKeywords),
+		KeywordsRemoved:     listDifference(fromKeywords, toKeywords),
+		NewQuestions:        listDifference(utils.DecodeStringList(to.QuestionComments), utils.DecodeStringList(from.QuestionComments)),
+		NewNegativeComments: listDifference(utils.DecodeStringList(to.NegativeComments), utils.DecodeStringList(from.NegativeComments)),
+	}
+}
+
+// normalizeListItem sert de clé de comparaison (casse et espaces ignorés)
+func normalizeListItem(item string) string {
+	return strings.ToLower(strings.Join(strings.Fields(item), " "))
+}
+
+// listDifference retourne les éléments de a absents de b, dans l'ordre de a et sans doublon
+func listDifference(a, b []string) []string {
+	inB := make(map[string]bool, len(b))
+	for _, item := range b {
+		inB[normalizeListItem(item)] = true
+	}
+	result := []string{}
+	for _, item := range a {
+		key := normalizeListItem(item)
+		if key == "" || inB[key] {
+			continue
+		}
+		inB[key] = true // évite les doublons dans le résultat
+		result = append(result, item)
+	}
+	return result
+}
+
+func (s *insightService) GetInsightsForExport(ctx context.Context, userID uuid.UUID, insightIDs []uuid.UUID) ([]models.Insight, error) {
+	if len(insightIDs) > MaxInsightExportBatch {
+		return nil, fmt.Errorf("export limité à %d insights", MaxInsightExportBatch)
+	}
+	insights, err := s.insightRepo.GetInsightsByIDs(ctx, userID, insightIDs)
+	if err != nil {
+		return nil, err
+	}
+	unique := make(map[uuid.UUID]bool, len(insightIDs))
+	for _, id := range insightIDs {
+		unique[id] = true
+	}
+	if len(insights) != len(unique) {
+		return nil, ErrInsightNotFound
+	}
+	return insights, nil
+}
+
+func (s *insightService) InsightOwner(ctx context.Context, insightID uuid.UUID) (uuid.UUID, error) {
+	return s.insightRepo.GetInsightOwner(ctx, insightID)
+}
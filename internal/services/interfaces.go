@@ -4,20 +4,44 @@ import (
 	"context"
 	// "time"
 	"github.com/Azertdev/FiberTest/internal/models" // Adapt path if needed
+	"github.com/Azertdev/FiberTest/internal/utils"
+	"golang.org/x/oauth2"
 )
 
 // YouTubeAdapter defines the contract for fetching YouTube data.
 type YouTubeAdapter interface {
 	GetComments(ctx context.Context, videoID string, maxResults int64) ([]models.Comment, error) // Return models.Comment for simplicity now
+	GetMyVideos(ctx context.Context, tokenSource oauth2.TokenSource, maxResults int64) ([]models.YouTubeVideo, error)
 }
 
 // GroqAdapter defines the contract for interacting with the Groq API.
 type GroqAdapter interface {
 	AnalyzeComments(ctx context.Context, comments []string, videoTranscript string) (string, error)
 		SummarizeTranscript(ctx context.Context, transcript string) (string, error)
+	// SummarizeInsights produit la synthèse globale (sections 1 et 2) des analyses par lots
+	SummarizeInsights(ctx context.Context, input utils.MetaSummaryInput) (string, error)
 }
 
 // TranscriptUtil defines the contract for fetching video transcripts.
 type TranscriptUtil interface {
 	GetTranscript(ctx context.Context, videoID string) (string, error)
 }
+
+// NotificationChannel defines the contract for fanning out a notification (log, webhook, ...).
+type NotificationChannel interface {
+	Name() string
+	Send(ctx context.Context, notification models.Notification) error
+}
+
+// Mailer defines the contract for delivering transactional emails (verification, password reset).
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// OIDCProvider defines the contract for an OpenID Connect login provider (authorization code + PKCE).
+type OIDCProvider interface {
+	Name() string
+	AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error)
+	Exchange(ctx context.Context, code, verifier, nonce string) (*models.ExternalIdentity, error)
+	TokenSource(ctx context.Context, refreshToken string) (oauth2.TokenSource, error)
+}
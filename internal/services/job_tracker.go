@@ -0,0 +1,65 @@
+// internal/services/job_tracker.go
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrShuttingDown : le serveur s'arrête, aucun nouveau traitement n'est accepté
+var ErrShuttingDown = errors.New("arrêt du serveur en cours, réessayez dans quelques instants")
+
+// jobTracker suit les traitements longs (analyses) pour pouvoir les laisser finir à l'arrêt.
+// Le contexte d'un job est détaché de celui de la requête : fasthttp annule ce dernier dès le
+// début de l'arrêt, ce qui interrompait les analyses en cours. Il n'est annulé qu'à l'expiration
+// du délai de drain.
+type jobTracker struct {
+	mu       sync.Mutex
+	wg       sync.WaitGroup
+	draining bool
+	base     context.Context
+	cancel   context.CancelFunc
+}
+
+func newJobTracker() *jobTracker {
+	base, cancel := context.WithCancel(context.Background())
+	return &jobTracker{base: base, cancel: cancel}
+}
+
+// start enregistre un job. finish doit être appelé à la fin du traitement.
+func (t *jobTracker) start(parent context.Context) (ctx context.Context, finish func(), err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.draining {
+		return nil, nil, ErrShuttingDown
+	}
+	t.wg.Add(1)
+	ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+	stop := context.AfterFunc(t.base, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		t.wg.Done()
+	}, nil
+}
+
+// drain refuse les nouveaux jobs et attend les jobs en cours ; à l'expiration de ctx, ils sont annulés
+func (t *jobTracker) drain(ctx context.Context) error {
+	t.mu.Lock()
+	t.draining = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		t.cancel()
+		return ctx.Err()
+	}
+}
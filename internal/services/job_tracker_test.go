@@ -0,0 +1,71 @@
+// internal/services/job_tracker_test.go
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJobTrackerRefusesNewJobsOnceDraining(t *testing.T) {
+	tracker := newJobTracker()
+	if err := tracker.drain(t.Context()); err != nil {
+		t.Fatalf("drain sans job: %v", err)
+	}
+	if _, _, err := tracker.start(t.Context()); !errors.Is(err, ErrShuttingDown) {
+		t.Fatalf("job accepté pendant l'arrêt: %v", err)
+	}
+}
+
+func TestJobTrackerDrainWaitsForRunningJobs(t *testing.T) {
+	tracker := newJobTracker()
+	// Le job survit à l'annulation de la requête qui l'a lancé
+	requestCtx, cancelRequest := context.WithCancel(t.Context())
+	jobCtx, finish, err := tracker.start(requestCtx)
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	cancelRequest()
+
+	drained := make(chan error, 1)
+	go func() { drained <- tracker.drain(t.Context()) }()
+	select {
+	case err := <-drained:
+		t.Fatalf("drain terminé avant le job: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if jobCtx.Err() != nil {
+		t.Fatalf("job annulé pendant le drain: %v", jobCtx.Err())
+	}
+
+	finish()
+	select {
+	case err := <-drained:
+		if err != nil {
+			t.Fatalf("drain: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("drain toujours bloqué après la fin du job")
+	}
+}
+
+func TestJobTrackerDrainCancelsJobsWhenItsContextExpires(t *testing.T) {
+	tracker := newJobTracker()
+	jobCtx, finish, err := tracker.start(t.Context())
+	if err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer finish()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 20*time.Millisecond)
+	defer cancel()
+	if err := tracker.drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("drain: %v, attendu l'expiration du délai", err)
+	}
+	select {
+	case <-jobCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("job en cours non annulé à l'expiration du drain")
+	}
+}
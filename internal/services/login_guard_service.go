@@ -0,0 +1,214 @@
+// internal/services/login_guard_service.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+)
+
+// LoginGuardPolicy : seuils de la protection contre le brute-force.
+// Après DelayAfter échecs, chaque nouvel essai doit attendre BaseDelay, doublé à chaque échec (plafonné à MaxDelay).
+// Après MaxFailures échecs dans la fenêtre Window, la clé est verrouillée pendant LockoutDuration.
+// Les tentatives sont comptées avant la vérification du mot de passe (ReserveLogin) : des essais
+// concurrents ne peuvent pas tous passer avant que le premier échec soit enregistré.
+type LoginGuardPolicy struct {
+	Window          time.Duration
+	DelayAfter      int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	MaxUserFailures int // par nom d'utilisateur
+	MaxIPFailures   int // par adresse IP (un attaquant qui essaie plusieurs comptes)
+	LockoutDuration time.Duration
+}
+
+var DefaultLoginGuardPolicy = LoginGuardPolicy{
+	Window:          15 * time.Minute,
+	DelayAfter:      3,
+	BaseDelay:       time.Second,
+	MaxDelay:        30 * time.Second,
+	MaxUserFailures: 5,
+	MaxIPFailures:   20,
+	LockoutDuration: 15 * time.Minute,
+}
+
+// LoginThrottledError indique qu'une tentative de connexion est refusée avant même de vérifier le mot de passe
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginThrottledError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("connexion temporairement bloquée après trop d'échecs, réessayez dans %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("trop de tentatives de connexion, réessayez dans %s", e.RetryAfter.Round(time.Second))
+}
+
+type LoginGuardService interface {
+	// ReserveLogin compte la tentative pour l'utilisateur et l'IP avant la vérification du mot de
+	// passe ; retourne un *LoginThrottledError (tentative non comptée) si l'un d'eux doit attendre.
+	// Chaque réservation est suivie de RecordLoginFailure ou de RecordLoginSuccess.
+	ReserveLogin(ctx context.Context, username, ip string) error
+	RecordLoginFailure(ctx context.Context, username, ip string) error
+	RecordLoginSuccess(ctx context.Context, username, ip string) error
+	PurgeExpired(ctx context.Context) error
+}
+
+type loginGuardService struct {
+	attemptRepo         repositories.LoginAttemptRepository
+	userRepo            repositories.UserRepository
+	notificationService NotificationService
+	policy              LoginGuardPolicy
+	now                 func() time.Time
+}
+
+func NewLoginGuardService(attemptRepo repositories.LoginAttemptRepository, userRepo repositories.UserRepository, notificationService NotificationService, policy LoginGuardPolicy) LoginGuardService {
+	if attemptRepo == nil || userRepo == nil || notificationService == nil {
+		log.Fatal("ERREUR FATALE: Dépendances manquantes lors de la création de LoginGuardService")
+	}
+	return &loginGuardService{
+		attemptRepo:         attemptRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		policy:              policy,
+		now:                 time.Now,
+	}
+}
+
+func userAttemptKey(username string) string {
+	return "user:" + strings.ToLower(strings.TrimSpace(username))
+}
+
+func ipAttemptKey(ip string) string {
+	return "ip:" + ip
+}
+
+func (s *loginGuardService) ReserveLogin(ctx context.Context, username, ip string) error {
+	now := s.now()
+	windowStart := now.Add(-s.policy.Window)
+	keys := []struct {
+		key         string
+		maxFailures int
+	}{
+		{userAttemptKey(username), s.policy.MaxUserFailures},
+		{ipAttemptKey(ip), s.policy.MaxIPFailures},
+	}
+	var reserved []string
+	for _, k := range keys {
+		attempt, err := s.attemptRepo.ReserveLoginAttempt(ctx, k.key, now, windowStart)
+		if err != nil {
+			s.release(ctx, reserved)
+			return err
+		}
+		reserved = append(reserved, k.key)
+		if throttled := s.throttle(attempt, k.maxFailures, now); throttled != nil {
+			s.release(ctx, reserved)
+			return throttled
+		}
+	}
+	return nil
+}
+
+// throttle décide si la tentative réservée (attempt.Failures-ième de la fenêtre) doit attendre
+func (s *loginGuardService) throttle(attempt *models.LoginAttempt, maxFailures int, now time.Time) *LoginThrottledError {
+	if isLocked(attempt, now) {
+		return &LoginThrottledError{RetryAfter: attempt.LockedUntil.Sub(now), Locked: true}
+	}
+	// Tentatives concurrentes au-delà du maximum : les précédentes, en cours, vont verrouiller la clé
+	if attempt.Failures > maxFailures {
+		return &LoginThrottledError{RetryAfter: s.policy.LockoutDuration, Locked: true}
+	}
+	if nextAllowed := attempt.LastFailureAt.Add(s.delay(attempt.Failures - 1)); now.Before(nextAllowed) {
+		return &LoginThrottledError{RetryAfter: nextAllowed.Sub(now)}
+	}
+	return nil
+}
+
+// release annule les tentatives réservées ; un échec laisse au pire une tentative de trop dans la fenêtre
+func (s *loginGuardService) release(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.attemptRepo.ReleaseLoginAttempt(ctx, key); err != nil {
+			log.Printf("WARN: Échec annulation de la tentative de connexion %s: %v", key, err)
+		}
+	}
+}
+
+// delay retourne l'attente imposée après 'failures' échecs consécutifs
+func (s *loginGuardService) delay(failures int) time.Duration {
+	if failures < s.policy.DelayAfter {
+		return 0
+	}
+	delay := s.policy.BaseDelay
+	for i := s.policy.DelayAfter; i < failures && delay < s.policy.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.policy.MaxDelay {
+		delay = s.policy.MaxDelay
+	}
+	return delay
+}
+
+func (s *loginGuardService) RecordLoginFailure(ctx context.Context, username, ip string) error {
+	now := s.now()
+
+	userAttempt, err := s.attemptRepo.RecordLoginFailure(ctx, userAttemptKey(username), now)
+	if err != nil {
+		return err
+	}
+	if userAttempt.Failures >= s.policy.MaxUserFailures && !isLocked(userAttempt, now) {
+		if err := s.attemptRepo.LockLoginKey(ctx, userAttempt.AttemptKey, now.Add(s.policy.LockoutDuration)); err != nil {
+			return err
+		}
+		log.Printf("WARN: Connexion bloquée pour '%s' après %d échecs (dernière IP: %s)", username, userAttempt.Failures, ip)
+		s.notifyLockout(ctx, username, userAttempt.Failures, ip)
+	}
+
+	ipAttempt, err := s.attemptRepo.RecordLoginFailure(ctx, ipAttemptKey(ip), now)
+	if err != nil {
+		return err
+	}
+	if ipAttempt.Failures >= s.policy.MaxIPFailures && !isLocked(ipAttempt, now) {
+		if err := s.attemptRepo.LockLoginKey(ctx, ipAttempt.AttemptKey, now.Add(s.policy.LockoutDuration)); err != nil {
+			return err
+		}
+		log.Printf("WARN: Connexions bloquées depuis l'IP %s après %d échecs", ip, ipAttempt.Failures)
+	}
+	return nil
+}
+
+func isLocked(attempt *models.LoginAttempt, now time.Time) bool {
+	return attempt.LockedUntil != nil && now.Before(*attempt.LockedUntil)
+}
+
+// notifyLockout prévient le titulaire du compte ; un nom d'utilisateur inconnu est ignoré
+func (s *loginGuardService) notifyLockout(ctx context.Context, username string, failures int, ip string) {
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return
+	}
+	message := fmt.Sprintf("Connexion à votre compte bloquée pendant %s après %d tentatives échouées (dernière depuis l'adresse %s). Si ce n'était pas vous, changez votre mot de passe.",
+		s.policy.LockoutDuration, failures, ip)
+	if _, err := s.notificationService.Notify(ctx, user.ID, NotificationTypeAlert, message); err != nil {
+		log.Printf("WARN: [UserID: %s] Échec notification de verrouillage: %v", user.ID, err)
+	}
+}
+
+// RecordLoginSuccess remet à zéro le compteur du compte et annule la tentative réservée pour
+// l'IP (ses échecs précédents sont conservés : un attaquant ne doit pas pouvoir les effacer en se
+// connectant à son propre compte)
+func (s *loginGuardService) RecordLoginSuccess(ctx context.Context, username, ip string) error {
+	if err := s.attemptRepo.ResetLoginAttempts(ctx, userAttemptKey(username)); err != nil {
+		return err
+	}
+	return s.attemptRepo.ReleaseLoginAttempt(ctx, ipAttemptKey(ip))
+}
+
+func (s *loginGuardService) PurgeExpired(ctx context.Context) error {
+	return s.attemptRepo.PurgeLoginAttempts(ctx, s.now().Add(-s.policy.Window))
+}
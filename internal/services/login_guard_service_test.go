@@ -0,0 +1,173 @@
+// internal/services/login_guard_service_test.go
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azertdev/FiberTest/internal/repositories"
+)
+
+// testLoginGuard : garde en mémoire dont l'horloge est avancée par le test
+type testLoginGuard struct {
+	*loginGuardService
+	attempts repositories.LoginAttemptRepository
+	repos    repositories.AllRepository
+	clock    time.Time
+}
+
+func newTestLoginGuard(t *testing.T, policy LoginGuardPolicy) *testLoginGuard {
+	t.Helper()
+	_, repos := openTestRepositories(t)
+	attempts := repositories.NewMemoryLoginAttemptRepository()
+	notifications := NewNotificationService(repos.NotificationRepository, nil)
+	g := &testLoginGuard{attempts: attempts, repos: repos, clock: time.Now().Truncate(time.Second)}
+	g.loginGuardService = NewLoginGuardService(attempts, repos.UserRepository, notifications, policy).(*loginGuardService)
+	g.now = func() time.Time { return g.clock }
+	return g
+}
+
+func (g *testLoginGuard) advance(d time.Duration) { g.clock = g.clock.Add(d) }
+
+// fail réserve une tentative puis enregistre son échec
+func (g *testLoginGuard) fail(t *testing.T, username, ip string) {
+	t.Helper()
+	if err := g.ReserveLogin(t.Context(), username, ip); err != nil {
+		t.Fatalf("tentative refusée: %v", err)
+	}
+	if err := g.RecordLoginFailure(t.Context(), username, ip); err != nil {
+		t.Fatalf("RecordLoginFailure: %v", err)
+	}
+}
+
+func throttledBy(t *testing.T, err error) *LoginThrottledError {
+	t.Helper()
+	var throttled *LoginThrottledError
+	if !errors.As(err, &throttled) {
+		t.Fatalf("tentative non refusée: %v", err)
+	}
+	return throttled
+}
+
+var testGuardPolicy = LoginGuardPolicy{
+	Window:          15 * time.Minute,
+	DelayAfter:      2,
+	BaseDelay:       time.Second,
+	MaxDelay:        4 * time.Second,
+	MaxUserFailures: 5,
+	MaxIPFailures:   100,
+	LockoutDuration: 10 * time.Minute,
+}
+
+func TestLoginGuardDelaySchedule(t *testing.T) {
+	guard := &loginGuardService{policy: testGuardPolicy}
+	expected := map[int]time.Duration{0: 0, 1: 0, 2: time.Second, 3: 2 * time.Second, 4: 4 * time.Second, 5: 4 * time.Second, 20: 4 * time.Second}
+	for failures, delay := range expected {
+		if got := guard.delay(failures); got != delay {
+			t.Errorf("délai après %d échecs: %s, attendu %s", failures, got, delay)
+		}
+	}
+}
+
+func TestLoginGuardProgressiveDelayThenLockout(t *testing.T) {
+	g := newTestLoginGuard(t, testGuardPolicy)
+	ctx := t.Context()
+	user := createTestUser(t, g.repos, "alice", true)
+
+	g.fail(t, "alice", "10.0.0.1")
+	g.fail(t, "alice", "10.0.0.1")
+	// Deux échecs : une seconde d'attente ; la tentative refusée n'est pas comptée
+	if throttled := throttledBy(t, g.ReserveLogin(ctx, "alice", "10.0.0.1")); throttled.Locked || throttled.RetryAfter != time.Second {
+		t.Fatalf("délai inattendu: %+v", throttled)
+	}
+	if attempt, _ := g.attempts.GetLoginAttempt(ctx, userAttemptKey("alice")); attempt.Failures != 2 {
+		t.Fatalf("tentative refusée comptée: %d", attempt.Failures)
+	}
+	g.advance(time.Second)
+	g.fail(t, "alice", "10.0.0.1")
+	g.advance(2 * time.Second)
+	g.fail(t, "alice", "10.0.0.1")
+	g.advance(4 * time.Second)
+	g.fail(t, "alice", "10.0.0.1")
+
+	// Cinquième échec : compte verrouillé et titulaire prévenu
+	g.advance(time.Minute)
+	if throttled := throttledBy(t, g.ReserveLogin(ctx, "alice", "10.0.0.1")); !throttled.Locked {
+		t.Fatalf("compte non verrouillé: %+v", throttled)
+	}
+	notifications, err := g.repos.NotificationRepository.ListNotificationsByUser(ctx, user.ID, false)
+	if err != nil || len(notifications) != 1 || notifications[0].Type != NotificationTypeAlert {
+		t.Fatalf("notification de verrouillage: %v, %+v", err, notifications)
+	}
+
+	// Verrou expiré : nouvelle série
+	g.advance(testGuardPolicy.LockoutDuration)
+	if err := g.ReserveLogin(ctx, "alice", "10.0.0.1"); err != nil {
+		t.Fatalf("tentative après expiration du verrou: %v", err)
+	}
+	if attempt, _ := g.attempts.GetLoginAttempt(ctx, userAttemptKey("alice")); attempt.Failures != 1 || attempt.LockedUntil != nil {
+		t.Fatalf("compteur après expiration: %+v", attempt)
+	}
+}
+
+func TestLoginGuardWindowExpiry(t *testing.T) {
+	g := newTestLoginGuard(t, testGuardPolicy)
+	ctx := t.Context()
+	g.fail(t, "bob", "10.0.0.2")
+	g.fail(t, "bob", "10.0.0.2")
+
+	// Premier échec hors de la fenêtre : aucune attente, le compteur repart de zéro
+	g.advance(testGuardPolicy.Window + time.Second)
+	if err := g.ReserveLogin(ctx, "bob", "10.0.0.2"); err != nil {
+		t.Fatalf("tentative après la fenêtre: %v", err)
+	}
+	if attempt, _ := g.attempts.GetLoginAttempt(ctx, userAttemptKey("bob")); attempt.Failures != 1 {
+		t.Fatalf("compteur après la fenêtre: %d", attempt.Failures)
+	}
+}
+
+func TestLoginGuardSuccessResetsUserButKeepsIPFailures(t *testing.T) {
+	g := newTestLoginGuard(t, testGuardPolicy)
+	ctx := t.Context()
+	g.fail(t, "carol", "10.0.0.3")
+	if err := g.ReserveLogin(ctx, "carol", "10.0.0.3"); err != nil {
+		t.Fatalf("ReserveLogin: %v", err)
+	}
+	if err := g.RecordLoginSuccess(ctx, "carol", "10.0.0.3"); err != nil {
+		t.Fatalf("RecordLoginSuccess: %v", err)
+	}
+	if attempt, _ := g.attempts.GetLoginAttempt(ctx, userAttemptKey("carol")); attempt != nil {
+		t.Fatalf("compteur du compte conservé: %+v", attempt)
+	}
+	if attempt, _ := g.attempts.GetLoginAttempt(ctx, ipAttemptKey("10.0.0.3")); attempt == nil || attempt.Failures != 1 {
+		t.Fatalf("compteur de l'IP: %+v", attempt)
+	}
+}
+
+func TestLoginGuardConcurrentAttemptsCannotBypassTheLimit(t *testing.T) {
+	policy := testGuardPolicy
+	policy.DelayAfter = 100 // aucun délai : seul le plafond d'échecs limite les essais
+	policy.MaxUserFailures = 3
+	g := newTestLoginGuard(t, policy)
+
+	var allowed int
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if g.ReserveLogin(t.Context(), "dave", "10.0.0.4") == nil {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != policy.MaxUserFailures {
+		t.Fatalf("%d tentatives concurrentes acceptées, %d attendues", allowed, policy.MaxUserFailures)
+	}
+}
@@ -0,0 +1,74 @@
+// internal/services/notification_service.go
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+)
+
+// Types de notification (ENUM notification_type, voir models)
+const (
+	NotificationTypeAnalysis = models.NotificationTypeAnalysis
+	NotificationTypePayment  = models.NotificationTypePayment
+	NotificationTypeAlert    = models.NotificationTypeAlert
+)
+
+type NotificationService interface {
+	// Notify enregistre la notification puis la diffuse sur tous les canaux configurés
+	Notify(ctx context.Context, userID uuid.UUID, notificationType, message string) (*models.Notification, error)
+	ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]models.Notification, error)
+	MarkAsRead(ctx context.Context, userID, notificationID uuid.UUID) error
+	NotificationOwner(ctx context.Context, notificationID uuid.UUID) (uuid.UUID, error)
+}
+
+type notificationService struct {
+	notificationRepo repositories.NotificationRepository
+	channels         []NotificationChannel
+}
+
+func NewNotificationService(notificationRepo repositories.NotificationRepository, channels []NotificationChannel) NotificationService {
+	if notificationRepo == nil {
+		log.Fatal("ERREUR FATALE: NotificationRepository manquant lors de la création de NotificationService")
+	}
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		channels:         channels,
+	}
+}
+
+func (s *notificationService) Notify(ctx context.Context, userID uuid.UUID, notificationType, message string) (*models.Notification, error) {
+	notification := &models.Notification{
+		UserID:  userID,
+		Type:    notificationType,
+		Message: message,
+	}
+	if err := s.notificationRepo.CreateNotification(ctx, notification); err != nil {
+		return nil, fmt.Errorf("échec enregistrement notification: %w", err)
+	}
+
+	// La notification est déjà visible dans l'application : un canal en échec ne bloque pas les autres
+	for _, channel := range s.channels {
+		if err := channel.Send(ctx, *notification); err != nil {
+			log.Printf("WARN: [UserID: %s] Échec diffusion notification %s via le canal '%s': %v", userID, notification.ID, channel.Name(), err)
+		}
+	}
+	return notification, nil
+}
+
+func (s *notificationService) ListNotifications(ctx context.Context, userID uuid.UUID, unreadOnly bool) ([]models.Notification, error) {
+	return s.notificationRepo.ListNotificationsByUser(ctx, userID, unreadOnly)
+}
+
+func (s *notificationService) MarkAsRead(ctx context.Context, userID, notificationID uuid.UUID) error {
+	return s.notificationRepo.MarkNotificationRead(ctx, userID, notificationID)
+}
+
+func (s *notificationService) NotificationOwner(ctx context.Context, notificationID uuid.UUID) (uuid.UUID, error) {
+	return s.notificationRepo.GetNotificationOwner(ctx, notificationID)
+}
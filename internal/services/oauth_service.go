@@ -0,0 +1,331 @@
+// internal/services/oauth_service.go
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// Durée de validité d'un flux de connexion (entre la redirection et le callback)
+const OAuthFlowLifetime = 10 * time.Minute
+
+var (
+	ErrUnknownOAuthProvider = errors.New("fournisseur OAuth inconnu")
+	ErrInvalidOAuthState    = errors.New("flux de connexion OAuth invalide ou expiré")
+	ErrOAuthIdentityLinked  = errors.New("cette identité externe est déjà liée à un autre compte")
+	ErrOAuthEmailMissing    = errors.New("le fournisseur n'a pas communiqué d'adresse email")
+	ErrNoYouTubeAccess      = errors.New("aucun compte Google lié avec l'accès YouTube")
+	// ErrOAuthAccountUnverified : un compte local non vérifié utilise déjà l'adresse email
+	ErrOAuthAccountUnverified = errors.New("un compte existe déjà avec cette adresse email : connectez-vous avec votre mot de passe puis liez ce fournisseur depuis votre compte")
+)
+
+// oauthFlow est l'état d'un flux en cours, chiffré dans un cookie : aucun stockage serveur,
+// et le callback peut être traité par n'importe quelle instance
+type oauthFlow struct {
+	Provider   string    `json:"p"`
+	State      string    `json:"s"`
+	Verifier   string    `json:"v"` // code_verifier PKCE
+	Nonce      string    `json:"n"`
+	LinkUserID uuid.UUID `json:"l,omitempty"` // liaison à un compte existant (utilisateur connecté)
+	ExpiresAt  time.Time `json:"e"`
+}
+
+type OAuthService interface {
+	// BeginLogin retourne l'URL d'autorisation et l'état chiffré à renvoyer au callback (cookie).
+	// linkUserID vaut uuid.Nil pour une connexion, l'utilisateur courant pour lier son compte.
+	BeginLogin(ctx context.Context, provider string, linkUserID uuid.UUID) (authURL, sealedFlow string, err error)
+	// CompleteLogin vérifie l'état, échange le code et retourne le compte connecté (créé ou lié si besoin)
+	CompleteLogin(ctx context.Context, provider, state, code, sealedFlow string) (*models.User, error)
+	// ListMyYouTubeVideos liste les vidéos de la chaîne Google liée (privées et non répertoriées comprises)
+	ListMyYouTubeVideos(ctx context.Context, userID uuid.UUID, maxResults int64) ([]models.YouTubeVideo, error)
+}
+
+type oauthService struct {
+	identityRepo   repositories.OAuthIdentityRepository
+	userRepo       repositories.UserRepository
+	providers      map[string]OIDCProvider
+	secretBox      *utils.SecretBox
+	youtubeAdapter YouTubeAdapter
+}
+
+// NewOAuthService : secretBox peut être nil si aucun fournisseur n'est configuré
+func NewOAuthService(identityRepo repositories.OAuthIdentityRepository, userRepo repositories.UserRepository, providers []OIDCProvider, secretBox *utils.SecretBox, youtubeAdapter YouTubeAdapter) OAuthService {
+	if identityRepo == nil || userRepo == nil {
+		log.Fatal("ERREUR FATALE: Dépendances manquantes lors de la création de OAuthService")
+	}
+	if len(providers) > 0 && secretBox == nil {
+		log.Fatal("ERREUR FATALE: Clé de chiffrement OAuth manquante lors de la création de OAuthService")
+	}
+	byName := make(map[string]OIDCProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &oauthService{
+		identityRepo:   identityRepo,
+		userRepo:       userRepo,
+		providers:      byName,
+		secretBox:      secretBox,
+		youtubeAdapter: youtubeAdapter,
+	}
+}
+
+func randomHex(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("échec génération aléatoire: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func (s *oauthService) BeginLogin(ctx context.Context, providerName string, linkUserID uuid.UUID) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", ErrUnknownOAuthProvider
+	}
+
+	state, err := randomHex(16)
+	if err != nil {
+		return "", "", err
+	}
+	nonce, err := randomHex(16)
+	if err != nil {
+		return "", "", err
+	}
+	flow := oauthFlow{
+		Provider:   providerName,
+		State:      state,
+		Verifier:   oauth2.GenerateVerifier(),
+		Nonce:      nonce,
+		LinkUserID: linkUserID,
+		ExpiresAt:  time.Now().Add(OAuthFlowLifetime),
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, flow.State, flow.Nonce, flow.Verifier)
+	if err != nil {
+		return "", "", err
+	}
+	payload, err := json.Marshal(flow)
+	if err != nil {
+		return "", "", err
+	}
+	sealed, err := s.secretBox.Seal(payload)
+	if err != nil {
+		return "", "", err
+	}
+	return authURL, sealed, nil
+}
+
+func (s *oauthService) CompleteLogin(ctx context.Context, providerName, state, code, sealedFlow string) (*models.User, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownOAuthProvider
+	}
+	flow, err := s.openFlow(sealedFlow)
+	if err != nil || flow.Provider != providerName || flow.State != state || time.Now().After(flow.ExpiresAt) {
+		return nil, ErrInvalidOAuthState
+	}
+
+	external, err := provider.Exchange(ctx, code, flow.Verifier, flow.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	identity, err := s.identityRepo.FindIdentity(ctx, external.Provider, external.Subject)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case identity != nil:
+		if flow.LinkUserID != uuid.Nil && identity.UserID != flow.LinkUserID {
+			return nil, ErrOAuthIdentityLinked
+		}
+		if err := s.refreshIdentity(ctx, identity, external); err != nil {
+			return nil, err
+		}
+		return s.userRepo.FindByID(ctx, identity.UserID)
+	case flow.LinkUserID != uuid.Nil:
+		return s.linkIdentity(ctx, flow.LinkUserID, external)
+	}
+
+	// Première connexion : rattachement à un compte existant par email vérifié, sinon création
+	if external.Email == "" {
+		return nil, ErrOAuthEmailMissing
+	}
+	existing, err := s.userRepo.FindByEmail(ctx, external.Email)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return s.createUserFromIdentity(ctx, external)
+	case err != nil:
+		return nil, err
+	}
+	// Rattachement automatique seulement si les deux côtés ont prouvé la possession de l'adresse :
+	// sinon, un compte créé à l'avance avec l'email de la victime recevrait sa connexion Google
+	if !external.EmailVerified || existing.EmailVerifiedAt == nil {
+		log.Printf("WARN: [UserID: %s] Identité %s non liée : email non vérifié par le fournisseur ou par le compte", existing.ID, external.Provider)
+		return nil, ErrOAuthAccountUnverified
+	}
+	log.Printf("INFO: [UserID: %s] Identité %s liée via l'email vérifié", existing.ID, external.Provider)
+	return s.linkIdentity(ctx, existing.ID, external)
+}
+
+func (s *oauthService) openFlow(sealedFlow string) (*oauthFlow, error) {
+	payload, err := s.secretBox.Open(sealedFlow)
+	if err != nil {
+		return nil, err
+	}
+	var flow oauthFlow
+	if err := json.Unmarshal(payload, &flow); err != nil {
+		return nil, err
+	}
+	return &flow, nil
+}
+
+// newIdentityRecord chiffre le refresh token du fournisseur avant stockage
+func (s *oauthService) newIdentityRecord(userID uuid.UUID, external *models.ExternalIdentity) (*models.OAuthIdentity, error) {
+	identity := &models.OAuthIdentity{
+		UserID:   userID,
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		Email:    external.Email,
+		Scopes:   strings.Join(external.Scopes, " "),
+	}
+	if external.RefreshToken != "" {
+		encrypted, err := s.secretBox.Seal([]byte(external.RefreshToken))
+		if err != nil {
+			return nil, err
+		}
+		identity.EncryptedRefreshToken = encrypted
+	}
+	return identity, nil
+}
+
+// refreshIdentity met à jour email, scopes et refresh token (le fournisseur n'en délivre pas à chaque connexion)
+func (s *oauthService) refreshIdentity(ctx context.Context, identity *models.OAuthIdentity, external *models.ExternalIdentity) error {
+	updated, err := s.newIdentityRecord(identity.UserID, external)
+	if err != nil {
+		return err
+	}
+	identity.Email = updated.Email
+	identity.Scopes = updated.Scopes
+	if updated.EncryptedRefreshToken != "" {
+		identity.EncryptedRefreshToken = updated.EncryptedRefreshToken
+	}
+	return s.identityRepo.UpdateIdentity(ctx, identity)
+}
+
+func (s *oauthService) linkIdentity(ctx context.Context, userID uuid.UUID, external *models.ExternalIdentity) (*models.User, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.identityRepo.FindUserIdentity(ctx, userID, external.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		// Un seul compte par fournisseur : le nouveau remplace l'ancien
+		existing.Subject = external.Subject
+		existing.EncryptedRefreshToken = ""
+		if err := s.refreshIdentity(ctx, existing, external); err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	identity, err := s.newIdentityRecord(userID, external)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.identityRepo.CreateIdentity(ctx, identity); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+var usernameUnsafeChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (s *oauthService) createUserFromIdentity(ctx context.Context, external *models.ExternalIdentity) (*models.User, error) {
+	// Nom d'utilisateur dérivé de l'email, suffixé pour rester unique
+	base := usernameUnsafeChars.ReplaceAllString(strings.SplitN(external.Email, "@", 2)[0], "")
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	if base == "" {
+		base = external.Provider
+	}
+	suffix, err := randomHex(3)
+	if err != nil {
+		return nil, err
+	}
+
+	// Mot de passe aléatoire inconnu de tous : l'utilisateur peut en définir un via la réinitialisation
+	randomPassword, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(randomPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username: base + "-" + suffix,
+		Email:    external.Email,
+		Password: string(hashedPassword),
+		Role:     models.RoleUser,
+	}
+	if external.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	identity, err := s.newIdentityRecord(uuid.Nil, external)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.identityRepo.CreateUserWithIdentity(ctx, user, identity); err != nil {
+		return nil, err
+	}
+	log.Printf("INFO: [UserID: %s] Compte créé via %s", user.ID, external.Provider)
+	return user, nil
+}
+
+func (s *oauthService) ListMyYouTubeVideos(ctx context.Context, userID uuid.UUID, maxResults int64) ([]models.YouTubeVideo, error) {
+	provider, ok := s.providers["google"]
+	if !ok || s.youtubeAdapter == nil {
+		return nil, ErrNoYouTubeAccess
+	}
+	identity, err := s.identityRepo.FindUserIdentity(ctx, userID, "google")
+	if err != nil {
+		return nil, err
+	}
+	if identity == nil || identity.EncryptedRefreshToken == "" || !strings.Contains(" "+identity.Scopes+" ", " "+models.YouTubeReadOnlyScope+" ") {
+		return nil, ErrNoYouTubeAccess
+	}
+
+	refreshToken, err := s.secretBox.Open(identity.EncryptedRefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("échec du déchiffrement du refresh token Google: %w", err)
+	}
+	tokenSource, err := provider.TokenSource(ctx, string(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	return s.youtubeAdapter.GetMyVideos(ctx, tokenSource, maxResults)
+}
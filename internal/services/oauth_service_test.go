@@ -0,0 +1,155 @@
+// internal/services/oauth_service_test.go
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// fakeOIDCProvider retourne l'identité externe configurée par le test à chaque échange de code
+type fakeOIDCProvider struct {
+	identity models.ExternalIdentity
+}
+
+func (p *fakeOIDCProvider) Name() string { return "google" }
+
+func (p *fakeOIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	return "https://accounts.example.com/auth?state=" + state, nil
+}
+
+func (p *fakeOIDCProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*models.ExternalIdentity, error) {
+	identity := p.identity
+	return &identity, nil
+}
+
+func (p *fakeOIDCProvider) TokenSource(ctx context.Context, refreshToken string) (oauth2.TokenSource, error) {
+	return nil, errors.New("non utilisé")
+}
+
+type oauthTestEnv struct {
+	service  OAuthService
+	provider *fakeOIDCProvider
+	repos    repositories.AllRepository
+}
+
+func newOAuthTestEnv(t *testing.T) *oauthTestEnv {
+	t.Helper()
+	_, repos := openTestRepositories(t)
+	box, err := utils.NewSecretBox([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("SecretBox: %v", err)
+	}
+	provider := &fakeOIDCProvider{identity: models.ExternalIdentity{
+		Provider: "google", Subject: "google-sub-1", Email: "victim@example.com", EmailVerified: true,
+		RefreshToken: "refresh", Scopes: []string{"openid", "email"},
+	}}
+	return &oauthTestEnv{
+		service:  NewOAuthService(repos.OAuthIdentityRepository, repos.UserRepository, []OIDCProvider{provider}, box, nil),
+		provider: provider,
+		repos:    repos,
+	}
+}
+
+// login exécute un flux complet (redirection puis callback) ; linkUserID vaut uuid.Nil pour une connexion
+func (e *oauthTestEnv) login(t *testing.T, linkUserID uuid.UUID) (*models.User, error) {
+	t.Helper()
+	authURL, sealed, err := e.service.BeginLogin(t.Context(), "google", linkUserID)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	state := authURL[strings.Index(authURL, "state=")+len("state="):]
+	return e.service.CompleteLogin(t.Context(), "google", state, "code", sealed)
+}
+
+func TestOAuthCompleteLoginCreatesThenReusesAccount(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	created, err := env.login(t, uuid.Nil)
+	if err != nil {
+		t.Fatalf("première connexion: %v", err)
+	}
+	if created.Email != "victim@example.com" || created.EmailVerifiedAt == nil {
+		t.Fatalf("compte créé: %+v", created)
+	}
+	again, err := env.login(t, uuid.Nil)
+	if err != nil || again.ID != created.ID {
+		t.Fatalf("seconde connexion: %v, %v", err, again)
+	}
+}
+
+func TestOAuthCompleteLoginRejectsInvalidState(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	_, sealed, err := env.service.BeginLogin(t.Context(), "google", uuid.Nil)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	if _, err := env.service.CompleteLogin(t.Context(), "google", "autre-state", "code", sealed); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Fatalf("state falsifié: %v", err)
+	}
+}
+
+func TestOAuthCompleteLoginAutoLinksOnlyVerifiedAccounts(t *testing.T) {
+	t.Run("compte local non vérifié", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		// Compte créé à l'avance par un attaquant avec l'adresse de la victime
+		createTestUser(t, env.repos, "victim", false)
+		if _, err := env.login(t, uuid.Nil); !errors.Is(err, ErrOAuthAccountUnverified) {
+			t.Fatalf("connexion rattachée à un compte non vérifié: %v", err)
+		}
+		if identity, _ := env.repos.OAuthIdentityRepository.FindIdentity(t.Context(), "google", "google-sub-1"); identity != nil {
+			t.Fatalf("identité liée: %+v", identity)
+		}
+	})
+	t.Run("email non vérifié par le fournisseur", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		owner := createTestUser(t, env.repos, "victim", true)
+		env.provider.identity.Email = owner.Email
+		env.provider.identity.EmailVerified = false
+		if _, err := env.login(t, uuid.Nil); !errors.Is(err, ErrOAuthAccountUnverified) {
+			t.Fatalf("connexion rattachée sans email vérifié: %v", err)
+		}
+	})
+	t.Run("compte et email vérifiés", func(t *testing.T) {
+		env := newOAuthTestEnv(t)
+		owner := createTestUser(t, env.repos, "victim", true)
+		user, err := env.login(t, uuid.Nil)
+		if err != nil || user.ID != owner.ID {
+			t.Fatalf("rattachement: %v, %v", err, user)
+		}
+	})
+}
+
+func TestOAuthExplicitLinking(t *testing.T) {
+	env := newOAuthTestEnv(t)
+	// Liaison explicite depuis un compte connecté, même non vérifié et avec une autre adresse
+	alice := createTestUser(t, env.repos, "alice", false)
+	user, err := env.login(t, alice.ID)
+	if err != nil || user.ID != alice.ID {
+		t.Fatalf("liaison: %v, %v", err, user)
+	}
+	identity, err := env.repos.OAuthIdentityRepository.FindUserIdentity(t.Context(), alice.ID, "google")
+	if err != nil || identity == nil || identity.Subject != "google-sub-1" {
+		t.Fatalf("identité liée: %v, %+v", err, identity)
+	}
+	if identity.EncryptedRefreshToken == "" || identity.EncryptedRefreshToken == "refresh" {
+		t.Fatal("refresh token non chiffré")
+	}
+
+	// L'identité ne peut pas être liée à un second compte
+	bob := createTestUser(t, env.repos, "bob", true)
+	if _, err := env.login(t, bob.ID); !errors.Is(err, ErrOAuthIdentityLinked) {
+		t.Fatalf("identité liée à deux comptes: %v", err)
+	}
+	// Reconnexion : l'identité liée mène au compte d'alice
+	if user, err := env.login(t, uuid.Nil); err != nil || user.ID != alice.ID {
+		t.Fatalf("connexion après liaison: %v, %v", err, user)
+	}
+}
@@ -0,0 +1,189 @@
+// internal/services/token_service.go
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// Durée de vie d'un refresh token (renouvelée à chaque rotation)
+const RefreshTokenLifetime = 30 * 24 * time.Hour
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token invalide ou expiré")
+	ErrRefreshTokenReused  = errors.New("réutilisation d'un refresh token détectée")
+)
+
+// TokenPair est la réponse d'une connexion ou d'un renouvellement
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"` // durée de vie du token d'accès, en secondes
+}
+
+type TokenService interface {
+	// IssueTokens ouvre une nouvelle famille de refresh tokens (connexion)
+	IssueTokens(ctx context.Context, user *models.User) (*TokenPair, error)
+	// Refresh échange un refresh token contre une nouvelle paire (rotation)
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+	// Logout révoque le token d'accès courant et, si fourni, la famille du refresh token
+	Logout(ctx context.Context, principal utils.Principal, refreshToken string) error
+	// VerifyAccessToken valide la signature et les claims d'un token d'accès (pas la révocation)
+	VerifyAccessToken(tokenString string) (*utils.Claims, error)
+	IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error)
+	// JWKS retourne les clés publiques de vérification (RS256/EdDSA)
+	JWKS() []utils.JWK
+	// RevokeAllSessions révoque tous les refresh tokens de l'utilisateur (changement de mot de passe...)
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) error
+	PurgeExpired(ctx context.Context) error
+}
+
+type tokenService struct {
+	tokenRepo repositories.TokenRepository
+	userRepo  repositories.UserRepository
+	jwtKeys   *utils.KeySet // clés de signature et de vérification des tokens d'accès
+}
+
+func NewTokenService(tokenRepo repositories.TokenRepository, userRepo repositories.UserRepository, jwtKeys *utils.KeySet) TokenService {
+	if tokenRepo == nil || userRepo == nil || jwtKeys == nil {
+		log.Fatal("ERREUR FATALE: Dépendances manquantes lors de la création de TokenService")
+	}
+	return &tokenService{tokenRepo: tokenRepo, userRepo: userRepo, jwtKeys: jwtKeys}
+}
+
+// hashOpaqueToken : seul le hash est stocké, un dump de la base ne permet pas de rejouer les tokens
+func hashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func newOpaqueToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("échec génération aléatoire: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (s *tokenService) IssueTokens(ctx context.Context, user *models.User) (*TokenPair, error) {
+	return s.issue(ctx, user, uuid.New())
+}
+
+func (s *tokenService) issue(ctx context.Context, user *models.User, familyID uuid.UUID) (*TokenPair, error) {
+	accessToken, err := utils.GenerateJWT(s.jwtKeys, user.ID, user.Role)
+	if err != nil {
+		return nil, fmt.Errorf("échec création du token d'accès: %w", err)
+	}
+
+	refreshToken, err := newOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+	err = s.tokenRepo.CreateRefreshToken(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashOpaqueToken(refreshToken),
+		ExpiresAt: time.Now().Add(RefreshTokenLifetime),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int(utils.JWTLifetime.Seconds()),
+	}, nil
+}
+
+func (s *tokenService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	stored, err := s.tokenRepo.FindRefreshTokenByHash(ctx, hashOpaqueToken(refreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if stored == nil || stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	// Compte chargé avant de consommer le token : une panne de la base ne le grille pas
+	user, err := s.userRepo.FindByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Compte supprimé entre-temps
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("chargement du compte: %w", err)
+	}
+
+	// Un token déjà échangé qui revient est probablement volé : on coupe toute la famille
+	swapped, err := s.tokenRepo.MarkRefreshTokenUsed(ctx, stored.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if stored.UsedAt != nil || !swapped {
+		log.Printf("WARN: [UserID: %s] Réutilisation du refresh token %s détectée, révocation de la famille %s", stored.UserID, stored.ID, stored.FamilyID)
+		if err := s.tokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
+	}
+
+	return s.issue(ctx, user, stored.FamilyID)
+}
+
+func (s *tokenService) Logout(ctx context.Context, principal utils.Principal, refreshToken string) error {
+	err := s.tokenRepo.RevokeAccessToken(ctx, &models.RevokedAccessToken{
+		JTI:       principal.TokenID,
+		UserID:    principal.UserID,
+		ExpiresAt: principal.ExpiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	stored, err := s.tokenRepo.FindRefreshTokenByHash(ctx, hashOpaqueToken(refreshToken))
+	if err != nil {
+		return err
+	}
+	// Un token inconnu ou appartenant à un autre utilisateur est ignoré silencieusement
+	if stored == nil || stored.UserID != principal.UserID {
+		return nil
+	}
+	return s.tokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyID)
+}
+
+func (s *tokenService) VerifyAccessToken(tokenString string) (*utils.Claims, error) {
+	return utils.VerifyJWT(s.jwtKeys, tokenString)
+}
+
+func (s *tokenService) JWKS() []utils.JWK {
+	return s.jwtKeys.JWKS()
+}
+
+func (s *tokenService) IsAccessTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	return s.tokenRepo.IsAccessTokenRevoked(ctx, jti)
+}
+
+func (s *tokenService) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	return s.tokenRepo.RevokeUserRefreshTokens(ctx, userID)
+}
+
+func (s *tokenService) PurgeExpired(ctx context.Context) error {
+	return s.tokenRepo.PurgeExpiredTokens(ctx, time.Now())
+}
@@ -0,0 +1,171 @@
+// internal/services/token_service_test.go
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+	"github.com/Azertdev/FiberTest/internal/repositories"
+	"github.com/Azertdev/FiberTest/internal/utils"
+)
+
+// failingUserRepository simule une panne (ou un compte disparu) sur FindByID
+type failingUserRepository struct {
+	repositories.UserRepository
+	err error
+}
+
+func (r *failingUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.UserRepository.FindByID(ctx, id)
+}
+
+type tokenTestEnv struct {
+	service TokenService
+	users   *failingUserRepository
+	repos   repositories.AllRepository
+	db      *gorm.DB
+}
+
+func newTokenTestEnv(t *testing.T) *tokenTestEnv {
+	t.Helper()
+	db, repos := openTestRepositories(t)
+	keys, err := utils.NewKeySet("test", []*utils.SigningKey{utils.NewHMACKey("test", []byte("0123456789abcdef0123456789abcdef"))})
+	if err != nil {
+		t.Fatalf("clés JWT: %v", err)
+	}
+	users := &failingUserRepository{UserRepository: repos.UserRepository}
+	return &tokenTestEnv{service: NewTokenService(repos.TokenRepository, users, keys), users: users, repos: repos, db: db}
+}
+
+func TestRefreshRotatesAndRevokesTheFamilyOnReuse(t *testing.T) {
+	env := newTokenTestEnv(t)
+	ctx := t.Context()
+	user := createTestUser(t, env.repos, "alice", true)
+	login, err := env.service.IssueTokens(ctx, user)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	otherDevice, err := env.service.IssueTokens(ctx, user)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	rotated, err := env.service.Refresh(ctx, login.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if rotated.RefreshToken == login.RefreshToken || rotated.AccessToken == "" {
+		t.Fatal("refresh token non renouvelé")
+	}
+
+	// Rejeu du token échangé : toute la famille est révoquée, y compris le token issu de la rotation
+	if _, err := env.service.Refresh(ctx, login.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("rejeu: %v, attendu ErrRefreshTokenReused", err)
+	}
+	if _, err := env.service.Refresh(ctx, rotated.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("token de la famille révoquée: %v, attendu ErrInvalidRefreshToken", err)
+	}
+	// Les autres connexions de l'utilisateur ne sont pas touchées
+	if _, err := env.service.Refresh(ctx, otherDevice.RefreshToken); err != nil {
+		t.Fatalf("autre famille révoquée: %v", err)
+	}
+}
+
+func TestRefreshRejectsUnknownAndExpiredTokens(t *testing.T) {
+	env := newTokenTestEnv(t)
+	ctx := t.Context()
+	user := createTestUser(t, env.repos, "alice", true)
+	tokens, err := env.service.IssueTokens(ctx, user)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	if _, err := env.service.Refresh(ctx, "inconnu"); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("token inconnu: %v", err)
+	}
+	if err := env.db.Model(&models.RefreshToken{}).Where("user_id = ?", user.ID).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.service.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("token expiré: %v", err)
+	}
+}
+
+func TestRefreshOnlyInvalidatesTheTokenWhenTheAccountIsGone(t *testing.T) {
+	env := newTokenTestEnv(t)
+	ctx := t.Context()
+	user := createTestUser(t, env.repos, "alice", true)
+	tokens, err := env.service.IssueTokens(ctx, user)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+
+	// Panne de la base : l'erreur remonte telle quelle et le token n'est pas consommé
+	outage := errors.New("connexion perdue")
+	env.users.err = outage
+	if _, err := env.service.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, outage) || errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("panne: %v, attendu l'erreur de la base", err)
+	}
+	env.users.err = nil
+	rotated, err := env.service.Refresh(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("token inutilisable après la panne: %v", err)
+	}
+
+	// Compte supprimé entre-temps
+	env.users.err = gorm.ErrRecordNotFound
+	if _, err := env.service.Refresh(ctx, rotated.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("compte disparu: %v, attendu ErrInvalidRefreshToken", err)
+	}
+}
+
+func TestLogoutRevokesTheAccessTokenAndTheRefreshFamily(t *testing.T) {
+	env := newTokenTestEnv(t)
+	ctx := t.Context()
+	alice := createTestUser(t, env.repos, "alice", true)
+	bob := createTestUser(t, env.repos, "bob", true)
+	tokens, err := env.service.IssueTokens(ctx, alice)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	bobTokens, err := env.service.IssueTokens(ctx, bob)
+	if err != nil {
+		t.Fatalf("IssueTokens: %v", err)
+	}
+	claims, err := env.service.VerifyAccessToken(tokens.AccessToken)
+	if err != nil {
+		t.Fatalf("VerifyAccessToken: %v", err)
+	}
+	principal, err := claims.Principal()
+	if err != nil {
+		t.Fatalf("Principal: %v", err)
+	}
+
+	// Le refresh token de Bob, fourni par Alice, n'est pas révoqué
+	if err := env.service.Logout(ctx, principal, bobTokens.RefreshToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+	if _, err := env.service.Refresh(ctx, bobTokens.RefreshToken); err != nil {
+		t.Fatalf("session de Bob révoquée par Alice: %v", err)
+	}
+	if err := env.service.Logout(ctx, principal, tokens.RefreshToken); err != nil {
+		t.Fatalf("Logout: %v", err)
+	}
+
+	revoked, err := env.service.IsAccessTokenRevoked(ctx, principal.TokenID)
+	if err != nil || !revoked {
+		t.Fatalf("jti %s non révoqué: %v", principal.TokenID, err)
+	}
+	if _, err := env.service.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Fatalf("refresh après déconnexion: %v", err)
+	}
+}
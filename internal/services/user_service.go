@@ -1,23 +1,30 @@
 package services
 
 import (
-	"time"
+	"context"
+	"errors"
 
 	"github.com/Azertdev/FiberTest/internal/models"
 	"github.com/Azertdev/FiberTest/internal/repositories"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtKey = []byte("JWT_SECRET")
+var (
+	ErrInvalidRole     = errors.New("rôle invalide (user ou admin)")
+	ErrInvalidPassword = errors.New("mot de passe actuel incorrect")
+)
 
 type UserService interface {
-	CreateUser(user *models.User) error
-	GetAllUsers() ([]models.User, error)
-	GetUserByID(id uint) (*models.User, error)
-	AuthenticateUser(username, password string)(*models.User, error)
-	GenerateJWT(userID uuid.UUID) (string, error) 
+	CreateUser(ctx context.Context, user *models.User) error
+	GetAllUsers(ctx context.Context) ([]models.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error)
+	AuthenticateUser(ctx context.Context, username, password string)(*models.User, error)
+	UpdateUserRole(ctx context.Context, id uuid.UUID, role string) error
+	UpdateProfile(ctx context.Context, id uuid.UUID, request models.UpdateProfileRequest) (*models.User, error)
+	ChangePassword(ctx context.Context, id uuid.UUID, request models.ChangePasswordRequest) error
+	ChangeEmail(ctx context.Context, id uuid.UUID, request models.ChangeEmailRequest) (*models.User, error)
+	DeleteAccount(ctx context.Context, id uuid.UUID, currentPassword string) error
 }
 
 type userService struct {
@@ -28,38 +35,79 @@ func NewUserService(userRepo repositories.UserRepository) *userService {
 	return &userService{userRepo}
 }
 
-func (s *userService) CreateUser(user *models.User) error {
+func (s *userService) CreateUser(ctx context.Context, user *models.User) error {
+	// Le rôle n'est jamais choisi à l'inscription : seul un admin peut le changer
+	user.Role = models.RoleUser
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 	user.Password = string(hashedPassword)
-	return s.userRepo.Create(user)
+	return s.userRepo.Create(ctx, user)
 }
 
-func (s *userService) GetAllUsers() ([]models.User, error) {
-	return s.userRepo.FindAll()
+func (s *userService) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	return s.userRepo.FindAll(ctx, )
 }
-func (s *userService) GetUserByID(id uint) (*models.User, error) {
-	return s.userRepo.FindByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return s.userRepo.FindByID(ctx, id)
 }
 
-func (s *userService) AuthenticateUser(username, password string) (*models.User, error) {
-	return s.userRepo.AuthenticateUser(username, password)
+func (s *userService) AuthenticateUser(ctx context.Context, username, password string) (*models.User, error) {
+	return s.userRepo.AuthenticateUser(ctx, username, password)
 }
 
-func (s *userService) GenerateJWT(userID uuid.UUID) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 1).Unix(),
+func (s *userService) UpdateUserRole(ctx context.Context, id uuid.UUID, role string) error {
+	if role != models.RoleUser && role != models.RoleAdmin {
+		return ErrInvalidRole
 	}
+	return s.userRepo.UpdateRole(ctx, id, role)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+func (s *userService) UpdateProfile(ctx context.Context, id uuid.UUID, request models.UpdateProfileRequest) (*models.User, error) {
+	if err := s.userRepo.UpdateFields(ctx, id, map[string]any{"username": request.Username}); err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(ctx, id)
+}
 
-	tokenString, err := token.SignedString(jwtKey)
+func (s *userService) ChangePassword(ctx context.Context, id uuid.UUID, request models.ChangePasswordRequest) error {
+	if err := s.verifyCurrentPassword(ctx, id, request.CurrentPassword); err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
-		return "", err
+		return err
 	}
+	return s.userRepo.UpdateFields(ctx, id, map[string]any{"password": string(hashedPassword)})
+}
 
-	return tokenString, nil
-}
\ No newline at end of file
+func (s *userService) ChangeEmail(ctx context.Context, id uuid.UUID, request models.ChangeEmailRequest) (*models.User, error) {
+	if err := s.verifyCurrentPassword(ctx, id, request.CurrentPassword); err != nil {
+		return nil, err
+	}
+	// La nouvelle adresse doit être confirmée à son tour
+	if err := s.userRepo.UpdateFields(ctx, id, map[string]any{"email": request.NewEmail, "email_verified_at": nil}); err != nil {
+		return nil, err
+	}
+	return s.userRepo.FindByID(ctx, id)
+}
+
+func (s *userService) DeleteAccount(ctx context.Context, id uuid.UUID, currentPassword string) error {
+	if err := s.verifyCurrentPassword(ctx, id, currentPassword); err != nil {
+		return err
+	}
+	return s.userRepo.DeleteWithRelations(ctx, id)
+}
+
+// verifyCurrentPassword recharge l'utilisateur et compare le mot de passe fourni au hash bcrypt
+func (s *userService) verifyCurrentPassword(ctx context.Context, id uuid.UUID, password string) error {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return ErrInvalidPassword
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+// internal/utils/insight_export.go
+package utils
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// Formats d'export supportés
+const (
+	ExportFormatCSV      = "csv"
+	ExportFormatJSON     = "json"
+	ExportFormatMarkdown = "md"
+	ExportFormatPDF      = "pdf"
+)
+
+// ExportContentTypes associe chaque format à son Content-Type
+var ExportContentTypes = map[string]string{
+	ExportFormatCSV:      "text/csv; charset=utf-8",
+	ExportFormatJSON:     "application/json; charset=utf-8",
+	ExportFormatMarkdown: "text/markdown; charset=utf-8",
+	ExportFormatPDF:      "application/pdf",
+}
+
+// exportSection est une liste de commentaires catégorisés d'un insight
+type exportSection struct {
+	Category string // valeur de la colonne "category" du CSV
+	Title    string // titre dans les rapports Markdown/PDF
+	Items    []string
+}
+
+func insightSections(insight *models.Insight) []exportSection {
+	return []exportSection{
+		{Category: "question", Title: "Questions posées", Items: DecodeStringList(insight.QuestionComments)},
+		{Category: "negative", Title: "Critiques négatives", Items: DecodeStringList(insight.NegativeComments)},
+		{Category: "positive", Title: "Points positifs ou constructifs", Items: DecodeStringList(insight.TopComments)},
+		{Category: "feedback", Title: "Feedbacks spécifiques ou techniques", Items: DecodeStringList(insight.FeedbackComments)},
+	}
+}
+
+// DecodeStringList décode un champ datatypes.JSON contenant un []string (liste vide si absent ou invalide)
+func DecodeStringList(raw []byte) []string {
+	var items []string
+	if len(raw) == 0 || json.Unmarshal(raw, &items) != nil || items == nil {
+		return []string{}
+	}
+	return items
+}
+
+// WriteInsightsExport écrit les insights dans le format demandé
+func WriteInsightsExport(w io.Writer, format string, insights []models.Insight) error {
+	switch format {
+	case ExportFormatCSV:
+		return WriteInsightsCSV(w, insights)
+	case ExportFormatJSON:
+		return WriteInsightsJSON(w, insights)
+	case ExportFormatMarkdown:
+		return WriteInsightsMarkdown(w, insights)
+	case ExportFormatPDF:
+		return WriteInsightsPDF(w, insights)
+	}
+	return fmt.Errorf("format d'export non supporté: %q", format)
+}
+
+// WriteInsightsCSV écrit une ligne par commentaire catégorisé. Les commentaires sont ceux des listes
+// de l'insight, déjà limitées à la fusion des lots (MergeLimits.MaxListItems par catégorie).
+func WriteInsightsCSV(w io.Writer, insights []models.Insight) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"insight_id", "video_id", "version", "created_at", "category", "comment"}); err != nil {
+		return err
+	}
+	for i := range insights {
+		insight := &insights[i]
+		for _, section := range insightSections(insight) {
+			for _, item := range section.Items {
+				record := []string{
+					insight.ID.String(),
+					csvCell(insight.VideoID),
+					strconv.Itoa(insight.Version),
+					insight.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+					section.Category,
+					csvCell(item),
+				}
+				if err := writer.Write(record); err != nil {
+					return err
+				}
+			}
+		}
+		writer.Flush() // Envoie les lignes au fur et à mesure (téléchargement en streaming)
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvCell neutralise les cellules qu'un tableur exécuterait comme une formule (=, +, -, @, tabulation,
+// retour chariot en tête) : les commentaires viennent des spectateurs YouTube
+func csvCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// WriteInsightsJSON écrit l'insight complet (objet) ou la liste d'insights (tableau)
+func WriteInsightsJSON(w io.Writer, insights []models.Insight) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if len(insights) == 1 {
+		return encoder.Encode(insights[0])
+	}
+	return encoder.Encode(insights)
+}
+
+// WriteInsightsMarkdown écrit un rapport Markdown, une section par insight
+func WriteInsightsMarkdown(w io.Writer, insights []models.Insight) error {
+	for i := range insights {
+		insight := &insights[i]
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n---\n\n"); err != nil {
+				return err
+			}
+		}
+		var b strings.Builder
+		fmt.Fprintf(&b, "# Rapport d'analyse – vidéo %s (version %d)\n\n", insight.VideoID, insight.Version)
+		fmt.Fprintf(&b, "_Analyse du %s – %d commentaires analysés_\n\n", insight.CreatedAt.Format("02/01/2006 15:04"), insight.CommentCount)
+		fmt.Fprintf(&b, "## Sentiment général\n\n%s\n\n", orPlaceholder(insight.Sentiment, "_Non disponible._"))
+		if line := sentimentStatsLine(insight.SentimentStats); line != "" {
+			fmt.Fprintf(&b, "%s\n\n", line)
+		}
+		fmt.Fprintf(&b, "## Résumé des commentaires\n\n%s\n\n", orPlaceholder(insight.Summary, "_Non disponible._"))
+		for _, section := range insightSections(insight) {
+			fmt.Fprintf(&b, "## %s\n\n", section.Title)
+			writeMarkdownList(&b, section.Items)
+		}
+		b.WriteString("## Mots-clés\n\n")
+		writeMarkdownList(&b, keywordItems(insight))
+		fmt.Fprintf(&b, "## Résumé de la vidéo\n\n%s\n", orPlaceholder(insight.TranscriptSummary, "_Non disponible._"))
+
+		if _, err := io.WriteString(w, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeMarkdownList(b *strings.Builder, items []string) {
+	if len(items) == 0 {
+		b.WriteString("_Aucun élément._\n\n")
+		return
+	}
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", strings.ReplaceAll(item, "\n", " "))
+	}
+	b.WriteString("\n")
+}
+
+// keywordItems liste les mots-clés avec le nombre de commentaires qui les mentionnent
+// (insights antérieurs au calcul des fréquences : mots-clés seuls)
+func keywordItems(insight *models.Insight) []string {
+	var frequencies []models.KeywordFrequency
+	if len(insight.KeywordFrequencies) == 0 || json.Unmarshal(insight.KeywordFrequencies, &frequencies) != nil || len(frequencies) == 0 {
+		return DecodeStringList(insight.Keywords)
+	}
+	items := make([]string, len(frequencies))
+	for i, keyword := range frequencies {
+		items[i] = fmt.Sprintf("%s (%d commentaires)", keyword.Keyword, keyword.Frequency)
+	}
+	return items
+}
+
+// sentimentStatsLine résume le sentiment chiffré (vide si aucun commentaire n'a été évalué)
+func sentimentStatsLine(stats models.SentimentStats) string {
+	if stats.Scored == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Score : %+.2f (confiance %.0f %%) – %d positifs, %d neutres, %d négatifs, %d mixtes sur %d commentaires évalués",
+		stats.Score, stats.Confidence*100, stats.Positive, stats.Neutral, stats.Negative, stats.Mixed, stats.Scored)
+}
+
+func orPlaceholder(value, placeholder string) string {
+	if strings.TrimSpace(value) == "" {
+		return placeholder
+	}
+	return value
+}
+
+// WriteInsightsPDF écrit un rapport PDF (polices standard, une page de départ par insight)
+func WriteInsightsPDF(w io.Writer, insights []models.Insight) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle("Rapport d'analyse EngageSense", true)
+	pdf.AliasNbPages("")
+	// Les polices standard sont en cp1252 : conversion depuis l'UTF-8 (les emojis sont ignorés)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	heading := func(text string) {
+		pdf.Ln(3)
+		pdf.SetFont("Helvetica", "B", 13)
+		pdf.MultiCell(0, 7, tr(text), "", "L", false)
+		pdf.Ln(1)
+	}
+	paragraph := func(text string) {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 5, tr(strings.TrimSpace(text)), "", "L", false)
+	}
+	list := func(items []string) {
+		if len(items) == 0 {
+			pdf.SetFont("Helvetica", "I", 10)
+			pdf.MultiCell(0, 5, tr("Aucun élément."), "", "L", false)
+			return
+		}
+		pdf.SetFont("Helvetica", "", 10)
+		for _, item := range items {
+			pdf.MultiCell(0, 5, tr("- "+item), "", "L", false)
+		}
+	}
+
+	for i := range insights {
+		insight := &insights[i]
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.MultiCell(0, 9, tr(fmt.Sprintf("Rapport d'analyse – vidéo %s (version %d)", insight.VideoID, insight.Version)), "", "L", false)
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.MultiCell(0, 5, tr(fmt.Sprintf("Analyse du %s – %d commentaires analysés", insight.CreatedAt.Format("02/01/2006 15:04"), insight.CommentCount)), "", "L", false)
+
+		heading("Sentiment général")
+		paragraph(orPlaceholder(insight.Sentiment, "Non disponible."))
+		if line := sentimentStatsLine(insight.SentimentStats); line != "" {
+			paragraph(line)
+		}
+		heading("Résumé des commentaires")
+		paragraph(orPlaceholder(insight.Summary, "Non disponible."))
+		for _, section := range insightSections(insight) {
+			heading(section.Title)
+			list(section.Items)
+		}
+		heading("Mots-clés")
+		list(keywordItems(insight))
+		heading("Résumé de la vidéo")
+		paragraph(strings.ReplaceAll(orPlaceholder(insight.TranscriptSummary, "Non disponible."), "#", ""))
+	}
+
+	if pdf.Err() {
+		return fmt.Errorf("échec de la génération du PDF: %w", pdf.Error())
+	}
+	return pdf.Output(w)
+}
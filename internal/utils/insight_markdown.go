@@ -0,0 +1,53 @@
+// internal/utils/insight_markdown.go
+package utils
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// polarityLabelNames : étiquette écrite dans la section de polarité pour chaque sentiment
+var polarityLabelNames = map[string]string{
+	models.SentimentPositive: "positif",
+	models.SentimentNeutral:  "neutre",
+	models.SentimentNegative: "négatif",
+	models.SentimentMixed:    "mixte",
+}
+
+// RenderInsightMarkdown produit une réponse au format demandé par le prompt d'analyse (sections
+// 1 à 7, et 8 si CommentScores est renseigné), telle que ParseInsightResponse la relit
+func RenderInsightMarkdown(insight ParsedInsight) string {
+	var b strings.Builder
+	section := func(title string, items []string, empty string) {
+		fmt.Fprintf(&b, "## %s\n", title)
+		if len(items) == 0 {
+			fmt.Fprintf(&b, "%s\n\n", empty)
+			return
+		}
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "## 1. Sentiment Général\n%s\n\n", insight.Sentiment)
+	fmt.Fprintf(&b, "## 2. Résumé Général des Commentaires\n%s\n\n", insight.Summary)
+	section("3. Questions Posées", insight.QuestionComments, "Aucune question identifiée.")
+	section("4. Critiques Négatives", insight.NegativeComments, "Aucune critique négative significative identifiée.")
+	section("5. Points Positifs ou Constructifs", insight.TopComments, "Aucun commentaire positif ou constructif notable identifié.")
+	section("6. Feedbacks Spécifiques ou Techniques", insight.FeedbackComments, "Aucun feedback spécifique ou technique identifié.")
+	section("7. Mots-clés et Thèmes Fréquents", insight.Keywords, "Aucun mot-clé identifié.")
+	if len(insight.CommentScores) > 0 {
+		indexes := slices.Sorted(maps.Keys(insight.CommentScores))
+		lines := make([]string, len(indexes))
+		for i, index := range indexes {
+			score := insight.CommentScores[index]
+			lines[i] = fmt.Sprintf("[%d] %.2f %s", index, score.Polarity, polarityLabelNames[score.Label])
+		}
+		section("8. Polarité des Commentaires", lines, "")
+	}
+	return b.String()
+}
@@ -0,0 +1,67 @@
+// internal/utils/insight_meta_summary.go
+package utils
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// MetaSummaryInput regroupe ce que l'étape de réduction envoie au modèle pour produire un
+// sentiment et un résumé globaux à partir des analyses par lots
+type MetaSummaryInput struct {
+	CommentCount    int              // commentaires analysés au total
+	ChunkSummaries  []string         // résumé de chaque lot, dans l'ordre
+	SentimentCounts []SentimentCount // sentiments des lots, du plus fréquent au moins fréquent
+	TopKeywords     []string         // mots-clés de l'insight fusionné
+}
+
+// SentimentCount : nombre de lots ayant retourné ce sentiment
+type SentimentCount struct {
+	Sentiment string
+	Chunks    int
+}
+
+// NewMetaSummaryInput prépare l'entrée de l'étape de réduction à partir des analyses par lots
+// et de leur fusion (MergeParsedInsights)
+func NewMetaSummaryInput(partials []*ParsedInsight, merged *ParsedInsight, commentCount int) MetaSummaryInput {
+	input := MetaSummaryInput{CommentCount: commentCount, ChunkSummaries: []string{}, TopKeywords: []string{}}
+	counts := map[string]int{}
+	for _, p := range partials {
+		if p == nil {
+			continue
+		}
+		if summary := strings.TrimSpace(p.Summary); summary != "" {
+			input.ChunkSummaries = append(input.ChunkSummaries, summary)
+		}
+		if sentiment := strings.TrimSpace(p.Sentiment); sentiment != "" {
+			counts[sentiment]++
+		}
+	}
+	for sentiment, chunks := range counts {
+		input.SentimentCounts = append(input.SentimentCounts, SentimentCount{Sentiment: sentiment, Chunks: chunks})
+	}
+	// Ordre stable (le prompt ne doit pas dépendre de l'ordre d'itération de la map)
+	sort.Slice(input.SentimentCounts, func(i, j int) bool {
+		a, b := input.SentimentCounts[i], input.SentimentCounts[j]
+		if a.Chunks != b.Chunks {
+			return a.Chunks > b.Chunks
+		}
+		return a.Sentiment < b.Sentiment
+	})
+	if merged != nil {
+		input.TopKeywords = append(input.TopKeywords, merged.Keywords...)
+	}
+	return input
+}
+
+// ParseMetaSummary extrait le sentiment et le résumé de la réponse de l'étape de réduction
+// (sections 1 et 2 du format d'analyse). Les deux sont requis.
+func ParseMetaSummary(raw string) (sentiment, summary string, err error) {
+	parsed := ParseInsightResponse(raw)
+	sentiment, summary = strings.TrimSpace(parsed.Sentiment), strings.TrimSpace(parsed.Summary)
+	if sentiment == "" || summary == "" {
+		return "", "", errors.New("réponse de synthèse globale sans sentiment ou sans résumé")
+	}
+	return sentiment, summary, nil
+}
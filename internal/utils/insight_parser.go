@@ -1,11 +1,14 @@
 package utils
 
 import (
-	// "regexp"
+	"fmt"
 	"log"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
-	// "log" // Ajout pour le débogage
+
+	"github.com/Azertdev/FiberTest/internal/models"
 )
 
 type ParsedInsight struct {
@@ -16,13 +19,81 @@ type ParsedInsight struct {
 	TopComments      []string `json:"TopComments"`       // Renommé pour correspondre à l'usage précédent ? Ou à vérifier. Prompt = "Positifs ou Constructifs"
 	FeedbackComments []string `json:"FeedbackComments"`
 	Keywords         []string `json:"Keywords"`
+	NegativeCount    int      `json:"NegativeCount"` // Nombre total de critiques (les listes fusionnées sont limitées)
+	Warnings         []string `json:"Warnings,omitempty"` // Anomalies de format relevées par ParseInsightResponse
+	// Sentiment de chaque commentaire, par numéro dans le lot (à partir de 1)
+	CommentScores map[int]CommentScore `json:"CommentScores,omitempty"`
 }
 
-func ParseInsightResponse(raw string) *ParsedInsight {
-	// Log d'entrée pour voir ce que l'on reçoit VRAIMENT de Groq
-	// log.Printf("DEBUG: ParseInsightResponse: Raw input:\n---\n%s\n---\n", raw)
+// Sections de la réponse d'analyse, dans l'ordre du prompt (voir GroqAdapter.AnalyzeComments)
+const (
+	sectionSentiment = "sentiment"
+	sectionSummary   = "summary"
+	sectionQuestions = "questions"
+	sectionNegatives = "negatives"
+	sectionPositives = "positives"
+	sectionFeedback  = "feedback"
+	sectionKeywords  = "keywords"
+	sectionPolarity  = "polarity"
+)
+
+var responseSections = []string{sectionSentiment, sectionSummary, sectionQuestions, sectionNegatives, sectionPositives, sectionFeedback, sectionKeywords}
+
+// optionalSections ne sont pas signalées quand elles manquent (réponses antérieures à leur ajout)
+var optionalSections = []string{sectionPolarity}
+
+// sectionAliases : titres reconnus, normalisés (voir normalizeHeading). Les titres du prompt
+// d'abord, puis les variantes courtes et anglaises produites par le modèle.
+var sectionAliases = map[string][]string{
+	sectionSentiment: {"sentiment general", "sentiment global", "sentiment", "overall sentiment", "general sentiment"},
+	sectionSummary:   {"resume general des commentaires", "resume general", "resume des commentaires", "resume", "summary", "general summary", "overall summary", "comments summary", "summary of comments", "general summary of comments"},
+	sectionQuestions: {"questions posees", "questions", "questions asked", "user questions"},
+	sectionNegatives: {"critiques negatives", "critiques", "commentaires negatifs", "negative comments", "negative criticism", "negative criticisms", "negative feedback", "criticisms"},
+	sectionPositives: {"points positifs ou constructifs", "points positifs", "commentaires positifs", "positive or constructive points", "positive or constructive comments", "positive points", "positive comments", "positives"},
+	sectionFeedback:  {"feedbacks specifiques ou techniques", "feedbacks specifiques", "feedbacks techniques", "feedbacks", "feedback", "specific or technical feedback", "specific or technical feedbacks", "technical feedback", "specific feedback"},
+	sectionKeywords:  {"mots cles et themes frequents", "mots cles", "themes frequents", "keywords and frequent themes", "keywords and themes", "keywords", "frequent themes"},
+	sectionPolarity:  {"polarite des commentaires", "polarite", "scores de sentiment", "comment polarity", "polarity", "sentiment scores"},
+}
+
+// polarityLabels : étiquettes acceptées dans la section de polarité (normalisées)
+var polarityLabels = map[string]string{
+	"positif": models.SentimentPositive, "positive": models.SentimentPositive,
+	"neutre": models.SentimentNeutral, "neutral": models.SentimentNeutral,
+	"negatif": models.SentimentNegative, "negative": models.SentimentNegative,
+	"mixte": models.SentimentMixed, "mixed": models.SentimentMixed,
+}
+
+// Réponses du modèle signifiant "rien à lister" (normalisées)
+var emptyListPlaceholders = []string{"aucun", "aucune", "none", "n a", "neant", "rien"}
+
+var (
+	thinkBlock      = regexp.MustCompile(`(?s)<think>.*?(</think>|$)`)
+	numberedPrefix  = regexp.MustCompile(`^\d+\s*[.)]\s*`)
+	bulletLine      = regexp.MustCompile(`^(\s*)(?:[-*•+]|\d+[.)])\s+(.*)$`)
+	horizontalRule  = regexp.MustCompile(`^\s*([-*_])\s*(\s*[-*_]\s*){2,}$`)
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+	// "[3] -0.5 négatif", "3: +0,8", "#3 – 0.2 mixte" (un tiret séparateur est suivi d'un espace)
+	polarityLine = regexp.MustCompile(`^\[?#?(\d+)\]?\s*(?:[:.)=]|[–-]\s)?\s*([+-]?\d+(?:[.,]\d+)?)\s*(.*)$`)
+	accents         = strings.NewReplacer("à", "a", "â", "a", "ä", "a", "é", "e", "è", "e", "ê", "e", "ë", "e", "î", "i", "ï", "i", "ô", "o", "ö", "o", "ù", "u", "û", "u", "ü", "u", "ç", "c", "œ", "oe")
+)
+
+type parsedItem struct {
+	text   string
+	indent int
+}
 
-	lines := strings.Split(raw, "\n")
+// rawSection accumule les lignes d'une section avant leur conversion
+type rawSection struct {
+	name    string
+	bullets []parsedItem
+	lines   []string // lignes hors liste
+}
+
+// ParseInsightResponse convertit la réponse Markdown du modèle en ParsedInsight. Les sections
+// sont reconnues par titre normalisé (niveau de titre, numérotation, gras, accents et anglais
+// indifférents) et les listes acceptent tout style de puce. Ce qui ne peut pas être rattaché à
+// une section connue est signalé dans Warnings au lieu d'être ignoré silencieusement.
+func ParseInsightResponse(raw string) *ParsedInsight {
 	parsed := &ParsedInsight{
 		// Initialiser les slices pour éviter les `null` en JSON si vides
 		QuestionComments: []string{},
@@ -31,159 +102,300 @@ func ParseInsightResponse(raw string) *ParsedInsight {
 		FeedbackComments: []string{},
 		Keywords:         []string{},
 	}
+	warn := func(format string, args ...any) {
+		parsed.Warnings = append(parsed.Warnings, fmt.Sprintf(format, args...))
+	}
 
-	var currentBlock string
-	var buffer []string
-
-	flush := func() {
-		// Log pour voir ce qui est flushé et où
-		// log.Printf("DEBUG: Flushing block '%s' with buffer: %v", currentBlock, buffer)
+	// Raisonnement des modèles de type deepseek-r1 : jamais destiné à l'utilisateur
+	raw = thinkBlock.ReplaceAllString(raw, "")
+
+	var (
+		sections  []*rawSection
+		current   *rawSection
+		unknown   string // titre de la section non reconnue en cours
+		ignored   int    // lignes ignorées sous ce titre ou avant la première section
+		preamble  = true
+		flushSkip = func() {
+			switch {
+			case ignored == 0:
+			case unknown != "":
+				warn("section non reconnue %q ignorée (%d ligne(s))", unknown, ignored)
+			default:
+				warn("texte hors section ignoré (%d ligne(s))", ignored)
+			}
+			unknown, ignored = "", 0
+		}
+	)
 
-		content := strings.TrimSpace(strings.Join(buffer, "\n")) // Join avec \n pour les paragraphes, puis trim
-		listItems := cleanBulletList(buffer) // Nettoie les items pour les listes
+	for _, line := range strings.Split(raw, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "```") || horizontalRule.MatchString(trimmed) {
+			continue
+		}
 
-		switch currentBlock {
-		case "sentiment":
-			// Gérer le cas où le texte est sur la même ligne que le titre (peu probable avec ##) ou la suivante
-			if strings.HasPrefix(content, "## 1. Sentiment Général") {
-				content = strings.TrimPrefix(content, "## 1. Sentiment Général")
+		if title, inline, isHeading := parseHeading(trimmed); isHeading {
+			name, known := matchSection(title, !numberedPrefix.MatchString(trimmed))
+			if known {
+				flushSkip()
+				preamble = false
+				current = &rawSection{name: name}
+				sections = append(sections, current)
+				if inline != "" {
+					current.lines = append(current.lines, inline)
+				}
+				continue
 			}
-			parsed.Sentiment = strings.TrimSpace(content)
-		case "summary":
-			if strings.HasPrefix(content, "## 2. Résumé Général des Commentaires") {
-				content = strings.TrimPrefix(content, "## 2. Résumé Général des Commentaires")
+			if strings.HasPrefix(trimmed, "#") {
+				// Titre explicite mais inconnu : son contenu n'appartient à aucune section
+				flushSkip()
+				preamble = false
+				current, unknown = nil, title
+				continue
 			}
-			parsed.Summary = strings.TrimSpace(content)
-		case "questions":
-            // Si Groq répond "Aucune question identifiée.", la liste sera vide.
-            if len(listItems) > 0 && listItems[0] != "Aucune question identifiée." {
-			    parsed.QuestionComments = listItems
-            }
-		case "negatives":
-             if len(listItems) > 0 && listItems[0] != "Aucune critique négative significative identifiée." {
-			    parsed.NegativeComments = listItems
-             }
-		case "positives": // Doit correspondre au nom du champ struct: TopComments ou PositiveComments? J'utilise TopComments basé sur l'ancienne structure JSON
-             if len(listItems) > 0 && listItems[0] != "Aucun commentaire positif ou constructif notable identifié." {
-			    parsed.TopComments = listItems
-             }
-		case "feedback":
-             if len(listItems) > 0 && listItems[0] != "Aucun feedback spécifique ou technique identifié." {
-			    parsed.FeedbackComments = listItems
-             }
-		case "keywords":
-			// Les mots-clés sont souvent une liste simple
-			parsed.Keywords = listItems
 		}
-		buffer = []string{} // Réinitialiser le buffer après flush
-	}
 
-	for _, line := range lines {
-		trimmedLine := strings.TrimSpace(line)
-		if trimmedLine == "" {
+		if current == nil {
+			if preamble || unknown != "" {
+				ignored++
+			}
 			continue
 		}
+		if match := bulletLine.FindStringSubmatch(line); match != nil {
+			current.bullets = append(current.bullets, parsedItem{text: cleanItem(match[2]), indent: len(match[1])})
+		} else {
+			current.lines = append(current.lines, cleanItem(trimmed))
+		}
+	}
+	flushSkip()
 
-		newBlockDetected := false
-		switch {
-		// Vérifier les en-têtes EXACTS du prompt
-		case strings.HasPrefix(trimmedLine, "## 1. Sentiment Général"):
-			flush()
-			currentBlock = "sentiment"
-			// Ajouter la ligne au buffer si le contenu n'est pas sur la même ligne (style Markdown)
-            buffer = append(buffer, strings.TrimPrefix(trimmedLine, "## 1. Sentiment Général"))
-			newBlockDetected = true
-		case strings.HasPrefix(trimmedLine, "## 2. Résumé Général des Commentaires"):
-			flush()
-			currentBlock = "summary"
-            buffer = append(buffer, strings.TrimPrefix(trimmedLine, "## 2. Résumé Général des Commentaires"))
-			newBlockDetected = true
-		case strings.HasPrefix(trimmedLine, "## 3. Questions Posées"):
-			flush()
-			currentBlock = "questions"
-			newBlockDetected = true
-             // Ne pas ajouter le titre lui-même au buffer des listes
-		case strings.HasPrefix(trimmedLine, "## 4. Critiques Négatives"):
-			flush()
-			currentBlock = "negatives"
-			newBlockDetected = true
-		case strings.HasPrefix(trimmedLine, "## 5. Points Positifs ou Constructifs"): // Le nom du bloc doit correspondre à la clé dans le switch `flush`
-			flush()
-			currentBlock = "positives" // --> Mappe vers TopComments dans le switch
-			newBlockDetected = true
-		case strings.HasPrefix(trimmedLine, "## 6. Feedbacks Spécifiques ou Techniques"):
-			flush()
-			currentBlock = "feedback"
-			newBlockDetected = true
-		case strings.HasPrefix(trimmedLine, "## 7. Mots-clés et Thèmes Fréquents"):
-			flush()
-			currentBlock = "keywords"
-			newBlockDetected = true
+	found := map[string]bool{}
+	for _, section := range sections {
+		if found[section.name] {
+			warn("section %s présente plusieurs fois : contenus cumulés", section.name)
 		}
+		found[section.name] = true
+		applySection(parsed, section, warn)
+	}
+	for _, name := range responseSections {
+		if !found[name] {
+			warn("section %s absente de la réponse", name)
+		}
+	}
+	parsed.NegativeCount = len(parsed.NegativeComments)
+	return parsed
+}
 
-		// Si ce n'est pas un nouveau bloc, ajouter la ligne au buffer
-		// Sauf si c'est le titre lui-même qu'on vient d'ajouter
-		if !newBlockDetected {
-             // Vérifier si c'est un item de liste (commence par "- ")
-			 if strings.HasPrefix(trimmedLine, "- ") {
-                 // Ajouter seulement le contenu de l'item, sans le "- "
-				 buffer = append(buffer, strings.TrimSpace(strings.TrimPrefix(trimmedLine, "- ")))
-			 } else if currentBlock == "sentiment" || currentBlock == "summary" {
-                 // Pour les blocs texte (sentiment, summary), ajouter la ligne entière
-                 buffer = append(buffer, trimmedLine)
-             }
+// parseHeading reconnaît "## 3. Titre", "**Titre**", "**Titre :** contenu" et "3. Titre".
+// inline est le contenu éventuel placé sur la même ligne que le titre.
+func parseHeading(line string) (title, inline string, ok bool) {
+	switch {
+	case strings.HasPrefix(line, "#"):
+		title = strings.TrimSpace(strings.TrimLeft(line, "#"))
+	case strings.HasPrefix(line, "**") || strings.HasPrefix(line, "__"):
+		marker := line[:2]
+		end := strings.Index(line[2:], marker)
+		if end < 0 {
+			return "", "", false
 		}
+		title = line[2 : 2+end]
+		inline = strings.TrimSpace(line[4+end:])
+	case numberedPrefix.MatchString(line):
+		title = line
+	default:
+		return "", "", false
 	}
 
-	flush() // Appeler flush une dernière fois pour la dernière section
+	title = strings.Trim(title, "*_ ")
+	title = numberedPrefix.ReplaceAllString(title, "")
+	// "Sentiment Général : Positif" ou "**Sentiment Général :** Positif"
+	if before, after, found := strings.Cut(title, ":"); found {
+		title = before
+		if rest := strings.TrimSpace(after); rest != "" {
+			inline = strings.TrimSpace(rest + " " + inline)
+		}
+	}
+	inline = strings.TrimSpace(strings.TrimLeft(inline, ":"))
+	return strings.Trim(title, "*_ "), cleanItem(inline), title != ""
+}
 
-	// Log de sortie pour voir le résultat du parsing
-	// log.Printf("DEBUG: ParseInsightResponse: Parsed result: %+v", parsed)
+// matchSection associe un titre à une section. lenient (titre Markdown ou en gras) accepte un
+// titre qui commence par un alias ("Critiques négatives (commentaires insatisfaits)") ; un simple
+// "3. ..." doit correspondre exactement, pour ne pas confondre une question numérotée avec un titre.
+func matchSection(title string, lenient bool) (string, bool) {
+	normalized := normalizeHeading(title)
+	best, bestLength := "", 0
+	for _, name := range append(responseSections, optionalSections...) {
+		for _, alias := range sectionAliases[name] {
+			matches := normalized == alias || (lenient && strings.HasPrefix(normalized, alias+" "))
+			if matches && len(alias) > bestLength {
+				best, bestLength = name, len(alias)
+			}
+		}
+	}
+	return best, best != ""
+}
 
-	return parsed
+// normalizeHeading : minuscules, sans accents ni ponctuation, espaces simples
+func normalizeHeading(value string) string {
+	value = accents.Replace(strings.ToLower(value))
+	return strings.TrimSpace(nonAlphanumeric.ReplaceAllString(value, " "))
 }
 
-// Modifié pour gérer les cas "Aucune..." et nettoyer le préfixe "-"
-func cleanBulletList(lines []string) []string {
-	var cleaned []string
-	// re := regexp.MustCompile(`\s*\(.*?\)`) // Regex pour supprimer "(X mentions)" - peut-être pas nécessaire/utile
+// cleanItem retire les espaces et l'emphase Markdown qui entoure tout l'élément
+func cleanItem(value string) string {
+	value = strings.TrimSpace(value)
+	for _, marker := range []string{"**", "__", "*", "_", "`"} {
+		if len(value) > 2*len(marker) && strings.HasPrefix(value, marker) && strings.HasSuffix(value, marker) {
+			value = strings.TrimSpace(value[len(marker) : len(value)-len(marker)])
+		}
+	}
+	return value
+}
 
-	// Premier élément peut être le reste du titre ou une phrase comme "Aucune..."
-	if len(lines) == 0 {
-		return cleaned
+func isEmptyListPlaceholder(value string) bool {
+	normalized := normalizeHeading(value)
+	for _, placeholder := range emptyListPlaceholders {
+		if normalized == placeholder || strings.HasPrefix(normalized, placeholder+" ") && !strings.ContainsAny(value, `"«`) {
+			return true
+		}
 	}
+	return false
+}
 
-    // Supprimer les lignes vides potentielles ajoutées au buffer
-    var nonEmptyLines []string
-    for _, l := range lines {
-        if strings.TrimSpace(l) != "" {
-            nonEmptyLines = append(nonEmptyLines, strings.TrimSpace(l))
-        }
-    }
-    lines = nonEmptyLines
+func applySection(parsed *ParsedInsight, section *rawSection, warn func(format string, args ...any)) {
+	switch section.name {
+	case sectionPolarity:
+		applyPolarity(parsed, section, warn)
+		return
+	case sectionSentiment, sectionSummary:
+		parts := append([]string{}, section.lines...)
+		for _, bullet := range section.bullets {
+			parts = append(parts, bullet.text)
+		}
+		text := strings.Join(parts, "\n")
+		if section.name == sectionSentiment {
+			parsed.Sentiment = joinText(parsed.Sentiment, text)
+		} else {
+			parsed.Summary = joinText(parsed.Summary, text)
+		}
+		return
+	}
 
+	items := listItems(section, warn)
+	switch section.name {
+	case sectionQuestions:
+		parsed.QuestionComments = append(parsed.QuestionComments, items...)
+	case sectionNegatives:
+		parsed.NegativeComments = append(parsed.NegativeComments, items...)
+	case sectionPositives:
+		parsed.TopComments = append(parsed.TopComments, items...)
+	case sectionFeedback:
+		parsed.FeedbackComments = append(parsed.FeedbackComments, items...)
+	case sectionKeywords:
+		parsed.Keywords = append(parsed.Keywords, items...)
+	}
+}
 
-	// Gérer explicitement les cas "Aucune..."
-	if len(lines) == 1 {
-		lineLower := strings.ToLower(lines[0])
-		if strings.HasPrefix(lineLower, "aucune") || strings.HasPrefix(lineLower, "aucun") {
-            // Retourner la phrase telle quelle ou une liste vide ?
-            // Ici on retourne la phrase pour info, mais le code appelant gère déjà ça
-			// return []string{lines[0]}
-            return []string{} // Préférable: une liste vide si "Aucun..."
+// listItems retourne les éléments d'une section de liste. Une puce terminée par ":" suivie de
+// sous-puces est un intitulé de groupe : ses sous-puces sont des éléments. Les sous-puces d'un
+// élément sont des précisions sur celui-ci et ne sont pas reprises. Sans aucune puce, chaque
+// ligne est un élément.
+func listItems(section *rawSection, warn func(format string, args ...any)) []string {
+	items := []string{}
+	if len(section.bullets) == 0 {
+		for _, line := range section.lines {
+			if isEmptyListPlaceholder(line) || strings.HasSuffix(line, ":") {
+				continue
+			}
+			items = append(items, line)
 		}
+		return items
+	}
+
+	type parent struct {
+		indent  int
+		isLabel bool
 	}
+	var parents []parent
+	details := 0
+	for i, bullet := range section.bullets {
+		for len(parents) > 0 && parents[len(parents)-1].indent >= bullet.indent {
+			parents = parents[:len(parents)-1]
+		}
+		isLabel := strings.HasSuffix(bullet.text, ":") && i+1 < len(section.bullets) && section.bullets[i+1].indent > bullet.indent
+		underItem := len(parents) > 0 && !parents[len(parents)-1].isLabel
+		parents = append(parents, parent{indent: bullet.indent, isLabel: isLabel && !underItem})
 
+		switch {
+		case underItem:
+			details++
+		case bullet.text == "" || isLabel:
+		case len(section.bullets) == 1 && isEmptyListPlaceholder(bullet.text):
+		default:
+			items = append(items, bullet.text)
+		}
+	}
+	if details > 0 {
+		warn("section %s : %d sous-élément(s) de précision ignoré(s)", section.name, details)
+	}
 
-	for _, l := range lines {
-        // Nettoyage plus simple : juste supprimer les espaces superflus
-		cleanedLine := strings.TrimSpace(l)
-		// cleanedLine = re.ReplaceAllString(cleanedLine, "") // Appliquer regex si besoin
-		if cleanedLine != "" {
-			cleaned = append(cleaned, cleanedLine)
+	ignored := 0
+	for _, line := range section.lines {
+		if !isEmptyListPlaceholder(line) && !strings.HasSuffix(line, ":") {
+			ignored++
 		}
 	}
-	return cleaned
+	if ignored > 0 {
+		warn("section %s : %d ligne(s) hors liste ignorée(s)", section.name, ignored)
+	}
+	return items
+}
+
+// applyPolarity lit une ligne "[numéro] score étiquette" par commentaire ; l'étiquette est
+// facultative (déduite du score)
+func applyPolarity(parsed *ParsedInsight, section *rawSection, warn func(format string, args ...any)) {
+	lines := append([]string{}, section.lines...)
+	for _, bullet := range section.bullets {
+		lines = append(lines, bullet.text)
+	}
+	invalid := 0
+	for _, line := range lines {
+		match := polarityLine.FindStringSubmatch(line)
+		if match == nil {
+			if !isEmptyListPlaceholder(line) && !strings.HasSuffix(line, ":") {
+				invalid++
+			}
+			continue
+		}
+		index, errIndex := strconv.Atoi(match[1])
+		polarity, errPolarity := strconv.ParseFloat(strings.Replace(match[2], ",", ".", 1), 64)
+		if errIndex != nil || errPolarity != nil || index < 1 {
+			invalid++
+			continue
+		}
+		label := ""
+		if words := strings.Fields(normalizeHeading(match[3])); len(words) > 0 {
+			label = polarityLabels[words[0]]
+		}
+		if parsed.CommentScores == nil {
+			parsed.CommentScores = map[int]CommentScore{}
+		}
+		parsed.CommentScores[index] = NewCommentScore(polarity, label)
+	}
+	if invalid > 0 {
+		warn("section %s : %d ligne(s) illisible(s) ignorée(s)", section.name, invalid)
+	}
+}
+
+func joinText(existing, addition string) string {
+	addition = strings.TrimSpace(addition)
+	if existing == "" {
+		return addition
+	}
+	if addition == "" {
+		return existing
+	}
+	return existing + "\n" + addition
 }
 
 type keywordFrequency struct {
@@ -191,8 +403,16 @@ type keywordFrequency struct {
 	Count   int
 }
 
+// MergeLimits borne la taille des listes de l'insight fusionné
+type MergeLimits struct {
+	MaxListItems int // éléments max par liste d'exemples (top, critiques, questions, feedbacks)
+	MaxKeywords  int // mots-clés max, classés par fréquence
+}
+
+var DefaultMergeLimits = MergeLimits{MaxListItems: 10, MaxKeywords: 15}
+
 // mergeParsedInsights combine les résultats de plusieurs analyses partielles de manière plus intelligente.
-func MergeParsedInsights(partials []*ParsedInsight) *ParsedInsight {
+func MergeParsedInsights(partials []*ParsedInsight, limits MergeLimits) *ParsedInsight {
 	totalPartials := len(partials)
 	if totalPartials == 0 {
 		log.Println("WARN: mergeParsedInsights appelé avec une liste vide de partiels.")
@@ -208,7 +428,8 @@ func MergeParsedInsights(partials []*ParsedInsight) *ParsedInsight {
 	allNegativeComments := []string{}
 	allQuestionComments := []string{}
 	allFeedbackComments := []string{}
-	keywordsCount := make(map[string]int) // map[lowercase_keyword]count
+	keywordsCount := make(map[string]int)               // map[NormalizeKeyword]count
+	keywordSurfaces := make(map[string]map[string]int) // formes rencontrées par clé ("vidéos", "video"...)
 
 	for i, p := range partials {
 		if p == nil {
@@ -234,40 +455,53 @@ func MergeParsedInsights(partials []*ParsedInsight) *ParsedInsight {
 		for _, item := range p.QuestionComments { if strings.TrimSpace(item) != "" { allQuestionComments = append(allQuestionComments, item) } }
 		for _, item := range p.FeedbackComments { if strings.TrimSpace(item) != "" { allFeedbackComments = append(allFeedbackComments, item) } }
 
-		// Compter les mots-clés (en minuscule, ignorer si vide)
+		// Compter les mots-clés par clé normalisée (une fois par lot, ignorer si vide)
+		seenKeywords := make(map[string]bool)
 		for _, kw := range p.Keywords {
             trimmedKw := strings.ToLower(strings.TrimSpace(kw))
-			if trimmedKw != "" {
-				keywordsCount[trimmedKw]++
+			key := NormalizeKeyword(trimmedKw)
+			if key == "" {
+				continue
+			}
+			if keywordSurfaces[key] == nil {
+				keywordSurfaces[key] = make(map[string]int)
+			}
+			keywordSurfaces[key][trimmedKw]++
+			if !seenKeywords[key] {
+				seenKeywords[key] = true
+				keywordsCount[key]++
 			}
 		}
 	}
 
     // --- Traitement et Synthèse ---
 
-	// 1. Déterminer le Sentiment Global (Majorité)
+	// 1. Déterminer le Sentiment Global (Majorité ; égalité au maximum : "Partagé / Mixte")
 	finalSentiment := "Indéterminé" // Valeur par défaut
-	maxCount := 0
 	if len(sentimentCounts) > 0 {
+		maxCount, leaders := 0, []string{}
 		for sentiment, count := range sentimentCounts {
-			if count > maxCount {
-				maxCount = count
-				finalSentiment = sentiment
-			} else if count == maxCount {
-				// Gérer les égalités (ex: si Positif et Négatif ont le même compte max)
-                // On pourrait choisir "Partagé" ou une autre logique. Pour l'instant, on garde le premier trouvé.
-                // Ou on peut explicitement définir une priorité ou un état mixte.
-                finalSentiment = "Partagé / Mixte" // Exemple pour gérer une égalité simple
+			switch {
+			case count > maxCount:
+				maxCount, leaders = count, []string{sentiment}
+			case count == maxCount:
+				leaders = append(leaders, sentiment)
 			}
 		}
-        log.Printf("INFO: Fusion: Sentiment final déterminé: %s (basé sur %v)", finalSentiment, sentimentCounts)
+		// Décision sur l'ensemble des ex aequo : indépendante de l'ordre d'itération de la map
+		if len(leaders) == 1 {
+			finalSentiment = leaders[0]
+		} else {
+			finalSentiment = "Partagé / Mixte"
+		}
+		log.Printf("INFO: Fusion: Sentiment final déterminé: %s (basé sur %v)", finalSentiment, sentimentCounts)
 	} else {
-        log.Println("WARN: Fusion: Aucun sentiment trouvé dans les partiels.")
-    }
+		log.Println("WARN: Fusion: Aucun sentiment trouvé dans les partiels.")
+	}
 
 
 	// 2. Déterminer le Résumé Global (Stratégie simple: prendre le premier non vide)
-	// TODO: Amélioration possible -> Méta-résumé par IA ou concaténation intelligente.
+	// Repli de la synthèse globale par IA (services.AnalysisOptions.MetaSummary, voir ParseMetaSummary).
 	finalSummary := "Impossible de générer un résumé global." // Défaut
 	if len(allSummaries) > 0 {
 		finalSummary = allSummaries[0] // Prend le résumé du premier chunk qui en avait un
@@ -278,7 +512,7 @@ func MergeParsedInsights(partials []*ParsedInsight) *ParsedInsight {
 
 
     // 3. Dédoublonner et Limiter les Listes d'Exemples
-    limitPerList := 10 // Limite max d'éléments à retourner par liste (configurable !)
+    limitPerList := limits.MaxListItems
     deduplicateAndLimit := func(items []string, limit int) []string {
         seen := make(map[string]bool)
         result := []string{}
@@ -298,21 +532,27 @@ func MergeParsedInsights(partials []*ParsedInsight) *ParsedInsight {
     finalNegativeComments := deduplicateAndLimit(allNegativeComments, limitPerList)
     finalQuestionComments := deduplicateAndLimit(allQuestionComments, limitPerList)
     finalFeedbackComments := deduplicateAndLimit(allFeedbackComments, limitPerList)
+    // Compter toutes les critiques distinctes avant limitation (utilisé par les règles d'alerte)
+    negativeCount := len(deduplicateAndLimit(allNegativeComments, len(allNegativeComments)))
     log.Printf("INFO: Fusion: Listes dédoublonnées et limitées à %d éléments.", limitPerList)
 
 
     // 4. Classer les Mots-clés par Fréquence et Limiter
     rankedKeywords := []keywordFrequency{}
-    for kw, count := range keywordsCount {
-        rankedKeywords = append(rankedKeywords, keywordFrequency{Keyword: kw, Count: count})
+    for key, count := range keywordsCount {
+        // Forme affichée : la plus fréquente parmi les variantes regroupées
+        rankedKeywords = append(rankedKeywords, keywordFrequency{Keyword: mostFrequentSurface(keywordSurfaces[key]), Count: count})
     }
-    // Trier par fréquence (décroissant)
+    // Trier par fréquence (décroissant), puis par ordre alphabétique (indépendant de la map)
     sort.Slice(rankedKeywords, func(i, j int) bool {
-        return rankedKeywords[i].Count > rankedKeywords[j].Count
+        if rankedKeywords[i].Count != rankedKeywords[j].Count {
+            return rankedKeywords[i].Count > rankedKeywords[j].Count
+        }
+        return rankedKeywords[i].Keyword < rankedKeywords[j].Keyword
     })
 
     finalKeywords := []string{}
-    limitKeywords := 15 // Limite max de mots-clés à retourner (configurable !)
+    limitKeywords := limits.MaxKeywords
     for i, kwf := range rankedKeywords {
         if i >= limitKeywords {
             break
@@ -331,6 +571,7 @@ func MergeParsedInsights(partials []*ParsedInsight) *ParsedInsight {
 		QuestionComments: finalQuestionComments,
 		FeedbackComments: finalFeedbackComments,
 		Keywords:         finalKeywords,
+		NegativeCount:    negativeCount,
 	}
 
 	log.Printf("INFO: Fusion terminée.")
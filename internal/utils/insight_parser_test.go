@@ -0,0 +1,73 @@
+// internal/utils/insight_parser_test.go
+package utils
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// go test ./internal/utils -run TestParseInsightResponseGolden -update régénère les fichiers .golden.json
+var update = flag.Bool("update", false, "régénère les fichiers golden de testdata/insights")
+
+// Corpus de réponses du modèle (réelles et synthétiques) : chaque testdata/insights/<cas>.md
+// est comparé au ParsedInsight attendu de <cas>.golden.json
+func TestParseInsightResponseGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "insights", "*.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inputs) == 0 {
+		t.Fatal("aucun cas dans testdata/insights")
+	}
+	for _, input := range inputs {
+		name := strings.TrimSuffix(filepath.Base(input), ".md")
+		t.Run(name, func(t *testing.T) {
+			raw, err := os.ReadFile(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.MarshalIndent(ParseInsightResponse(string(raw)), "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			golden := strings.TrimSuffix(input, ".md") + ".golden.json"
+			if *update {
+				if err := os.WriteFile(golden, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(golden)
+			if err != nil {
+				t.Fatalf("fichier golden absent (lancer avec -update) : %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("résultat différent de %s\n--- obtenu ---\n%s\n--- attendu ---\n%s", golden, got, want)
+			}
+		})
+	}
+}
+
+func TestParseInsightResponseNumberedLineIsNotHeading(t *testing.T) {
+	// Une question numérotée qui commence comme un titre de section reste un élément de la liste
+	parsed := ParseInsightResponse("## 3. Questions Posées\n1. Questions sur le prix\n2. Feedback sur le son\n")
+	if len(parsed.QuestionComments) != 2 {
+		t.Fatalf("QuestionComments = %q, attendu 2 éléments", parsed.QuestionComments)
+	}
+}
+
+func TestParseInsightResponseEmpty(t *testing.T) {
+	parsed := ParseInsightResponse("")
+	if parsed.QuestionComments == nil || parsed.Keywords == nil {
+		t.Fatal("les listes doivent être initialisées")
+	}
+	if len(parsed.Warnings) != len(responseSections) {
+		t.Fatalf("Warnings = %q, attendu une section absente par section", parsed.Warnings)
+	}
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ErrJWTKeysNotConfigured est retournée si aucune clé n'est fournie (voir config.LoadJWTKeySet)
+var ErrJWTKeysNotConfigured = errors.New("clés JWT non configurées")
+
+const (
+	JWTIssuer   = "engagesense"
+	JWTAudience = "engagesense-api"
+	JWTLifetime = time.Hour
+)
+
+// Claims est la structure unique des tokens d'accès, partagée par l'émission et la vérification.
+// Subject contient l'UUID de l'utilisateur.
+type Claims struct {
+	Role string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// Principal est l'identité authentifiée placée dans le contexte de la requête par le middleware
+type Principal struct {
+	UserID    uuid.UUID
+	Role      string
+	TokenID   string    // jti du token d'accès (révocation)
+	ExpiresAt time.Time // expiration du token d'accès
+	// APIKeyID est renseigné pour une requête authentifiée par clé d'API (uuid.Nil pour un JWT)
+	APIKeyID uuid.UUID
+	// Scopes de la clé d'API ; nil pour un JWT, qui donne un accès complet
+	Scopes []string
+}
+
+// IsAPIKey indique si la requête est authentifiée par une clé d'API plutôt que par un JWT
+func (p Principal) IsAPIKey() bool {
+	return p.APIKeyID != uuid.Nil
+}
+
+// HasScope : un JWT a tous les droits de l'utilisateur, une clé d'API seulement ses scopes
+func (p Principal) HasScope(scope string) bool {
+	if !p.IsAPIKey() {
+		return true
+	}
+	for _, granted := range p.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidSubject est retournée quand le "sub" du token n'est pas un UUID valide
+var ErrInvalidSubject = errors.New("sujet du token invalide")
+
+// GenerateJWT signe un token d'accès pour l'utilisateur avec la clé courante de keys
+func GenerateJWT(keys *KeySet, userID uuid.UUID, role string) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		Role: role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   userID.String(),
+			Issuer:    JWTIssuer,
+			Audience:  jwt.ClaimStrings{JWTAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(JWTLifetime)),
+		},
+	}
+
+	if keys == nil {
+		return "", ErrJWTKeysNotConfigured
+	}
+	return keys.Sign(claims)
+}
+
+// VerifyJWT valide un token JWT (signature, expiration, émetteur, audience) et retourne ses claims
+func VerifyJWT(keys *KeySet, tokenString string) (*Claims, error) {
+	if keys == nil {
+		return nil, ErrJWTKeysNotConfigured
+	}
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, keys.Keyfunc,
+		jwt.WithValidMethods(keys.Algorithms()),
+		jwt.WithIssuer(JWTIssuer),
+		jwt.WithAudience(JWTAudience),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Principal convertit les claims en identité typée ; le sujet doit être un UUID non nul
+func (c *Claims) Principal() (Principal, error) {
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil || userID == uuid.Nil {
+		return Principal{}, fmt.Errorf("%w: %q", ErrInvalidSubject, c.Subject)
+	}
+	if c.ID == "" {
+		return Principal{}, errors.New("token sans identifiant (jti)")
+	}
+	principal := Principal{UserID: userID, Role: c.Role, TokenID: c.ID}
+	if c.ExpiresAt != nil {
+		principal.ExpiresAt = c.ExpiresAt.Time
+	}
+	return principal, nil
+}
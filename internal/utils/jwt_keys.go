@@ -0,0 +1,257 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Algorithmes de signature supportés pour les tokens d'accès
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// SigningKey est une clé identifiée par son kid. PrivateKey est nil pour une clé de vérification seule
+// (ancienne clé conservée pendant une rotation, ou clé publique d'un autre service).
+type SigningKey struct {
+	ID         string
+	Algorithm  string
+	PrivateKey any // []byte (HS256), *rsa.PrivateKey ou ed25519.PrivateKey
+	PublicKey  any // []byte (HS256), *rsa.PublicKey ou ed25519.PublicKey
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	return jwt.GetSigningMethod(k.Algorithm)
+}
+
+// KeySet regroupe la clé active (signature) et toutes les clés acceptées en vérification
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+// NewKeySet valide les clés et sélectionne la clé de signature activeKID
+func NewKeySet(activeKID string, keys []*SigningKey) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]*SigningKey, len(keys))}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, errors.New("clé JWT sans identifiant (kid)")
+		}
+		if _, exists := set.keys[key.ID]; exists {
+			return nil, fmt.Errorf("kid JWT dupliqué: %q", key.ID)
+		}
+		if err := validateSigningKey(key); err != nil {
+			return nil, fmt.Errorf("clé JWT %q: %w", key.ID, err)
+		}
+		set.keys[key.ID] = key
+	}
+
+	active, ok := set.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("clé JWT active %q introuvable", activeKID)
+	}
+	if active.PrivateKey == nil {
+		return nil, fmt.Errorf("clé JWT active %q sans clé privée", activeKID)
+	}
+	set.active = active
+	return set, nil
+}
+
+func validateSigningKey(key *SigningKey) error {
+	switch key.Algorithm {
+	case AlgHS256:
+		secret, ok := key.PublicKey.([]byte)
+		if !ok || len(secret) < 32 {
+			return errors.New("secret HS256 absent ou trop court (32 octets minimum)")
+		}
+		if key.PrivateKey == nil {
+			key.PrivateKey = secret // un secret HMAC sert à signer et à vérifier
+		}
+	case AlgRS256:
+		if _, ok := key.PublicKey.(*rsa.PublicKey); !ok {
+			return errors.New("clé publique RSA attendue")
+		}
+		if key.PrivateKey != nil {
+			if _, ok := key.PrivateKey.(*rsa.PrivateKey); !ok {
+				return errors.New("clé privée RSA attendue")
+			}
+		}
+	case AlgEdDSA:
+		if _, ok := key.PublicKey.(ed25519.PublicKey); !ok {
+			return errors.New("clé publique Ed25519 attendue")
+		}
+		if key.PrivateKey != nil {
+			if _, ok := key.PrivateKey.(ed25519.PrivateKey); !ok {
+				return errors.New("clé privée Ed25519 attendue")
+			}
+		}
+	default:
+		return fmt.Errorf("algorithme non supporté: %q", key.Algorithm)
+	}
+	return nil
+}
+
+// NewHMACKey crée une clé HS256 à partir d'un secret partagé
+func NewHMACKey(kid string, secret []byte) *SigningKey {
+	return &SigningKey{ID: kid, Algorithm: AlgHS256, PrivateKey: secret, PublicKey: secret}
+}
+
+// ParsePEMKey crée une clé RS256 ou EdDSA depuis un bloc PEM : clé privée (PKCS#8 ou PKCS#1)
+// pour signer et vérifier, ou clé publique (PKIX) pour vérifier seulement.
+func ParsePEMKey(kid string, pemBytes []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("aucun bloc PEM trouvé")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("type de bloc PEM non supporté: %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("clé PEM invalide: %w", err)
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: kid, Algorithm: AlgRS256, PrivateKey: k, PublicKey: &k.PublicKey}, nil
+	case *rsa.PublicKey:
+		return &SigningKey{ID: kid, Algorithm: AlgRS256, PublicKey: k}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: kid, Algorithm: AlgEdDSA, PrivateKey: k, PublicKey: k.Public().(ed25519.PublicKey)}, nil
+	case ed25519.PublicKey:
+		return &SigningKey{ID: kid, Algorithm: AlgEdDSA, PublicKey: k}, nil
+	}
+	return nil, fmt.Errorf("type de clé non supporté: %T", parsed)
+}
+
+// Sign signe les claims avec la clé active et ajoute son kid dans l'en-tête
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.active.method(), claims)
+	token.Header["kid"] = s.active.ID
+	return token.SignedString(s.active.PrivateKey)
+}
+
+// Keyfunc choisit la clé de vérification d'après le kid du token ; l'algorithme doit correspondre à la clé
+func (s *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("kid inconnu: %q", kid)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("algorithme %q inattendu pour la clé %q", token.Method.Alg(), kid)
+	}
+	return key.PublicKey, nil
+}
+
+// Algorithms retourne les algorithmes acceptés en vérification
+func (s *KeySet) Algorithms() []string {
+	seen := map[string]bool{}
+	var algs []string
+	for _, key := range s.keys {
+		if !seen[key.Algorithm] {
+			seen[key.Algorithm] = true
+			algs = append(algs, key.Algorithm)
+		}
+	}
+	sort.Strings(algs)
+	return algs
+}
+
+// JWK est la représentation publique d'une clé (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKey décode la clé publique d'un JWK RSA, EC (P-256) ou OKP (Ed25519),
+// par exemple pour vérifier les ID tokens d'un fournisseur OpenID Connect
+func (k JWK) PublicKey() (any, error) {
+	decode := base64.RawURLEncoding.DecodeString
+	switch k.Kty {
+	case "RSA":
+		n, err := decode(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("JWK %q: modulo invalide: %w", k.Kid, err)
+		}
+		e, err := decode(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("JWK %q: exposant invalide: %w", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("JWK %q: courbe non supportée: %s", k.Kid, k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("JWK %q: coordonnée x invalide: %w", k.Kid, err)
+		}
+		y, err := decode(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("JWK %q: coordonnée y invalide: %w", k.Kid, err)
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("JWK %q: courbe non supportée: %s", k.Kid, k.Crv)
+		}
+		x, err := decode(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("JWK %q: clé Ed25519 invalide", k.Kid)
+		}
+		return ed25519.PublicKey(x), nil
+	}
+	return nil, fmt.Errorf("JWK %q: type de clé non supporté: %s", k.Kid, k.Kty)
+}
+
+// JWKS retourne les clés publiques asymétriques. Les secrets HS256 ne sont jamais exposés.
+func (s *KeySet) JWKS() []JWK {
+	jwks := []JWK{}
+	for _, key := range s.keys {
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwks = append(jwks, JWK{
+				Kty: "RSA", Kid: key.ID, Alg: key.Algorithm, Use: "sig",
+				N: base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E: base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			jwks = append(jwks, JWK{
+				Kty: "OKP", Kid: key.ID, Alg: key.Algorithm, Use: "sig",
+				Crv: "Ed25519",
+				X:   base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	sort.Slice(jwks, func(i, j int) bool { return jwks[i].Kid < jwks[j].Kid })
+	return jwks
+}
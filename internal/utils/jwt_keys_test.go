@@ -0,0 +1,304 @@
+// internal/utils/jwt_keys_test.go
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testHMACSecret = "0123456789abcdef0123456789abcdef"
+
+// La génération d'une clé RSA est lente : une seule clé pour tous les tests
+var testRSAKey = sync.OnceValue(func() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return key
+})
+
+func testEd25519Key(t *testing.T) ed25519.PrivateKey {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func testClaims() Claims {
+	now := time.Now()
+	return Claims{Role: "user", RegisteredClaims: jwt.RegisteredClaims{
+		ID:        uuid.NewString(),
+		Subject:   uuid.NewString(),
+		Issuer:    JWTIssuer,
+		Audience:  jwt.ClaimStrings{JWTAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+	}}
+}
+
+// signWith signe les claims avec method et key en plaçant kid dans l'en-tête (vide : pas de kid)
+func signWith(t *testing.T, method jwt.SigningMethod, kid string, key any) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, testClaims())
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("signature %s: %v", method.Alg(), err)
+	}
+	return signed
+}
+
+func pemBlock(t *testing.T, blockType string, der []byte) []byte {
+	t.Helper()
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
+
+func TestNewKeySetValidatesKeys(t *testing.T) {
+	rsaKey := testRSAKey()
+	cases := []struct {
+		name   string
+		active string
+		keys   []*SigningKey
+		want   string // vide : jeu de clés valide
+	}{
+		{"HS256 seule", "h", []*SigningKey{NewHMACKey("h", []byte(testHMACSecret))}, ""},
+		{"RSA active, HS256 en vérification", "r", []*SigningKey{
+			{ID: "r", Algorithm: AlgRS256, PrivateKey: rsaKey, PublicKey: &rsaKey.PublicKey},
+			NewHMACKey("h", []byte(testHMACSecret)),
+		}, ""},
+		{"secret trop court", "h", []*SigningKey{NewHMACKey("h", []byte("court"))}, "trop court"},
+		{"kid vide", "", []*SigningKey{NewHMACKey("", []byte(testHMACSecret))}, "sans identifiant"},
+		{"kid dupliqué", "h", []*SigningKey{NewHMACKey("h", []byte(testHMACSecret)), NewHMACKey("h", []byte(testHMACSecret))}, "dupliqué"},
+		{"clé active absente", "autre", []*SigningKey{NewHMACKey("h", []byte(testHMACSecret))}, "introuvable"},
+		{"clé active sans clé privée", "r", []*SigningKey{{ID: "r", Algorithm: AlgRS256, PublicKey: &rsaKey.PublicKey}}, "sans clé privée"},
+		{"algorithme inconnu", "x", []*SigningKey{{ID: "x", Algorithm: "HS512", PublicKey: []byte(testHMACSecret)}}, "non supporté"},
+		{"clé d'un autre algorithme", "r", []*SigningKey{{ID: "r", Algorithm: AlgRS256, PublicKey: []byte(testHMACSecret)}}, "RSA attendue"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewKeySet(tc.active, tc.keys)
+			if tc.want == "" {
+				if err != nil {
+					t.Fatalf("jeu de clés refusé: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("erreur %v, attendu %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestVerifyJWTSelectsKeyByKidAndAlgorithm(t *testing.T) {
+	rsaKey := testRSAKey()
+	edKey := testEd25519Key(t)
+	keys, err := NewKeySet("h", []*SigningKey{
+		NewHMACKey("h", []byte(testHMACSecret)),
+		{ID: "r", Algorithm: AlgRS256, PrivateKey: rsaKey, PublicKey: &rsaKey.PublicKey},
+		{ID: "e", Algorithm: AlgEdDSA, PrivateKey: edKey, PublicKey: edKey.Public()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaPublicPEM, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"HS256 avec sa clé", signWith(t, jwt.SigningMethodHS256, "h", []byte(testHMACSecret)), true},
+		{"RS256 avec sa clé", signWith(t, jwt.SigningMethodRS256, "r", rsaKey), true},
+		{"EdDSA avec sa clé", signWith(t, jwt.SigningMethodEdDSA, "e", edKey), true},
+		{"kid inconnu", signWith(t, jwt.SigningMethodHS256, "inconnu", []byte(testHMACSecret)), false},
+		{"sans kid", signWith(t, jwt.SigningMethodHS256, "", []byte(testHMACSecret)), false},
+		// Confusion d'algorithme : HMAC dont le secret est la clé publique RSA, publiée par le JWKS
+		{"HS256 sur la clé RSA", signWith(t, jwt.SigningMethodHS256, "r", pemBlock(t, "PUBLIC KEY", rsaPublicPEM)), false},
+		{"RS256 sur la clé HS256", signWith(t, jwt.SigningMethodRS256, "h", rsaKey), false},
+		{"EdDSA sur la clé RSA", signWith(t, jwt.SigningMethodEdDSA, "r", edKey), false},
+		{"alg none", signWith(t, jwt.SigningMethodNone, "h", jwt.UnsafeAllowNoneSignatureType), false},
+		{"signé par une autre clé", signWith(t, jwt.SigningMethodHS256, "h", []byte(strings.Repeat("x", 32))), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			claims, err := VerifyJWT(keys, tc.token)
+			if tc.valid && (err != nil || claims.Subject == "") {
+				t.Fatalf("token valide refusé: %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("token accepté")
+			}
+		})
+	}
+}
+
+func TestVerifyJWTAcceptsOldKeyAfterRotation(t *testing.T) {
+	oldKey := NewHMACKey("2026-04", []byte(testHMACSecret))
+	before, err := NewKeySet("2026-04", []*SigningKey{oldKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldToken, err := GenerateJWT(before, uuid.New(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotation : nouvelle clé active, l'ancienne reste acceptée en vérification
+	edKey := testEd25519Key(t)
+	after, err := NewKeySet("2026-10", []*SigningKey{
+		{ID: "2026-10", Algorithm: AlgEdDSA, PrivateKey: edKey, PublicKey: edKey.Public()},
+		NewHMACKey("2026-04", []byte(testHMACSecret)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyJWT(after, oldToken); err != nil {
+		t.Fatalf("token signé avant la rotation refusé: %v", err)
+	}
+	newToken, err := GenerateJWT(after, uuid.New(), "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, _, err := jwt.NewParser().ParseUnverified(newToken, &Claims{})
+	if err != nil || parsed.Header["kid"] != "2026-10" || parsed.Method.Alg() != AlgEdDSA {
+		t.Fatalf("nouveau token non signé par la clé active: %v, %v", err, parsed.Header)
+	}
+	if _, err := VerifyJWT(after, newToken); err != nil {
+		t.Fatalf("nouveau token refusé: %v", err)
+	}
+
+	// Ancienne clé retirée : ses tokens ne sont plus acceptés
+	retired, err := NewKeySet("2026-10", []*SigningKey{{ID: "2026-10", Algorithm: AlgEdDSA, PrivateKey: edKey, PublicKey: edKey.Public()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifyJWT(retired, oldToken); err == nil {
+		t.Fatal("token d'une clé retirée accepté")
+	}
+}
+
+func TestParsePEMKey(t *testing.T) {
+	rsaKey := testRSAKey()
+	edKey := testEd25519Key(t)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der := func(value []byte, err error) []byte {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+
+	cases := []struct {
+		name       string
+		pem        []byte
+		alg        string // vide : clé refusée
+		canSign    bool
+		errContent string
+	}{
+		{"RSA PKCS#1", pemBlock(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)), AlgRS256, true, ""},
+		{"RSA PKCS#8", pemBlock(t, "PRIVATE KEY", der(x509.MarshalPKCS8PrivateKey(rsaKey))), AlgRS256, true, ""},
+		{"RSA publique", pemBlock(t, "PUBLIC KEY", der(x509.MarshalPKIXPublicKey(&rsaKey.PublicKey))), AlgRS256, false, ""},
+		{"Ed25519 PKCS#8", pemBlock(t, "PRIVATE KEY", der(x509.MarshalPKCS8PrivateKey(edKey))), AlgEdDSA, true, ""},
+		{"Ed25519 publique", pemBlock(t, "PUBLIC KEY", der(x509.MarshalPKIXPublicKey(edKey.Public()))), AlgEdDSA, false, ""},
+		{"pas de PEM", []byte("pas une clé"), "", false, "aucun bloc PEM"},
+		{"certificat", pemBlock(t, "CERTIFICATE", []byte{1, 2, 3}), "", false, "non supporté"},
+		{"contenu corrompu", pemBlock(t, "PRIVATE KEY", []byte{1, 2, 3}), "", false, "clé PEM invalide"},
+		{"PKCS#1 déclaré, PKCS#8 fourni", pemBlock(t, "RSA PRIVATE KEY", der(x509.MarshalPKCS8PrivateKey(edKey))), "", false, "clé PEM invalide"},
+		{"clé ECDSA", pemBlock(t, "PRIVATE KEY", der(x509.MarshalPKCS8PrivateKey(ecKey))), "", false, "type de clé non supporté"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParsePEMKey("k", tc.pem)
+			if tc.alg == "" {
+				if err == nil || !strings.Contains(err.Error(), tc.errContent) {
+					t.Fatalf("erreur %v, attendu %q", err, tc.errContent)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePEMKey: %v", err)
+			}
+			if key.ID != "k" || key.Algorithm != tc.alg || (key.PrivateKey != nil) != tc.canSign {
+				t.Fatalf("clé %q %s, privée %t ; attendu %s, privée %t", key.ID, key.Algorithm, key.PrivateKey != nil, tc.alg, tc.canSign)
+			}
+			// Une clé publique seule vérifie sans pouvoir devenir la clé active
+			_, err = NewKeySet("k", []*SigningKey{key})
+			if tc.canSign != (err == nil) {
+				t.Fatalf("NewKeySet: %v", err)
+			}
+		})
+	}
+}
+
+func TestJWKSNeverExposesHMACSecrets(t *testing.T) {
+	rsaKey := testRSAKey()
+	edKey := testEd25519Key(t)
+	keys, err := NewKeySet("h", []*SigningKey{
+		NewHMACKey("h", []byte(testHMACSecret)),
+		{ID: "r", Algorithm: AlgRS256, PrivateKey: rsaKey, PublicKey: &rsaKey.PublicKey},
+		{ID: "e", Algorithm: AlgEdDSA, PrivateKey: edKey, PublicKey: edKey.Public()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwks := keys.JWKS()
+	if len(jwks) != 2 || jwks[0].Kid != "e" || jwks[0].Kty != "OKP" || jwks[1].Kid != "r" || jwks[1].Kty != "RSA" {
+		t.Fatalf("JWKS inattendu: %+v", jwks)
+	}
+	published, err := json.Marshal(jwks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(published), `"h"`) || strings.Contains(string(published), "HS256") {
+		t.Fatalf("clé HS256 publiée: %s", published)
+	}
+
+	// Les clés publiées permettent de vérifier les tokens signés par les clés privées
+	for _, jwk := range jwks {
+		public, err := jwk.PublicKey()
+		if err != nil {
+			t.Fatalf("JWK %q: %v", jwk.Kid, err)
+		}
+		switch public := public.(type) {
+		case *rsa.PublicKey:
+			if !public.Equal(&rsaKey.PublicKey) {
+				t.Fatal("clé RSA publiée différente")
+			}
+		case ed25519.PublicKey:
+			if !public.Equal(edKey.Public()) {
+				t.Fatal("clé Ed25519 publiée différente")
+			}
+		default:
+			t.Fatalf("type de clé publiée inattendu: %T", public)
+		}
+	}
+	if only, err := NewKeySet("h", []*SigningKey{NewHMACKey("h", []byte(testHMACSecret))}); err != nil || len(only.JWKS()) != 0 {
+		t.Fatalf("JWKS d'un jeu HS256 seul: %v, %+v", err, only.JWKS())
+	}
+}
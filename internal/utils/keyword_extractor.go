@@ -0,0 +1,343 @@
+// internal/utils/keyword_extractor.go
+package utils
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// Extraction de mots-clés sur le texte brut des commentaires, déterministe et sans modèle.
+// Les mots sont mis en minuscules, sans accents, puis réduits à leur racine (stemKeyword) :
+// "vidéo", "video" et "vidéos" désignent le même mot-clé. Les candidats sont les expressions de
+// 1 à maxKeyphraseWords mots consécutifs, délimitées par la ponctuation et les mots vides (comme
+// dans RAKE). Leur score (keywordScore) est le nombre de commentaires qui les mentionnent pondéré
+// par leur IDF sur les commentaires analysés, majoré pour les expressions de plusieurs mots : un
+// mot cité par presque tous les commentaires ("vidéo" sous une vidéo) passe derrière un thème
+// plus rare.
+
+const (
+	maxKeyphraseWords = 3
+	minKeywordLength  = 3   // mots plus courts ignorés ("ok", "xd")
+	phraseWordBonus   = 0.5 // majoration du score par mot supplémentaire
+)
+
+type keywordToken struct {
+	word string // minuscules, accents conservés (forme affichée)
+	stem string
+	stop bool // mot vide, trop court ou numérique : ne forme pas de mot-clé
+}
+
+// NormalizeKeyword retourne la clé de regroupement d'un mot-clé : racines des mots non vides,
+// séparées par une espace ("Qualité du son" → "qualit son"). Un mot-clé fait uniquement de mots
+// vides garde tous ses mots.
+func NormalizeKeyword(keyword string) string {
+	return strings.Join(keywordStems(keyword), " ")
+}
+
+// ExtractKeywords retourne au plus limit mots-clés des commentaires, du plus au moins pertinent,
+// avec le nombre de commentaires qui les mentionnent. Avec plusieurs commentaires, un mot-clé
+// doit apparaître dans au moins deux d'entre eux.
+func ExtractKeywords(texts []string, limit int) []models.KeywordFrequency {
+	type candidate struct {
+		key      string
+		words    int
+		docs     map[int]bool
+		surfaces map[string]int
+	}
+	candidates := map[string]*candidate{}
+	for doc, text := range texts {
+		for _, segment := range keywordSegments(text) {
+			for start := range segment {
+				for end := start + 1; end <= len(segment) && end-start <= maxKeyphraseWords; end++ {
+					if segment[end-1].stop {
+						break
+					}
+					stems, words := make([]string, 0, end-start), make([]string, 0, end-start)
+					for _, token := range segment[start:end] {
+						stems, words = append(stems, token.stem), append(words, token.word)
+					}
+					key := strings.Join(stems, " ")
+					c, ok := candidates[key]
+					if !ok {
+						c = &candidate{key: key, words: end - start, docs: map[int]bool{}, surfaces: map[string]int{}}
+						candidates[key] = c
+					}
+					c.docs[doc] = true
+					c.surfaces[strings.Join(words, " ")]++
+				}
+			}
+		}
+	}
+
+	minDocs := 2
+	if len(texts) < 2 {
+		minDocs = 1
+	}
+	var ranked []*candidate
+	for _, c := range candidates {
+		if len(c.docs) >= minDocs {
+			ranked = append(ranked, c)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		si, sj := keywordScore(len(ranked[i].docs), ranked[i].words, len(texts)), keywordScore(len(ranked[j].docs), ranked[j].words, len(texts))
+		if si != sj {
+			return si > sj
+		}
+		return ranked[i].key < ranked[j].key
+	})
+
+	keywords := []models.KeywordFrequency{}
+	var selected []selectedKeyword
+	for _, c := range ranked {
+		if len(keywords) >= limit {
+			break
+		}
+		stems := strings.Fields(c.key)
+		if subsumedKeyword(selected, stems, len(c.docs)) {
+			continue
+		}
+		selected = append(selected, selectedKeyword{stems: stems, frequency: len(c.docs)})
+		keywords = append(keywords, models.KeywordFrequency{Keyword: mostFrequentSurface(c.surfaces), Frequency: len(c.docs)})
+	}
+	return keywords
+}
+
+// RankKeywords fusionne les mots-clés du modèle (llmKeywords, du plus au moins fréquent) et ceux
+// extraits des commentaires, regroupés par NormalizeKeyword, et les classe par keywordScore du
+// nombre de commentaires qui les mentionnent. À score égal, les mots-clés du
+// modèle passent devant ; un mot-clé du modèle absent des commentaires (thème implicite) est
+// conservé avec une fréquence nulle.
+func RankKeywords(llmKeywords []string, texts []string, limit int) []models.KeywordFrequency {
+	type candidate struct {
+		keyword string
+		stems   []string
+		fromLLM bool
+		order   int
+		freq    int
+	}
+	var candidates []*candidate
+	byKey := map[string]bool{}
+	add := func(keyword string, fromLLM bool) {
+		keyword = strings.TrimSpace(keyword)
+		stems := keywordStems(keyword)
+		key := strings.Join(stems, " ")
+		if key == "" || byKey[key] {
+			return
+		}
+		byKey[key] = true
+		candidates = append(candidates, &candidate{keyword: keyword, stems: stems, fromLLM: fromLLM, order: len(candidates)})
+	}
+	for _, keyword := range llmKeywords {
+		add(keyword, true)
+	}
+	for _, extracted := range ExtractKeywords(texts, limit) {
+		add(extracted.Keyword, false)
+	}
+
+	documents := make([][][]keywordToken, len(texts))
+	for i, text := range texts {
+		documents[i] = keywordSegments(text)
+	}
+	for _, c := range candidates {
+		for _, segments := range documents {
+			if mentionsKeyword(segments, c.stems) {
+				c.freq++
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if sa, sb := keywordScore(a.freq, len(a.stems), len(texts)), keywordScore(b.freq, len(b.stems), len(texts)); sa != sb {
+			return sa > sb
+		}
+		if a.fromLLM != b.fromLLM {
+			return a.fromLLM
+		}
+		return a.order < b.order
+	})
+	keywords := []models.KeywordFrequency{}
+	var selected []selectedKeyword
+	for _, c := range candidates {
+		if len(keywords) >= limit {
+			break
+		}
+		if subsumedKeyword(selected, c.stems, c.freq) {
+			continue
+		}
+		selected = append(selected, selectedKeyword{stems: c.stems, frequency: c.freq})
+		keywords = append(keywords, models.KeywordFrequency{Keyword: c.keyword, Frequency: c.freq})
+	}
+	return keywords
+}
+
+type selectedKeyword struct {
+	stems     []string
+	frequency int
+}
+
+// subsumedKeyword : le mot-clé fait partie d'une expression déjà retenue, mentionnée au moins
+// aussi souvent ("son" n'apporte rien après "qualité du son" s'il n'apparaît pas ailleurs)
+func subsumedKeyword(selected []selectedKeyword, stems []string, frequency int) bool {
+	for _, s := range selected {
+		if len(s.stems) <= len(stems) || s.frequency < frequency {
+			continue
+		}
+		for start := 0; start+len(stems) <= len(s.stems); start++ {
+			if slices.Equal(s.stems[start:start+len(stems)], stems) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// KeywordNames retourne les mots-clés, dans l'ordre
+func KeywordNames(keywords []models.KeywordFrequency) []string {
+	names := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		names[i] = keyword.Keyword
+	}
+	return names
+}
+
+// keywordScore : frequency commentaires sur documents mentionnent le mot-clé. L'IDF ln((1+N)/df)
+// reste positive quand tous les commentaires le citent (et pour un commentaire unique) ; un
+// mot-clé absent des commentaires vaut 0.
+func keywordScore(frequency, words, documents int) float64 {
+	if frequency == 0 {
+		return 0
+	}
+	idf := math.Log(float64(1+documents) / float64(frequency))
+	return float64(frequency) * idf * (1 + phraseWordBonus*float64(words-1))
+}
+
+// mentionsKeyword : les racines du mot-clé apparaissent dans l'ordre au sein d'un même segment,
+// séparées uniquement par des mots vides ("qualité du son" mentionne "qualité son")
+func mentionsKeyword(segments [][]keywordToken, stems []string) bool {
+	for _, segment := range segments {
+		for start, token := range segment {
+			if token.stem != stems[0] {
+				continue
+			}
+			matched := 1
+			for _, next := range segment[start+1:] {
+				if matched == len(stems) {
+					break
+				}
+				if next.stem == stems[matched] {
+					matched++
+				} else if !next.stop {
+					break
+				}
+			}
+			if matched == len(stems) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func keywordStems(keyword string) []string {
+	var all, content []string
+	for _, segment := range keywordSegments(keyword) {
+		for _, token := range segment {
+			all = append(all, token.stem)
+			if !token.stop {
+				content = append(content, token.stem)
+			}
+		}
+	}
+	if len(content) == 0 {
+		return all
+	}
+	return content
+}
+
+// keywordSegments découpe le texte en segments (séparés par la ponctuation) de mots ; les
+// apostrophes séparent les élisions ("l'audio" → "l", "audio")
+func keywordSegments(text string) [][]keywordToken {
+	var segments [][]keywordToken
+	var current []keywordToken
+	flush := func() {
+		if len(current) > 0 {
+			segments = append(segments, current)
+			current = nil
+		}
+	}
+	runes := []rune(text)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			if !unicode.IsSpace(r) && r != '\'' && r != '’' && r != '-' {
+				flush()
+			}
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+			i++
+		}
+		word := strings.ToLower(string(runes[start:i]))
+		folded := accents.Replace(word)
+		current = append(current, keywordToken{
+			word: word,
+			stem: stemKeyword(folded),
+			stop: keywordStopWords[folded] || len([]rune(folded)) < minKeywordLength || isNumber(folded),
+		})
+	}
+	flush()
+	return segments
+}
+
+// stemKeyword réduit un mot (minuscules, sans accents) à une racine approximative, commune au
+// français et à l'anglais : terminaisons -ing, -ed, -ies, adverbes en -ement, pluriels en -s/-x
+// et -e final. Volontairement léger : il suffit que les variantes d'un même mot se rejoignent.
+func stemKeyword(word string) string {
+	length := len(word)
+	switch {
+	case length > 5 && strings.HasSuffix(word, "ies"):
+		word = word[:length-3] + "y"
+	case length > 5 && strings.HasSuffix(word, "ing"):
+		word = word[:length-3]
+	case length > 7 && strings.HasSuffix(word, "ement"):
+		word = word[:length-5]
+	case length > 5 && strings.HasSuffix(word, "ed"):
+		word = word[:length-2]
+	}
+	if len(word) > 3 && (strings.HasSuffix(word, "s") || strings.HasSuffix(word, "x")) && !strings.HasSuffix(word, "ss") {
+		word = word[:len(word)-1]
+	}
+	if len(word) > 3 && strings.HasSuffix(word, "e") {
+		word = word[:len(word)-1]
+	}
+	return word
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return word != ""
+}
+
+// mostFrequentSurface : forme la plus fréquente (ordre alphabétique en cas d'égalité)
+func mostFrequentSurface(surfaces map[string]int) string {
+	best, bestCount := "", 0
+	for surface, count := range surfaces {
+		if count > bestCount || (count == bestCount && surface < best) {
+			best, bestCount = surface, count
+		}
+	}
+	return best
+}
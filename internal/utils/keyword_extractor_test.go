@@ -0,0 +1,114 @@
+// internal/utils/keyword_extractor_test.go
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestNormalizeKeywordGroupsVariants(t *testing.T) {
+	groups := [][]string{
+		{"vidéo", "video", "Vidéos", "VIDEOS"},
+		{"Qualité du son", "qualite son", "qualités de son"},
+		{"explained", "explaining", "explains", "explain"},
+		{"rapidement", "rapide", "rapides"},
+	}
+	for _, group := range groups {
+		key := NormalizeKeyword(group[0])
+		for _, variant := range group[1:] {
+			if got := NormalizeKeyword(variant); got != key {
+				t.Errorf("%q → %q, %q → %q", group[0], key, variant, got)
+			}
+		}
+	}
+	if NormalizeKeyword("son") == "" || NormalizeKeyword("le") != "le" || NormalizeKeyword("  ") != "" {
+		t.Fatal("un mot-clé fait de mots vides doit garder ses mots")
+	}
+	if NormalizeKeyword("class") == NormalizeKeyword("clas") {
+		t.Fatal("un double s final n'est pas un pluriel")
+	}
+}
+
+func TestExtractKeywords(t *testing.T) {
+	texts := []string{
+		"Le montage vidéo est incroyable, merci !",
+		"Quel logiciel de montage vidéo utilises-tu ?",
+		"Les vidéos sur le montage sont les meilleures.",
+		"Super vidéo, merci",
+		"Le micro grésille un peu",
+		"Tu peux faire une vidéo sur le micro ?",
+		"Première fois que je commente",
+	}
+	keywords := ExtractKeywords(texts, 3)
+	// "vidéo", citée par 5 commentaires sur 7, pèse moins que les thèmes plus rares :
+	// montage vidéo 2 × ln(8/2) × 1,5 ≈ 4,2 ; montage 3 × ln(8/3) ≈ 2,9 ; micro 2 × ln(8/2) ≈ 2,8 ;
+	// vidéo 5 × ln(8/5) ≈ 2,4
+	expected := []models.KeywordFrequency{
+		{Keyword: "montage vidéo", Frequency: 2},
+		{Keyword: "montage", Frequency: 3},
+		{Keyword: "micro", Frequency: 2},
+	}
+	if !slices.Equal(keywords, expected) {
+		t.Fatalf("mots-clés extraits: %+v", keywords)
+	}
+
+	// Mots vides et mots cités dans un seul commentaire exclus
+	all := KeywordNames(ExtractKeywords(texts, 100))
+	for _, excluded := range []string{"merci", "super", "le", "logiciel", "commente"} {
+		if slices.Contains(all, excluded) {
+			t.Errorf("%q ne devrait pas être extrait: %v", excluded, all)
+		}
+	}
+	if !slices.Contains(all, "micro") {
+		t.Errorf("micro absent: %v", all)
+	}
+	// Un mot cité presque partout reste extrait, derrière les thèmes plus rares
+	if video := slices.Index(all, "vidéo"); video < slices.Index(all, "micro") {
+		t.Errorf("vidéo mal classée: %v", all)
+	}
+	// Un seul commentaire : l'expression complète, ses parties n'apportant rien de plus
+	if single := ExtractKeywords([]string{"Excellent tutoriel Docker"}, 5); len(single) != 1 || single[0].Keyword != "excellent tutoriel docker" {
+		t.Fatalf("commentaire unique: %+v", single)
+	}
+}
+
+func TestRankKeywordsMergesModelAndExtractedKeywords(t *testing.T) {
+	texts := []string{
+		"La qualité du son est mauvaise",
+		"Qualité de son au top",
+		"Le micro grésille, la qualité son en pâtit",
+		"Le micro est trop près",
+		"Quelle vidéo !",
+		"Deuxième vidéo de la série",
+	}
+	// "Vidéos" (modèle) et "vidéo" (extrait) ne font qu'un ; "pédagogie" n'est mentionné par
+	// aucun commentaire : conservé en dernier, avec une fréquence nulle
+	keywords := RankKeywords([]string{"pédagogie", "Vidéos", "qualité du son"}, texts, 4)
+	expected := []models.KeywordFrequency{
+		{Keyword: "qualité du son", Frequency: 3},
+		{Keyword: "Vidéos", Frequency: 2},
+		{Keyword: "micro", Frequency: 2},
+		{Keyword: "pédagogie", Frequency: 0},
+	}
+	if !slices.Equal(keywords, expected) {
+		t.Fatalf("mots-clés classés: %+v", keywords)
+	}
+	if limited := RankKeywords([]string{"pédagogie"}, texts, 1); len(limited) != 1 || limited[0].Keyword == "pédagogie" {
+		t.Fatalf("limite: %+v", limited)
+	}
+}
+
+func TestMergeParsedInsightsGroupsKeywordVariants(t *testing.T) {
+	merged := MergeParsedInsights([]*ParsedInsight{
+		{Keywords: []string{"Vidéo", "son", "vidéos"}},
+		{Keywords: []string{"vidéo", "micro"}},
+		{Keywords: []string{"videos", "micro"}},
+	}, DefaultMergeLimits)
+	// Trois lots pour la vidéo (comptée une fois par lot, affichée sous sa forme la plus
+	// fréquente), deux pour le micro
+	if !slices.Equal(merged.Keywords, []string{"vidéo", "micro", "son"}) {
+		t.Fatalf("mots-clés fusionnés: %v", merged.Keywords)
+	}
+}
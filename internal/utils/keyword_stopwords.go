@@ -0,0 +1,39 @@
+// internal/utils/keyword_stopwords.go
+package utils
+
+// Mots vides ignorés par l'extraction de mots-clés (français et anglais, en minuscules et sans
+// accents) : articles, pronoms, prépositions, auxiliaires, adverbes courants, et formules de
+// commentaire qui ne désignent pas un thème ("merci", "bravo", "lol"). "son" n'y figure pas :
+// le son d'une vidéo est un thème fréquent.
+var keywordStopWords = makeWordSet(
+	// Français
+	"a", "ai", "ainsi", "alors", "apres", "as", "au", "aucun", "aucune", "aussi", "autre", "autres", "aux", "avait",
+	"avant", "avec", "avez", "avoir", "avons", "beaucoup", "bien", "c", "ca", "car", "ce", "ceci", "cela", "celle",
+	"celles", "celui", "ces", "cet", "cette", "ceux", "chaque", "chez", "comme", "comment", "d", "dans", "de",
+	"deja", "des", "deux", "doit", "donc", "dont", "du", "elle", "elles", "en", "encore", "entre", "es", "est",
+	"et", "etaient", "etais", "etait", "ete", "etes", "etre", "eu", "fait", "faire", "fais", "faut", "fois", "font",
+	"il", "ils", "j", "je", "jamais", "juste", "l", "la", "le", "les", "leur", "leurs", "lui", "m", "ma", "mais",
+	"me", "meme", "mes", "moi", "moins", "mon", "n", "ne", "ni", "non", "nos", "notre", "nous", "on", "ont", "ou",
+	"oui", "par", "parce", "pas", "peu", "peut", "peux", "plus", "pour", "pourquoi", "qu", "quand", "que", "quel",
+	"quelle", "quelles", "quels", "qui", "quoi", "rien", "s", "sa", "sans", "se", "sera", "ses", "si", "sont",
+	"sous", "suis", "sur", "t", "ta", "te", "tes", "toi", "ton", "toujours", "tous", "tout", "toute", "toutes",
+	"tres", "trop", "tu", "un", "une", "va", "vais", "vont", "vos", "votre", "vous", "vraiment", "y", "ya",
+	// Anglais
+	"about", "after", "again", "all", "also", "am", "an", "and", "any", "are", "as", "at", "be", "been", "being",
+	"but", "by", "can", "could", "did", "do", "does", "doing", "dont", "for", "from", "get", "got", "had", "has",
+	"have", "he", "her", "him", "his", "how", "i", "if", "im", "in", "into", "is", "it", "its", "just", "me",
+	"more", "most", "my", "no", "nor", "not", "now", "of", "off", "on", "only", "or", "other", "our", "out", "over",
+	"own", "really", "same", "she", "should", "so", "some", "such", "than", "that", "the", "their", "them", "then",
+	"there", "these", "they", "this", "those", "through", "to", "too", "under", "up", "very", "was", "we", "were",
+	"what", "when", "where", "which", "while", "who", "why", "will", "with", "would", "you", "your",
+	// Formules de commentaire
+	"bravo", "cool", "lol", "mdr", "merci", "please", "ptdr", "stp", "super", "svp", "thank", "thanks", "top",
+)
+
+func makeWordSet(words ...string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
@@ -0,0 +1,113 @@
+// internal/utils/lexicon_data.go
+package utils
+
+// Lexiques de l'analyseur hors ligne (voir AnalyzeLexicon). Les mots sont en minuscules et sans
+// accents, sauf quand l'accent distingue un mot français d'un mot anglais ("hâte" / "hate") ;
+// les formes fléchies courantes (pluriel, féminin, -ed, -ing) sont retrouvées par lexiconLookup.
+// Valences de -3 (très négatif) à 3 (très positif).
+
+// Émotions reconnues par l'analyseur hors ligne
+const (
+	EmotionJoy      = "joie"
+	EmotionAnger    = "colère"
+	EmotionSadness  = "tristesse"
+	EmotionFear     = "peur"
+	EmotionSurprise = "surprise"
+	EmotionDisgust  = "dégoût"
+)
+
+type lexiconEntry struct {
+	valence float64
+	emotion string // facultative
+}
+
+var sentimentLexicon = map[string]lexiconEntry{
+	// Français : positif
+	"adore": {3, EmotionJoy}, "adorer": {3, EmotionJoy}, "aime": {2, EmotionJoy}, "aimer": {2, EmotionJoy},
+	"genial": {3, EmotionJoy}, "excellent": {3, EmotionJoy}, "parfait": {3, EmotionJoy}, "magnifique": {3, EmotionJoy},
+	"incroyable": {2.5, EmotionSurprise}, "superbe": {3, EmotionJoy}, "top": {2, EmotionJoy}, "bravo": {2.5, EmotionJoy},
+	"merci": {1.5, EmotionJoy}, "bien": {1.5, ""}, "bon": {1.5, ""}, "beau": {2, EmotionJoy}, "belle": {2, EmotionJoy},
+	"clair": {1.5, ""}, "utile": {2, ""}, "interessant": {2, ""}, "passionnant": {2.5, EmotionJoy},
+	"pedagogique": {2, ""}, "qualite": {1.5, ""}, "felicitation": {2.5, EmotionJoy}, "chapeau": {2, EmotionJoy},
+	"hâte": {1.5, EmotionJoy}, "content": {2, EmotionJoy}, "heureux": {2.5, EmotionJoy}, "ravi": {2.5, EmotionJoy},
+	"super": {2, EmotionJoy}, "cool": {1.5, EmotionJoy}, "sympa": {1.5, EmotionJoy}, "efficace": {2, ""}, "fluide": {1.5, ""},
+	"impressionnant": {2.5, EmotionSurprise}, "enorme": {2, EmotionSurprise}, "dingue": {1.5, EmotionSurprise},
+	"recommande": {2, ""}, "plaisir": {2, EmotionJoy}, "rire": {1.5, EmotionJoy}, "drole": {2, EmotionJoy},
+	"merveilleux": {3, EmotionJoy}, "fantastique": {3, EmotionJoy}, "formidable": {3, EmotionJoy},
+	"agreable": {2, EmotionJoy}, "complet": {1.5, ""}, "precis": {1.5, ""}, "solide": {1.5, ""},
+	"enfin": {0.5, ""}, "mieux": {1, ""}, "meilleur": {2, ""}, "reussi": {2, EmotionJoy}, "valide": {1, ""},
+	"surpris": {0.5, EmotionSurprise}, "etonnant": {1, EmotionSurprise}, "wow": {2, EmotionSurprise},
+	// Français : négatif
+	"nul": {-2.5, EmotionAnger}, "nulle": {-2.5, EmotionAnger}, "naze": {-2, EmotionAnger}, "mauvais": {-2, ""}, "horrible": {-3, EmotionDisgust},
+	"affreux": {-3, EmotionDisgust}, "deteste": {-3, EmotionAnger}, "detester": {-3, EmotionAnger},
+	"decu": {-2, EmotionSadness}, "decevant": {-2, EmotionSadness}, "deception": {-2, EmotionSadness},
+	"ennuyeux": {-2, ""}, "chiant": {-2, EmotionAnger}, "inutile": {-2, ""}, "faux": {-1.5, ""},
+	"erreur": {-1.5, ""}, "bug": {-1.5, ""}, "probleme": {-1.5, ""}, "arnaque": {-3, EmotionAnger},
+	"honte": {-2.5, EmotionDisgust}, "honteux": {-2.5, EmotionDisgust}, "degoutant": {-3, EmotionDisgust},
+	"triste": {-2, EmotionSadness}, "dommage": {-1.5, EmotionSadness}, "peur": {-1.5, EmotionFear},
+	"inquiet": {-1.5, EmotionFear}, "inquietant": {-2, EmotionFear}, "effrayant": {-2.5, EmotionFear},
+	"colere": {-2.5, EmotionAnger}, "enerve": {-2, EmotionAnger}, "enervant": {-2, EmotionAnger},
+	"marre": {-2, EmotionAnger}, "pire": {-2.5, ""}, "catastrophe": {-3, EmotionFear}, "raté": {-1.5, ""},
+	"mal": {-1.5, ""}, "flou": {-1, ""}, "lent": {-1, ""}, "long": {-0.5, ""}, "pub": {-1, EmotionAnger},
+	"clickbait": {-2, EmotionAnger}, "putaclic": {-2, EmotionAnger}, "menteur": {-2.5, EmotionAnger},
+	"mensonge": {-2.5, EmotionAnger}, "ridicule": {-2, EmotionDisgust}, "pitoyable": {-2.5, EmotionDisgust},
+	"confus": {-1.5, ""}, "incomprehensible": {-2, ""}, "galere": {-1.5, EmotionSadness}, "moche": {-2, EmotionDisgust},
+	// Anglais : positif
+	"love": {3, EmotionJoy}, "like": {1.5, EmotionJoy}, "great": {2.5, EmotionJoy}, "awesome": {3, EmotionJoy},
+	"amazing": {3, EmotionSurprise}, "good": {1.5, ""}, "nice": {1.5, EmotionJoy}, "perfect": {3, EmotionJoy},
+	"helpful": {2, ""}, "useful": {2, ""}, "thanks": {1.5, EmotionJoy},
+	"thank": {1.5, EmotionJoy}, "clear": {1.5, ""}, "best": {2.5, EmotionJoy}, "better": {1, ""},
+	"beautiful": {2.5, EmotionJoy}, "brilliant": {3, EmotionJoy}, "fun": {2, EmotionJoy}, "funny": {2, EmotionJoy},
+	"happy": {2.5, EmotionJoy}, "glad": {2, EmotionJoy}, "enjoy": {2, EmotionJoy}, "wonderful": {3, EmotionJoy},
+	"fantastic": {3, EmotionJoy}, "interesting": {2, ""}, "informative": {2, ""}, "recommend": {2, ""},
+	"impressive": {2.5, EmotionSurprise}, "wholesome": {2, EmotionJoy}, "legend": {2, EmotionJoy},
+	// Anglais : négatif
+	"hate": {-3, EmotionAnger}, "bad": {-2, ""}, "terrible": {-3, EmotionDisgust}, "awful": {-3, EmotionDisgust},
+	"boring": {-2, ""}, "useless": {-2, ""}, "wrong": {-1.5, ""}, "worst": {-3, EmotionAnger},
+	"worse": {-2, ""}, "disappointed": {-2, EmotionSadness}, "disappointing": {-2, EmotionSadness},
+	"sad": {-2, EmotionSadness}, "scary": {-2, EmotionFear}, "afraid": {-2, EmotionFear}, "angry": {-2.5, EmotionAnger},
+	"annoying": {-2, EmotionAnger}, "scam": {-3, EmotionAnger}, "fake": {-2, EmotionAnger}, "cringe": {-2, EmotionDisgust},
+	"trash": {-2.5, EmotionDisgust}, "garbage": {-2.5, EmotionDisgust}, "disgusting": {-3, EmotionDisgust},
+	"confusing": {-1.5, ""}, "slow": {-1, ""}, "broken": {-2, ""}, "lie": {-2.5, EmotionAnger}, "lies": {-2.5, EmotionAnger},
+	"unsubscribe": {-2, EmotionAnger}, "unsubscribed": {-2, EmotionAnger}, "ugly": {-2, EmotionDisgust},
+	"sucks": {-2.5, EmotionAnger}, "stupid": {-2.5, EmotionAnger}, "waste": {-2, EmotionAnger}, "blurry": {-1, ""},
+}
+
+// emojiLexicon : émojis et émoticônes
+var emojiLexicon = map[string]lexiconEntry{
+	"😀": {2, EmotionJoy}, "😃": {2, EmotionJoy}, "😄": {2, EmotionJoy}, "😁": {2, EmotionJoy}, "😊": {2, EmotionJoy},
+	"🙂": {1, EmotionJoy}, "😍": {3, EmotionJoy}, "🥰": {3, EmotionJoy}, "😘": {2, EmotionJoy}, "🤩": {3, EmotionSurprise},
+	"😂": {2, EmotionJoy}, "🤣": {2, EmotionJoy}, "😆": {2, EmotionJoy}, "😎": {1.5, EmotionJoy}, "🥳": {2.5, EmotionJoy},
+	"❤": {3, EmotionJoy}, "❤️": {3, EmotionJoy}, "💕": {2.5, EmotionJoy}, "💖": {2.5, EmotionJoy}, "💯": {2, EmotionJoy},
+	"👍": {2, ""}, "👏": {2, EmotionJoy}, "🙏": {1.5, EmotionJoy}, "🔥": {2, EmotionJoy}, "✨": {1, EmotionJoy},
+	"🎉": {2, EmotionJoy}, "💪": {1.5, EmotionJoy}, "😮": {0.5, EmotionSurprise}, "😲": {0.5, EmotionSurprise},
+	"🤯": {1, EmotionSurprise}, "😱": {-1, EmotionFear}, "😨": {-1.5, EmotionFear}, "😰": {-1.5, EmotionFear},
+	"😢": {-2, EmotionSadness}, "😭": {-2, EmotionSadness}, "😞": {-2, EmotionSadness}, "😔": {-1.5, EmotionSadness},
+	"💔": {-2, EmotionSadness}, "😡": {-3, EmotionAnger}, "😠": {-2.5, EmotionAnger}, "🤬": {-3, EmotionAnger},
+	"👎": {-2, EmotionAnger}, "🤮": {-3, EmotionDisgust}, "🤢": {-2.5, EmotionDisgust}, "💩": {-2, EmotionDisgust},
+	"🙄": {-1.5, EmotionAnger}, "😒": {-1.5, EmotionAnger}, "😴": {-1.5, ""}, "🥱": {-1.5, ""},
+	":)": {1.5, EmotionJoy}, ":-)": {1.5, EmotionJoy}, ":D": {2, EmotionJoy}, "<3": {2.5, EmotionJoy},
+	":(": {-1.5, EmotionSadness}, ":-(": {-1.5, EmotionSadness},
+}
+
+// Modificateurs : appliqués au mot porteur de sentiment qui les suit. Un intensificateur qui
+// figure aussi dans sentimentLexicon ("super") compte comme sentiment quand il n'est pas suivi
+// d'un mot porteur de sentiment ("super !").
+var (
+	intensifiers = map[string]float64{
+		"tres": 1.3, "vraiment": 1.3, "tellement": 1.4, "trop": 1.3, "hyper": 1.4, "super": 1.3, "grave": 1.3,
+		"extremement": 1.5, "totalement": 1.3, "completement": 1.3, "absolument": 1.4, "carrement": 1.3,
+		"very": 1.3, "really": 1.3, "so": 1.3, "extremely": 1.5, "totally": 1.3, "absolutely": 1.4,
+		"incredibly": 1.5, "truly": 1.3, "insanely": 1.4,
+	}
+	diminishers = map[string]float64{
+		"peu": 0.6, "assez": 0.8, "plutot": 0.8, "moyennement": 0.6, "legerement": 0.6,
+		"slightly": 0.6, "somewhat": 0.7, "kinda": 0.7, "barely": 0.5, "fairly": 0.8,
+	}
+	negators = map[string]bool{
+		"ne": true, "n": true, "pas": true, "jamais": true, "aucun": true, "aucune": true, "rien": true, "sans": true,
+		"not": true, "no": true, "never": true, "none": true, "nothing": true, "without": true, "cannot": true,
+	}
+	// Après "mais", la fin de la phrase l'emporte ("long mais passionnant")
+	contrastWords = map[string]bool{"mais": true, "cependant": true, "pourtant": true, "but": true, "however": true}
+)
@@ -0,0 +1,260 @@
+// internal/utils/lexicon_sentiment.go
+package utils
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Analyse de sentiment par lexique (français et anglais), sans réseau ni modèle : repli de
+// l'analyse par IA et moteur des plans sans IA. Inspirée de VADER : somme des valences des mots,
+// corrigées par la négation, les intensificateurs, les contrastes ("mais"), les majuscules et
+// les points d'exclamation, puis normalisée dans [-1, 1].
+
+const (
+	negationFactor   = -0.74 // un mot nié garde une part de sa force, inversée ("pas génial" ≠ "nul")
+	capsFactor       = 1.2   // MOT EN MAJUSCULES au milieu d'un texte en minuscules
+	exclamationBoost = 0.292 // par "!", au plus maxExclamations
+	maxExclamations  = 4
+	normalizeAlpha   = 15 // polarité = somme / sqrt(somme² + alpha)
+	lookBehind       = 3  // mots examinés avant le mot porteur de sentiment
+	// Un commentaire est "mixte" quand ses parts positive et négative dépassent mixedMinimum
+	// et que la plus faible vaut au moins mixedBalance fois la plus forte
+	mixedMinimum = 1.0
+	mixedBalance = 0.5
+)
+
+// Suffixes retirés pour retrouver la forme du lexique ("géniales" → "genial", "loved" → "love")
+var inflectionSuffixes = []string{"es", "s", "e", "x", "ed", "d", "ing"}
+
+// LexiconResult est le sentiment d'un texte selon le lexique
+type LexiconResult struct {
+	Score    CommentScore
+	Positive float64            // somme des contributions positives
+	Negative float64            // somme des contributions négatives (valeur absolue)
+	Emotions map[string]float64 // intensité cumulée par émotion (EmotionJoy...)
+}
+
+type lexiconToken struct {
+	lower     string // minuscules, accents conservés
+	folded    string // minuscules sans accents
+	caps      bool   // écrit en majuscules (au moins 2 lettres)
+	emoji     bool
+	clauseEnd bool // suivi d'une ponctuation qui borne la négation (. ? ; ,)
+}
+
+// AnalyzeLexicon calcule la polarité, l'étiquette et les émotions d'un texte
+func AnalyzeLexicon(text string) LexiconResult {
+	result := LexiconResult{Emotions: map[string]float64{}}
+	tokens, exclamations := tokenizeLexicon(text)
+	shouting := hasLowercaseWord(tokens)
+
+	type contribution struct {
+		value   float64
+		index   int
+		emotion string
+	}
+	var contributions []contribution
+	contrastAt := -1
+	for i, token := range tokens {
+		if contrastWords[token.folded] {
+			contrastAt = i
+			continue
+		}
+		entry, ok := lexiconLookup(token)
+		if !ok {
+			continue
+		}
+		// "super génial" : "super" intensifie ; "super !" : "super" est positif
+		if _, isIntensifier := intensifiers[token.folded]; isIntensifier && nextCarriesSentiment(tokens, i) {
+			continue
+		}
+
+		value, negated := entry.valence, false
+		for j := i - 1; j >= 0 && j >= i-lookBehind && !tokens[j].clauseEnd; j-- {
+			switch {
+			case negators[tokens[j].folded]:
+				negated = true
+			case intensifiers[tokens[j].folded] > 0:
+				value *= intensifiers[tokens[j].folded]
+			case diminishers[tokens[j].folded] > 0:
+				value *= diminishers[tokens[j].folded]
+			}
+		}
+		// Négation postposée du français parlé : "j'aime pas", "c'est jamais clair"
+		if !token.clauseEnd && i+1 < len(tokens) && (tokens[i+1].folded == "pas" || tokens[i+1].folded == "jamais") {
+			negated = true
+		}
+		if negated {
+			value *= negationFactor
+		}
+		if token.caps && shouting {
+			value *= capsFactor
+		}
+		emotion := entry.emotion
+		if negated {
+			emotion = "" // "pas content" n'exprime pas de joie
+		}
+		contributions = append(contributions, contribution{value: value, index: i, emotion: emotion})
+	}
+
+	var sum float64
+	for _, c := range contributions {
+		value := c.value
+		if contrastAt >= 0 {
+			if c.index < contrastAt {
+				value *= 0.5
+			} else {
+				value *= 1.5
+			}
+		}
+		sum += value
+		if value > 0 {
+			result.Positive += value
+		} else {
+			result.Negative -= value
+		}
+		if c.emotion != "" {
+			result.Emotions[c.emotion] += math.Abs(value)
+		}
+	}
+	if sum != 0 {
+		sum += math.Copysign(float64(min(exclamations, maxExclamations))*exclamationBoost, sum)
+	}
+
+	polarity := round3(sum / math.Sqrt(sum*sum+normalizeAlpha))
+	label := ""
+	weaker, stronger := math.Min(result.Positive, result.Negative), math.Max(result.Positive, result.Negative)
+	if weaker >= mixedMinimum && weaker >= mixedBalance*stronger {
+		label = "mixed"
+	}
+	result.Score = NewCommentScore(polarity, label)
+	return result
+}
+
+// DominantEmotions retourne les émotions par intensité décroissante (ordre alphabétique en cas d'égalité)
+func DominantEmotions(emotions map[string]float64) []string {
+	names := make([]string, 0, len(emotions))
+	for name, intensity := range emotions {
+		if intensity > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if emotions[names[i]] != emotions[names[j]] {
+			return emotions[names[i]] > emotions[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func lexiconLookup(token lexiconToken) (lexiconEntry, bool) {
+	if token.emoji {
+		entry, ok := emojiLexicon[token.lower]
+		return entry, ok
+	}
+	if entry, ok := sentimentLexicon[token.lower]; ok {
+		return entry, true
+	}
+	if entry, ok := sentimentLexicon[token.folded]; ok {
+		return entry, true
+	}
+	for _, suffix := range inflectionSuffixes {
+		stem, found := strings.CutSuffix(token.folded, suffix)
+		if !found || len(stem) < 3 {
+			continue
+		}
+		if entry, ok := sentimentLexicon[stem]; ok {
+			return entry, true
+		}
+	}
+	return lexiconEntry{}, false
+}
+
+func nextCarriesSentiment(tokens []lexiconToken, i int) bool {
+	if tokens[i].clauseEnd || i+1 >= len(tokens) {
+		return false
+	}
+	_, ok := lexiconLookup(tokens[i+1])
+	return ok
+}
+
+func hasLowercaseWord(tokens []lexiconToken) bool {
+	for _, token := range tokens {
+		if !token.emoji && !token.caps && token.lower != strings.ToUpper(token.lower) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenizeLexicon découpe le texte en mots (élisions séparées : "n'aime" → "n", "aime"), émojis
+// et émoticônes, et compte les points d'exclamation
+func tokenizeLexicon(text string) ([]lexiconToken, int) {
+	text = strings.NewReplacer("n't", " not", "N'T", " NOT", "’", "'").Replace(text)
+	runes := []rune(text)
+	var tokens []lexiconToken
+	exclamations := 0
+	endClause := func() {
+		if len(tokens) > 0 {
+			tokens[len(tokens)-1].clauseEnd = true
+		}
+	}
+
+	for i := 0; i < len(runes); {
+		if emoticon := emoticonAt(runes, i); emoticon != "" {
+			tokens = append(tokens, lexiconToken{lower: emoticon, folded: emoticon, emoji: true})
+			i += len([]rune(emoticon))
+			continue
+		}
+		r := runes[i]
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			word := string(runes[start:i])
+			lower := strings.ToLower(word)
+			letters := 0
+			for _, c := range word {
+				if unicode.IsLetter(c) {
+					letters++
+				}
+			}
+			tokens = append(tokens, lexiconToken{
+				lower:  lower,
+				folded: accents.Replace(lower),
+				caps:   letters >= 2 && word == strings.ToUpper(word) && word != lower,
+			})
+			continue
+		case r == '!':
+			exclamations++
+		case r == '.' || r == '?' || r == ';' || r == ',' || r == '\n':
+			endClause()
+		default:
+			if _, ok := emojiLexicon[string(r)]; ok {
+				tokens = append(tokens, lexiconToken{lower: string(r), folded: string(r), emoji: true})
+			}
+		}
+		i++
+	}
+	return tokens, exclamations
+}
+
+// emoticonAt retourne l'émoticône ASCII (":)", "<3"...) qui commence en i, ou ""
+func emoticonAt(runes []rune, i int) string {
+	if runes[i] != ':' && runes[i] != '<' {
+		return ""
+	}
+	for _, candidate := range []string{":-)", ":-(", ":)", ":(", ":D", "<3"} {
+		end := i + len(candidate)
+		if end <= len(runes) && string(runes[i:end]) == candidate {
+			return candidate
+		}
+	}
+	return ""
+}
@@ -0,0 +1,79 @@
+// internal/utils/lexicon_sentiment_test.go
+package utils
+
+import (
+	"testing"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func TestAnalyzeLexiconLabels(t *testing.T) {
+	cases := []struct {
+		text  string
+		label string
+	}{
+		{"Super vidéo, merci beaucoup !", models.SentimentPositive},
+		{"Vidéo nulle, je suis déçu.", models.SentimentNegative},
+		{"Je n'aime pas du tout cette vidéo", models.SentimentNegative},
+		{"J'aime pas ce format", models.SentimentNegative},
+		{"C'est pas mal du tout", models.SentimentPositive},
+		{"Great video, really helpful", models.SentimentPositive},
+		{"This isn't good, honestly boring", models.SentimentNegative},
+		{"Quelle heure est-il à Paris ?", models.SentimentNeutral},
+		{"😍😍", models.SentimentPositive},
+		{"😡 👎", models.SentimentNegative},
+		{"merci :)", models.SentimentPositive},
+		{"J'adore le montage. Le son est horrible.", models.SentimentMixed},
+		{"", models.SentimentNeutral},
+	}
+	for _, c := range cases {
+		if got := AnalyzeLexicon(c.text).Score; got.Label != c.label {
+			t.Errorf("%q: %s (%.3f), attendu %s", c.text, got.Label, got.Polarity, c.label)
+		}
+	}
+}
+
+func TestAnalyzeLexiconModifiers(t *testing.T) {
+	polarity := func(text string) float64 { return AnalyzeLexicon(text).Score.Polarity }
+
+	if plain, intense := polarity("c'est bien"), polarity("c'est vraiment bien"); intense <= plain {
+		t.Errorf("l'intensificateur doit renforcer: %v <= %v", intense, plain)
+	}
+	if plain, weak := polarity("c'est bien"), polarity("c'est assez bien"); weak >= plain {
+		t.Errorf("l'atténuateur doit affaiblir: %v >= %v", weak, plain)
+	}
+	if plain, loud := polarity("génial"), polarity("génial !!!"); loud <= plain {
+		t.Errorf("les points d'exclamation doivent renforcer: %v <= %v", loud, plain)
+	}
+	if plain, shouted := polarity("la vidéo est nulle"), polarity("la vidéo est NULLE"); shouted >= plain {
+		t.Errorf("les majuscules doivent renforcer: %v >= %v", shouted, plain)
+	}
+	// Après "mais", la fin de la phrase l'emporte
+	if got := polarity("un peu long mais passionnant"); got <= 0 {
+		t.Errorf("contraste: %v, attendu positif", got)
+	}
+	if got := polarity("passionnant mais beaucoup trop long et confus"); got >= 0 {
+		t.Errorf("contraste: %v, attendu négatif", got)
+	}
+	// La négation ne franchit pas la ponctuation
+	if got := polarity("Pas de chance. Vidéo géniale"); got <= 0 {
+		t.Errorf("portée de la négation: %v, attendu positif", got)
+	}
+	for _, text := range []string{"génial", "nul", "😍 😍 😍 😍 génial génial génial !!!!!!"} {
+		if got := polarity(text); got < -1 || got > 1 {
+			t.Errorf("%q: polarité hors bornes %v", text, got)
+		}
+	}
+}
+
+func TestAnalyzeLexiconEmotions(t *testing.T) {
+	result := AnalyzeLexicon("Je suis tellement déçu 😢, et franchement en colère 😡😡")
+	emotions := DominantEmotions(result.Emotions)
+	if len(emotions) != 2 || emotions[0] != EmotionAnger || emotions[1] != EmotionSadness {
+		t.Fatalf("émotions: %v (%v)", emotions, result.Emotions)
+	}
+	// Un mot nié n'exprime pas son émotion
+	if negated := AnalyzeLexicon("je ne suis pas content"); len(negated.Emotions) != 0 {
+		t.Fatalf("émotion d'un mot nié: %v", negated.Emotions)
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// SecretBox chiffre les secrets stockés en base (refresh tokens OAuth...) avec AES-256-GCM.
+// Le format produit est base64url(nonce || ciphertext).
+type SecretBox struct {
+	aead cipher.AEAD
+}
+
+// NewSecretBox attend une clé de 32 octets
+func NewSecretBox(key []byte) (*SecretBox, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("clé de chiffrement de %d octets (32 attendus)", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretBox{aead: aead}, nil
+}
+
+func (b *SecretBox) Seal(plaintext []byte) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("échec génération du nonce: %w", err)
+	}
+	sealed := b.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (b *SecretBox) Open(sealed string) ([]byte, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, fmt.Errorf("secret chiffré invalide: %w", err)
+	}
+	if len(raw) < b.aead.NonceSize() {
+		return nil, errors.New("secret chiffré trop court")
+	}
+	nonce, ciphertext := raw[:b.aead.NonceSize()], raw[b.aead.NonceSize():]
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("échec du déchiffrement (clé incorrecte ou donnée altérée)")
+	}
+	return plaintext, nil
+}
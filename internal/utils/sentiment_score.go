@@ -0,0 +1,99 @@
+// internal/utils/sentiment_score.go
+package utils
+
+import (
+	"math"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+// NeutralPolarityBand : en deçà de cette valeur absolue, une polarité sans étiquette est neutre
+const NeutralPolarityBand = 0.25
+
+// confidenceVolume : nombre de commentaires évalués pour lequel le facteur de volume vaut 0,5
+const confidenceVolume = 10.0
+
+// CommentScore est le sentiment d'un commentaire attribué par l'analyse
+type CommentScore struct {
+	Polarity float64 // de -1 à 1
+	Label    string  // models.SentimentPositive, SentimentNeutral, SentimentNegative ou SentimentMixed
+}
+
+// NewCommentScore borne la polarité à [-1, 1] et déduit l'étiquette si elle est vide
+func NewCommentScore(polarity float64, label string) CommentScore {
+	polarity = math.Max(-1, math.Min(1, polarity))
+	if label == "" {
+		label = LabelForPolarity(polarity)
+	}
+	return CommentScore{Polarity: polarity, Label: label}
+}
+
+// LabelForPolarity retourne l'étiquette correspondant à une polarité (jamais SentimentMixed :
+// un avis partagé ne se déduit pas d'un seul nombre)
+func LabelForPolarity(polarity float64) string {
+	switch {
+	case polarity >= NeutralPolarityBand:
+		return models.SentimentPositive
+	case polarity <= -NeutralPolarityBand:
+		return models.SentimentNegative
+	default:
+		return models.SentimentNeutral
+	}
+}
+
+// ComputeSentimentStats agrège la polarité des commentaires évalués (Polarity non nil).
+//   - Score : moyenne pondérée par 1 + ln(1 + likes), pour qu'un commentaire très aimé compte
+//     davantage sans écraser tous les autres ;
+//   - Confidence : part de commentaires évalués × volume (n / (n + 10)) × accord, l'accord valant
+//     1 quand toutes les polarités sont identiques et 0,5 quand elles sont dispersées au maximum.
+func ComputeSentimentStats(comments []models.Comment) models.SentimentStats {
+	var stats models.SentimentStats
+	var weightSum, weightedSum float64
+	for _, comment := range comments {
+		if comment.Polarity == nil {
+			continue
+		}
+		stats.Scored++
+		switch comment.SentimentLabel {
+		case models.SentimentPositive:
+			stats.Positive++
+		case models.SentimentNegative:
+			stats.Negative++
+		case models.SentimentMixed:
+			stats.Mixed++
+		default:
+			stats.Neutral++
+		}
+		weight := likeWeight(comment.LikeCount)
+		weightSum += weight
+		weightedSum += weight * *comment.Polarity
+	}
+	if stats.Scored == 0 {
+		return stats
+	}
+
+	score := weightedSum / weightSum
+	var variance float64
+	for _, comment := range comments {
+		if comment.Polarity != nil {
+			variance += likeWeight(comment.LikeCount) * math.Pow(*comment.Polarity-score, 2)
+		}
+	}
+	// Écart type d'une polarité dans [-1, 1] : au plus 1
+	spread := math.Min(1, math.Sqrt(variance/weightSum))
+	scored := float64(stats.Scored)
+	coverage := scored / float64(len(comments))
+	volume := scored / (scored + confidenceVolume)
+
+	stats.Score = round3(score)
+	stats.Confidence = round3(coverage * volume * (1 - spread/2))
+	return stats
+}
+
+func likeWeight(likes int64) float64 {
+	return 1 + math.Log1p(math.Max(0, float64(likes)))
+}
+
+func round3(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
@@ -0,0 +1,79 @@
+// internal/utils/sentiment_score_test.go
+package utils
+
+import (
+	"testing"
+
+	"github.com/Azertdev/FiberTest/internal/models"
+)
+
+func scoredComment(polarity float64, label string, likes int64) models.Comment {
+	return models.Comment{Polarity: &polarity, SentimentLabel: label, LikeCount: likes}
+}
+
+func TestComputeSentimentStatsWeightsByLikes(t *testing.T) {
+	comments := []models.Comment{
+		scoredComment(1, models.SentimentPositive, 0),
+		scoredComment(-1, models.SentimentNegative, 0),
+		{Content: "non évalué"},
+	}
+	balanced := ComputeSentimentStats(comments)
+	if balanced.Score != 0 || balanced.Scored != 2 || balanced.Positive != 1 || balanced.Negative != 1 {
+		t.Fatalf("statistiques inattendues: %+v", balanced)
+	}
+
+	// Le commentaire négatif très aimé fait pencher le score sans l'emporter totalement
+	comments[1].LikeCount = 100
+	weighted := ComputeSentimentStats(comments)
+	if weighted.Score >= 0 || weighted.Score <= -1 {
+		t.Fatalf("score pondéré: %v", weighted.Score)
+	}
+}
+
+func TestComputeSentimentStatsConfidence(t *testing.T) {
+	if stats := ComputeSentimentStats([]models.Comment{{}, {}}); stats.Scored != 0 || stats.Score != 0 || stats.Confidence != 0 {
+		t.Fatalf("aucun commentaire évalué: %+v", stats)
+	}
+
+	agreeing := make([]models.Comment, 30)
+	for i := range agreeing {
+		agreeing[i] = scoredComment(0.8, models.SentimentPositive, 0)
+	}
+	divided := make([]models.Comment, 30)
+	for i := range divided {
+		divided[i] = scoredComment(1, models.SentimentPositive, 0)
+		if i%2 == 0 {
+			divided[i] = scoredComment(-1, models.SentimentNegative, 0)
+		}
+	}
+	few := agreeing[:3]
+
+	high, low, small := ComputeSentimentStats(agreeing), ComputeSentimentStats(divided), ComputeSentimentStats(few)
+	if high.Confidence != 0.75 {
+		t.Fatalf("confiance avec accord total sur 30 commentaires: %v, attendu 0.75", high.Confidence)
+	}
+	if low.Confidence >= high.Confidence || small.Confidence >= high.Confidence {
+		t.Fatalf("la dispersion et le faible volume doivent réduire la confiance: %v, %v, %v", high.Confidence, low.Confidence, small.Confidence)
+	}
+	if mixed := ComputeSentimentStats([]models.Comment{scoredComment(0.1, models.SentimentMixed, 0)}); mixed.Mixed != 1 || mixed.Neutral != 0 {
+		t.Fatalf("étiquette mixte non comptée: %+v", mixed)
+	}
+}
+
+func TestNewCommentScoreClampsAndLabels(t *testing.T) {
+	cases := []struct {
+		polarity float64
+		label    string
+		want     CommentScore
+	}{
+		{2, "", CommentScore{1, models.SentimentPositive}},
+		{-0.3, "", CommentScore{-0.3, models.SentimentNegative}},
+		{0.2, "", CommentScore{0.2, models.SentimentNeutral}},
+		{0.2, models.SentimentMixed, CommentScore{0.2, models.SentimentMixed}},
+	}
+	for _, c := range cases {
+		if got := NewCommentScore(c.polarity, c.label); got != c.want {
+			t.Errorf("NewCommentScore(%v, %q) = %+v, attendu %+v", c.polarity, c.label, got, c.want)
+		}
+	}
+}